@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/links"
+	"github.com/godbus/dbus/v5"
+)
+
+// agendaEvent is an event as returned by the GetEvents D-Bus method. Its
+// fields are also what -format templates of "calbar agenda" refer to.
+type agendaEvent struct {
+	UID            string    `json:"uid"`
+	Summary        string    `json:"summary"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	AllDay         bool      `json:"all_day"`
+	Location       string    `json:"location,omitempty"`
+	Source         string    `json:"source"`
+	MeetingURL     string    `json:"meeting_url,omitempty"`
+	MeetingService string    `json:"meeting_service,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	Response       string    `json:"response,omitempty"`
+	Color          string    `json:"color,omitempty"`
+	Tags           []string  `json:"tags,omitempty"`
+	Stale          bool      `json:"stale,omitempty"`
+}
+
+func newAgendaEvent(e calendar.Event) agendaEvent {
+	link := meetingLink(e)
+	service := e.Meeting.Service
+	if service == "" && link != "" {
+		service = links.Service(link)
+	}
+	return agendaEvent{
+		UID:            e.UID,
+		Summary:        e.Summary,
+		Start:          e.Start,
+		End:            e.End,
+		AllDay:         e.AllDay,
+		Location:       e.Location,
+		Source:         e.Source,
+		MeetingURL:     link,
+		MeetingService: service,
+		Status:         string(e.Status),
+		Response:       string(e.Response),
+		Color:          e.Color,
+		Tags:           e.Tags,
+		Stale:          e.Stale,
+	}
+}
+
+// newAgendaEvents converts events for the GetEvents JSON format.
+func newAgendaEvents(events []calendar.Event) []agendaEvent {
+	out := make([]agendaEvent, 0, len(events))
+	for _, e := range events {
+		out = append(out, newAgendaEvent(e))
+	}
+	return out
+}
+
+// properties returns the event as a D-Bus dictionary (a{sv}) for the
+// control interface properties. Times are Unix seconds.
+func (e agendaEvent) properties() map[string]dbus.Variant {
+	tags := e.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return map[string]dbus.Variant{
+		"uid":             dbus.MakeVariant(e.UID),
+		"summary":         dbus.MakeVariant(e.Summary),
+		"start":           dbus.MakeVariant(e.Start.Unix()),
+		"end":             dbus.MakeVariant(e.End.Unix()),
+		"all_day":         dbus.MakeVariant(e.AllDay),
+		"location":        dbus.MakeVariant(e.Location),
+		"source":          dbus.MakeVariant(e.Source),
+		"meeting_url":     dbus.MakeVariant(e.MeetingURL),
+		"meeting_service": dbus.MakeVariant(e.MeetingService),
+		"status":          dbus.MakeVariant(e.Status),
+		"response":        dbus.MakeVariant(e.Response),
+		"color":           dbus.MakeVariant(e.Color),
+		"tags":            dbus.MakeVariant(tags),
+		"stale":           dbus.MakeVariant(e.Stale),
+	}
+}
+
+// agenda returns the visible events that overlap [from, to), sorted by start
+// time. Hidden events and events left out by the filters are not included.
+func (a *App) agenda(from, to time.Time) []agendaEvent {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	events := []agendaEvent{}
+	for _, e := range a.visibleEvents() {
+		if e.End.After(from) && e.Start.Before(to) {
+			events = append(events, newAgendaEvent(e))
+		}
+	}
+	return events
+}
+
+// runAgendaCommand implements "calbar agenda".
+func runAgendaCommand(cli cliOptions) error {
+	fs := flag.NewFlagSet("calbar agenda", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	days := fs.Int("days", 1, "")
+	asJSON := fs.Bool("json", false, "")
+	format := fs.String("format", "", "")
+	if err := fs.Parse(cli.commandArgs); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+	if fs.NArg() != 0 {
+		return usageErrorf("agenda takes no arguments")
+	}
+	if *days < 1 {
+		return usageErrorf("-days must be at least 1")
+	}
+	if *asJSON && *format != "" {
+		return usageErrorf("-json and -format are mutually exclusive")
+	}
+
+	var tmpl *template.Template
+	if *format != "" {
+		t, err := template.New("format").Parse(*format)
+		if err != nil {
+			return usageErrorf("-format: %v", err)
+		}
+		tmpl = t
+	}
+
+	now := time.Now()
+	y, m, d := now.Date()
+	to := time.Date(y, m, d+*days, 0, 0, 0, 0, now.Location())
+
+	events, err := sendGetEvents(now, to)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *asJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	case tmpl != nil:
+		for _, e := range events {
+			if err := tmpl.Execute(os.Stdout, e); err != nil {
+				return fmt.Errorf("-format: %w", err)
+			}
+			fmt.Println()
+		}
+		return nil
+	default:
+		printAgenda(os.Stdout, events, now)
+		return nil
+	}
+}
+
+// printAgenda prints events grouped by day, numbered so that event commands
+// can refer to them. Events that started before now are listed under today.
+func printAgenda(w io.Writer, events []agendaEvent, now time.Time) {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "No events")
+		return
+	}
+
+	var day string
+	for i, e := range events {
+		start := e.Start.Local()
+		if start.Before(now) {
+			start = now
+		}
+		if d := start.Format("Mon Jan 2"); d != day {
+			if day != "" {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, d)
+			day = d
+		}
+
+		when := "all day"
+		if !e.AllDay {
+			when = e.Start.Local().Format("15:04") + "–" + e.End.Local().Format("15:04")
+		}
+		line := fmt.Sprintf("%3d  %-11s  %s", i+1, when, e.Summary)
+		if e.MeetingURL != "" {
+			line += "  " + e.MeetingURL
+		} else if e.Location != "" {
+			line += "  (" + e.Location + ")"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// sendGetEvents asks the running instance for the visible events between
+// from and to.
+func sendGetEvents(from, to time.Time) ([]agendaEvent, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	var data string
+	obj := conn.Object(controlBusName, dbus.ObjectPath(controlPath))
+	if err := obj.Call(controlInterface+".GetEvents", 0, from.Unix(), to.Unix()).Store(&data); err != nil {
+		return nil, fmt.Errorf("get events: %w", err)
+	}
+
+	var events []agendaEvent
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		return nil, fmt.Errorf("decode events: %w", err)
+	}
+	return events, nil
+}
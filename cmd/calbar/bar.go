@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/godbus/dbus/v5"
+)
+
+// barTooltipEvents is how many upcoming events the status bar tooltip lists.
+const barTooltipEvents = 5
+
+// barStatus is what status bar modules show: the next event, a tooltip
+// listing the next few events, and the tray state.
+type barStatus struct {
+	Text       string `json:"text"`              // Next event and when it starts, or empty if there is none
+	Summary    string `json:"summary,omitempty"` // Title of the next event
+	Tooltip    string `json:"tooltip"`
+	Class      string `json:"class,omitempty"`     // "imminent", "ongoing", "stale" or "offline"
+	Percentage int    `json:"percentage"`          // How far through the ongoing event we are
+	Conflicts  int    `json:"conflicts,omitempty"` // Overlapping pairs of busy events within ui.time_range
+}
+
+// barStatus returns the current status bar state.
+func (a *App) barStatus(now time.Time) barStatus {
+	a.mu.RLock()
+	events := a.visibleEvents()
+	stale := a.isStale()
+	a.mu.RUnlock()
+
+	return a.statusFor(events, stale, now)
+}
+
+// statusFor computes the status bar state from the visible events, using the
+// same event selection as the tray tooltip and icon.
+func (a *App) statusFor(events []calendar.Event, stale bool, now time.Time) barStatus {
+	var s barStatus
+	upcoming := a.upcomingEvents(events, now)
+	if len(upcoming) == 0 {
+		s.Tooltip = "No upcoming events"
+	} else {
+		next := upcoming[0]
+		s.Summary = next.Summary
+		startsIn := next.Start.Sub(now)
+		switch {
+		case startsIn <= 0:
+			s.Text = next.Summary + " now"
+			if a.isBusy(next) {
+				s.Class = "ongoing"
+			}
+			if total := next.End.Sub(next.Start); total > 0 {
+				s.Percentage = min(100, int(100*now.Sub(next.Start)/total))
+			}
+		case startsIn < time.Hour:
+			s.Text = fmt.Sprintf("%s in %dm", next.Summary, int(math.Ceil(startsIn.Minutes())))
+		default:
+			s.Text = next.Summary + " at " + formatBarTime(next.Start, now)
+		}
+
+		var lines []string
+		for _, e := range upcoming[:min(len(upcoming), barTooltipEvents)] {
+			lines = append(lines, formatBarTime(e.Start, now)+"  "+e.Summary)
+		}
+		s.Tooltip = strings.Join(lines, "\n")
+	}
+
+	if conflicts := calendar.FindConflicts(events, now, now.Add(a.cfg.UI.TimeRange)); len(conflicts) > 0 {
+		s.Conflicts = len(conflicts)
+		s.Tooltip += "\n\n" + formatConflicts(conflicts, now)
+	}
+
+	if s.Class == "" && a.hasImminent(events, now) {
+		s.Class = "imminent"
+	}
+	if stale {
+		s.Class = "stale"
+		s.Tooltip += "\nEvents may be out of date"
+	}
+	return s
+}
+
+// formatBarTime formats an event start time, with the weekday if it is not
+// today.
+func formatBarTime(t, now time.Time) string {
+	t = t.Local()
+	if y, m, d := t.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return t.Format("3:04 PM")
+	}
+	return t.Format("Mon 3:04 PM")
+}
+
+// publishBarStatus emits the StatusChanged signal when the status bar state
+// has changed since it was last emitted.
+func (a *App) publishBarStatus() {
+	if a.control == nil {
+		return
+	}
+
+	a.barMu.Lock()
+	defer a.barMu.Unlock()
+
+	data, err := json.Marshal(a.barStatus(time.Now()))
+	if err != nil || string(data) == a.lastBarStatus {
+		return
+	}
+	a.lastBarStatus = string(data)
+	a.control.emit("StatusChanged", string(data))
+}
+
+// barModes are the output formats of "calbar bar".
+var barModes = []string{"waybar", "polybar", "i3blocks"}
+
+// runBarCommand implements "calbar bar".
+func runBarCommand(cli cliOptions) error {
+	fs := flag.NewFlagSet("calbar bar", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	follow := fs.Bool("follow", false, "")
+	mode := fs.String("mode", "waybar", "")
+	if err := fs.Parse(cli.commandArgs); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+	if fs.NArg() != 0 {
+		return usageErrorf("bar takes no arguments")
+	}
+	if !slices.Contains(barModes, *mode) {
+		return usageErrorf("unknown -mode %q (use %s)", *mode, strings.Join(barModes, ", "))
+	}
+
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	if !*follow {
+		status, err := getBarStatus(conn)
+		if err != nil {
+			return err
+		}
+		fmt.Println(formatBarStatus(*mode, status, true))
+		return nil
+	}
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(dbus.ObjectPath(controlPath)),
+		dbus.WithMatchInterface(controlInterface),
+		dbus.WithMatchMember("StatusChanged"),
+	); err != nil {
+		return fmt.Errorf("subscribe to status changes: %w", err)
+	}
+	signals := make(chan *dbus.Signal, 8)
+	conn.Signal(signals)
+
+	var last string
+	emit := func(status barStatus) {
+		if line := formatBarStatus(*mode, status, false); line != last {
+			fmt.Println(line)
+			last = line
+		}
+	}
+	refresh := func() {
+		status, err := getBarStatus(conn)
+		if err != nil {
+			// Keep following so the module recovers when CalBar starts
+			status = barStatus{Tooltip: "CalBar is not running", Class: "offline"}
+		}
+		emit(status)
+	}
+
+	refresh()
+	timer := time.NewTimer(untilNextMinute(time.Now()))
+	defer timer.Stop()
+	for {
+		select {
+		case sig, ok := <-signals:
+			if !ok {
+				return fmt.Errorf("session bus connection closed")
+			}
+			var status barStatus
+			if len(sig.Body) == 1 {
+				if data, ok := sig.Body[0].(string); ok && json.Unmarshal([]byte(data), &status) == nil {
+					emit(status)
+				}
+			}
+		case <-timer.C:
+			refresh()
+			timer.Reset(untilNextMinute(time.Now()))
+		}
+	}
+}
+
+// untilNextMinute returns the time until the start of the next minute.
+func untilNextMinute(now time.Time) time.Duration {
+	return now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+}
+
+// getBarStatus asks the running instance for the status bar state.
+func getBarStatus(conn *dbus.Conn) (barStatus, error) {
+	var data string
+	obj := conn.Object(controlBusName, dbus.ObjectPath(controlPath))
+	if err := obj.Call(controlInterface+".GetStatus", 0).Store(&data); err != nil {
+		return barStatus{}, fmt.Errorf("get status: %w", err)
+	}
+
+	var status barStatus
+	if err := json.Unmarshal([]byte(data), &status); err != nil {
+		return barStatus{}, fmt.Errorf("decode status: %w", err)
+	}
+	return status, nil
+}
+
+// pangoEscaper escapes text for Waybar, which renders it as Pango markup.
+var pangoEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// formatBarStatus formats a status for a bar. Waybar gets a JSON line;
+// Polybar and i3blocks get plain text. One-shot i3blocks output adds the
+// event title as the short text.
+func formatBarStatus(mode string, s barStatus, oneShot bool) string {
+	switch mode {
+	case "waybar":
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.Encode(struct {
+			Text       string `json:"text"`
+			Alt        string `json:"alt"`
+			Tooltip    string `json:"tooltip"`
+			Class      string `json:"class"`
+			Percentage int    `json:"percentage"`
+		}{pangoEscaper.Replace(s.Text), s.Class, pangoEscaper.Replace(s.Tooltip), s.Class, s.Percentage})
+		return strings.TrimSuffix(buf.String(), "\n")
+	case "i3blocks":
+		if oneShot {
+			return s.Text + "\n" + s.Summary
+		}
+		return s.Text
+	default:
+		return s.Text
+	}
+}
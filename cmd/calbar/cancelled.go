@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// cancelledPath returns the file that keeps when cancelled events were first
+// seen, next to the ICS output, so ui.cancelled_window survives restarts.
+func cancelledPath(output string) string {
+	if output == "" {
+		return ""
+	}
+	return strings.TrimSuffix(output, filepath.Ext(output)) + ".cancelled.json"
+}
+
+// loadCancelledSeen reads the cancelled events saved by saveCancelledSeen. It
+// returns nil if there are none.
+func loadCancelledSeen(path string) map[string]time.Time {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("failed to read cancelled events", "path", path, "error", err)
+		}
+		return nil
+	}
+	var seen map[string]time.Time
+	if err := json.Unmarshal(data, &seen); err != nil {
+		slog.Warn("failed to read cancelled events", "path", path, "error", err)
+		return nil
+	}
+	return seen
+}
+
+// saveCancelledSeen writes when each cancelled event was first seen
+// atomically.
+func saveCancelledSeen(path string, seen map[string]time.Time) error {
+	data, err := json.Marshal(seen)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
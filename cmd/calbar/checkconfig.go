@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/configcheck"
+)
+
+// checkConfigTimeout bounds how long "calbar check-config -probe" may take.
+const checkConfigTimeout = 2 * time.Minute
+
+// runCheckConfigCommand implements "calbar check-config".
+func runCheckConfigCommand(cli cliOptions) error {
+	fs := flag.NewFlagSet("calbar check-config", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	cmds := fs.Bool("cmds", false, "")
+	probe := fs.Bool("probe", false, "")
+	if err := fs.Parse(cli.commandArgs); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+	if fs.NArg() > 1 {
+		return usageErrorf("expected at most one config path")
+	}
+
+	path := cli.configPath
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+	if path == "" {
+		p, err := config.DefaultPath()
+		if err != nil {
+			return err
+		}
+		path = p
+	}
+	path, err := filepath.Abs(config.ResolvePath(path))
+	if err != nil {
+		return fmt.Errorf("resolve config path: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkConfigTimeout)
+	defer cancel()
+
+	problems := configcheck.Check(ctx, data, configcheck.Options{RunCommands: *cmds, Probe: *probe})
+	for _, p := range problems {
+		fmt.Fprintf(os.Stdout, "%s:%s\n", path, p)
+	}
+	switch len(problems) {
+	case 0:
+		fmt.Fprintf(os.Stdout, "%s: OK\n", path)
+		return nil
+	case 1:
+		return fmt.Errorf("1 problem found")
+	default:
+		return fmt.Errorf("%d problems found", len(problems))
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/cpuguy83/calbar/internal/config"
+)
+
+// localCommand is a subcommand that runs in the calling process instead of
+// being forwarded to the running instance.
+type localCommand struct {
+	usage       string // Argument synopsis shown after "calbar <command>"
+	description string
+	options     string // Command-specific option help, in flag.PrintDefaults style
+	run         func(cli cliOptions) error
+}
+
+var localCommands = map[string]localCommand{
+	"add": {
+		usage:       "<text>",
+		description: "Quick-add an event, e.g. \"Focus 2pm-4pm tomorrow\"",
+		options:     "  -dry-run\n        parse the text and print the event without creating it\n",
+		run:         runAddCommand,
+	},
+	"agenda": {
+		description: "Print upcoming events from the running instance",
+		options: "  -days int\n        number of days to show, starting today (default 1)\n" +
+			"  -format string\n        print each event with a Go template, e.g. '{{.Start.Format \"15:04\"}} {{.Summary}}'\n" +
+			"  -json\n        print the events as JSON\n",
+		run: runAgendaCommand,
+	},
+	"bar": {
+		description: "Print the next event for a Waybar, Polybar or i3blocks module",
+		options: "  -follow\n        keep running and print a new line whenever the status changes\n" +
+			"  -mode string\n        output for waybar (JSON), polybar or i3blocks (default \"waybar\")\n",
+		run: runBarCommand,
+	},
+	"check-config": {
+		usage:       "[path]",
+		description: "Check the config file for mistakes and report where they are",
+		options: "  -cmds\n        also run config_cmd and the *_cmd fields of each source\n" +
+			"  -probe\n        also fetch each source to check it can be reached (implies -cmds)\n",
+		run: runCheckConfigCommand,
+	},
+	"details": {
+		usage:       "<uid|number>",
+		description: "Print the details of an event in the running instance",
+		run:         runDetailsCommand,
+	},
+	"filter": {
+		usage:       "test",
+		description: "Show which events the filters keep and which rule decided each",
+		options: "  -rule string\n        also report whether each event matches this filter expression\n" +
+			"  -snapshot\n        read the last synced ICS output instead of fetching the sources\n",
+		run: runFilterCommand,
+	},
+	"hide": {
+		usage:       "[uid|number]",
+		description: "Hide the configured CalBar UI, or the given event",
+		run:         runHideCommand,
+	},
+	"join": {
+		usage:       "[uid|number]",
+		description: "Join the current or next meeting, or the meeting of the given event",
+		options: "  -current\n        join the meeting in progress\n" +
+			"  -next\n        join the next meeting that has not started\n",
+		run: runJoinCommand,
+	},
+	"open": {
+		usage:       "<uid|number>",
+		description: "Open the web link of an event in the running instance",
+		run:         runOpenCommand,
+	},
+	"secret": {
+		usage:       "set <source>",
+		description: "Store a source password in the Secret Service (read from stdin)",
+		run:         runSecretCommand,
+	},
+	"snooze": {
+		usage:       "<uid|number>",
+		description: "Remind about an event again after a while",
+		options:     "  -for duration\n        how long until the reminder (default 5m0s)\n",
+		run:         runSnoozeCommand,
+	},
+	"unhide": {
+		usage:       "<uid>",
+		description: "Show an event hidden in the running instance again",
+		run:         runUnhideCommand,
+	},
+}
+
+// localCommandNames lists the local commands in help order. "hide" is listed
+// with the control commands, which it extends.
+var localCommandNames = []string{"add", "agenda", "bar", "check-config", "details", "filter", "join", "open", "secret", "snooze", "unhide"}
+
+// usageError reports invalid command-line usage of a local command.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+
+func usageErrorf(format string, args ...any) error {
+	return usageError{fmt.Errorf(format, args...)}
+}
+
+func isCommand(name string) bool {
+	if _, ok := controlCommandMethods[name]; ok {
+		return true
+	}
+	_, ok := localCommands[name]
+	return ok
+}
+
+// runLocalCommand runs a local command and returns the process exit code.
+func runLocalCommand(cli cliOptions) int {
+	cmd := localCommands[cli.command]
+
+	err := cmd.run(cli)
+	var uerr usageError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errHelp):
+		printCommandUsage(os.Stdout, cli.command)
+		return 0
+	case errors.As(err, &uerr):
+		fmt.Fprintf(os.Stderr, "calbar %s: %v\n\n", cli.command, err)
+		printCommandUsage(os.Stderr, cli.command)
+		return 2
+	default:
+		fmt.Fprintf(os.Stderr, "calbar %s: %v\n", cli.command, err)
+		return 1
+	}
+}
+
+// errHelp is returned by local commands when help was requested.
+var errHelp = errors.New("help requested")
+
+// loadCLIConfig loads the configuration for a local command.
+func loadCLIConfig(configPath string) (*config.Config, string, error) {
+	if configPath == "" {
+		path, err := config.DefaultPath()
+		if err != nil {
+			return nil, "", err
+		}
+		configPath = path
+	}
+	path, err := filepath.Abs(config.ResolvePath(configPath))
+	if err != nil {
+		return nil, "", fmt.Errorf("resolve config path: %w", err)
+	}
+	cfg, err := config.LoadFrom(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return cfg, path, nil
+}
+
+func printLocalCommandUsage(w io.Writer, command string, cmd localCommand) {
+	synopsis := "calbar " + command + " [options]"
+	if cmd.usage != "" {
+		synopsis += " " + cmd.usage
+	}
+	fmt.Fprintf(w, "Usage:\n  %s\n\n", synopsis)
+	fmt.Fprintln(w, cmd.description+".")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Options:")
+	fmt.Fprint(w, cmd.options)
+	fmt.Fprintln(w, "  -h, -help")
+	fmt.Fprintln(w, "        show help")
+}
+
+// findSource returns the configured source with the given name.
+func findSource(cfg *config.Config, name string) (config.SourceConfig, bool) {
+	i := slices.IndexFunc(cfg.Sources, func(s config.SourceConfig) bool { return s.Name == name })
+	if i < 0 {
+		return config.SourceConfig{}, false
+	}
+	return cfg.Sources[i], true
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/notify"
+)
+
+// trackConflicts records the conflicts among the visible events and returns
+// those added since the last sync that start within notifications.conflicts.
+// The first sync only records them, so the conflicts CalBar starts up with
+// are not announced.
+// Must be called with Lock held.
+func (a *App) trackConflicts(now time.Time) []calendar.Conflict {
+	within := a.cfg.Notifications.Conflicts
+	if within <= 0 {
+		return nil
+	}
+
+	first := a.conflictsSeen == nil
+	seen := make(map[string]bool)
+	var added []calendar.Conflict
+	for _, c := range calendar.FindConflicts(a.visibleEvents(), now, now.Add(a.cfg.Sync.TimeRange)) {
+		seen[c.Key()] = true
+		if !first && !a.conflictsSeen[c.Key()] && c.B.Start.Before(now.Add(within)) {
+			added = append(added, c)
+		}
+	}
+	a.conflictsSeen = seen
+	return added
+}
+
+// notifyConflicts sends a notification for each new conflict.
+func (a *App) notifyConflicts(conflicts []calendar.Conflict, now time.Time) {
+	if a.notifier == nil || !a.cfg.Notifications.Enabled {
+		return
+	}
+	for _, c := range conflicts {
+		notif := conflictNotification(c, now)
+		id, err := a.notifier.Send(notif)
+		if err != nil {
+			slog.Warn("failed to send notification", "error", err)
+			continue
+		}
+		if id != 0 {
+			a.mu.Lock()
+			a.notificationIDs[id] = notificationTarget{uid: c.B.UID}
+			a.mu.Unlock()
+		}
+	}
+}
+
+// conflictNotification builds the notification for a new conflict. Details
+// opens the event that starts later.
+func conflictNotification(c calendar.Conflict, now time.Time) notify.Notification {
+	return notify.Notification{
+		Summary: "Double-booked",
+		Body: fmt.Sprintf("%s: %s overlaps %s",
+			formatBarTime(c.B.Start, now), c.B.Summary, c.A.Summary),
+		Actions: []notify.Action{{Key: "details", Label: "Open details"}},
+		Urgency: notify.UrgencyNormal,
+	}
+}
+
+// formatConflicts describes the conflicts for the status bar tooltip.
+func formatConflicts(conflicts []calendar.Conflict, now time.Time) string {
+	text := "1 conflict"
+	if len(conflicts) != 1 {
+		text = fmt.Sprintf("%d conflicts", len(conflicts))
+	}
+	for _, c := range conflicts[:min(len(conflicts), barTooltipEvents)] {
+		text += fmt.Sprintf("\n%s  %s / %s", formatBarTime(c.B.Start, now), c.A.Summary, c.B.Summary)
+	}
+	return text
+}
@@ -1,10 +1,19 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/cpuguy83/calbar/internal/calendar"
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
 )
 
 const (
@@ -42,13 +51,132 @@ func (s *controlService) Sync() *dbus.Error {
 	return nil
 }
 
+// AddEvent quick-adds an event from natural-language text and returns a
+// description of the created event.
+func (s *controlService) AddEvent(text string) (string, *dbus.Error) {
+	event, err := s.app.quickAdd(s.app.ctx, text)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return describeEvent(event), nil
+}
+
+// GetEvents returns the visible events that overlap the range from, to (Unix
+// seconds) as a JSON array.
+func (s *controlService) GetEvents(from, to int64) (string, *dbus.Error) {
+	data, err := json.Marshal(s.app.agenda(time.Unix(from, 0), time.Unix(to, 0)))
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return string(data), nil
+}
+
+// GetStatus returns the status bar state as JSON.
+func (s *controlService) GetStatus() (string, *dbus.Error) {
+	data, err := json.Marshal(s.app.barStatus(time.Now()))
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return string(data), nil
+}
+
+// Join opens the meeting link of the "current" or "next" meeting, or with ""
+// the one about to start or in progress, and returns a description of it.
+func (s *controlService) Join(which string) (string, *dbus.Error) {
+	event, err := s.app.joinTarget(which, time.Now())
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	desc, err := s.app.joinEvent(event)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return desc, nil
+}
+
+// JoinEvent opens the meeting link of an event, given by UID or agenda
+// number, and returns a description of it.
+func (s *controlService) JoinEvent(uid string) (string, *dbus.Error) {
+	event, err := s.app.eventByRef(uid, time.Now())
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	desc, err := s.app.joinEvent(event)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return desc, nil
+}
+
+// OpenEvent opens the web link of an event, given by UID or agenda number,
+// and returns it.
+func (s *controlService) OpenEvent(uid string) (string, *dbus.Error) {
+	event, err := s.app.eventByRef(uid, time.Now())
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	url, err := s.app.openEvent(event)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return url, nil
+}
+
+// HideEvent hides an event, given by UID or agenda number.
+func (s *controlService) HideEvent(uid string) *dbus.Error {
+	event, err := s.app.eventByRef(uid, time.Now())
+	if err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	s.app.hideEvent(event.UID)
+	return nil
+}
+
+// SnoozeEvent reminds about an event, given by UID or agenda number, again
+// after the given number of seconds, and returns a description of it.
+func (s *controlService) SnoozeEvent(uid string, seconds int64) (string, *dbus.Error) {
+	now := time.Now()
+	event, err := s.app.eventByRef(uid, now)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	desc, err := s.app.snoozeEvent(event, time.Duration(seconds)*time.Second, now)
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return desc, nil
+}
+
+func (s *controlService) UnhideEvent(uid string) *dbus.Error {
+	if !s.app.isHidden(uid) {
+		return dbus.MakeFailedError(fmt.Errorf("event %q is not hidden", uid))
+	}
+	s.app.unhideEvent(uid)
+	return nil
+}
+
+// GetEventDetails returns the details of an event, given by UID or agenda
+// number, as plain text.
+func (s *controlService) GetEventDetails(uid string) (string, *dbus.Error) {
+	event, err := s.app.eventByRef(uid, time.Now())
+	if err != nil {
+		return "", dbus.MakeFailedError(err)
+	}
+	return formatEventDetails(event, s.app.policy.Before(event)), nil
+}
+
 func (s *controlService) Quit() *dbus.Error {
 	s.app.Quit()
 	return nil
 }
 
 type controlServer struct {
-	conn *dbus.Conn
+	conn  *dbus.Conn
+	props *prop.Properties
+
+	// publishControlState runs on several goroutines with the menu backend
+	mu         sync.Mutex
+	lastEvents string // Visible events last announced with EventsChanged, as JSON; guarded by mu
 }
 
 func startControlServer(app *App) (*controlServer, error) {
@@ -73,11 +201,20 @@ func startControlServer(app *App) (*controlServer, error) {
 		return nil, fmt.Errorf("export control interface: %w", err)
 	}
 
+	props, err := prop.Export(conn, dbus.ObjectPath(controlPath), prop.Map{controlInterface: newControlProperties()})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("export control properties: %w", err)
+	}
+	properties := props.Introspection(controlInterface)
+	slices.SortFunc(properties, func(a, b introspect.Property) int { return strings.Compare(a.Name, b.Name) })
+
 	node := &introspect.Node{
 		Name: controlPath,
 		Interfaces: []introspect.Interface{
 			introspect.IntrospectData,
-			{Name: controlInterface, Methods: controlMethods},
+			prop.IntrospectData,
+			{Name: controlInterface, Methods: controlMethods, Signals: controlSignals, Properties: properties},
 		},
 	}
 	if err := conn.Export(introspect.NewIntrospectable(node), dbus.ObjectPath(controlPath), "org.freedesktop.DBus.Introspectable"); err != nil {
@@ -85,7 +222,95 @@ func startControlServer(app *App) (*controlServer, error) {
 		return nil, fmt.Errorf("export control introspection: %w", err)
 	}
 
-	return &controlServer{conn: conn}, nil
+	return &controlServer{conn: conn, props: props}, nil
+}
+
+// newControlProperties returns the read-only properties of the control
+// interface with their initial values.
+func newControlProperties() map[string]*prop.Prop {
+	readOnly := func(v any) *prop.Prop { return &prop.Prop{Value: v, Emit: prop.EmitTrue} }
+	return map[string]*prop.Prop{
+		"NextEvent":     readOnly(map[string]dbus.Variant{}),
+		"OngoingEvents": readOnly([]map[string]dbus.Variant{}),
+		"Stale":         readOnly(false),
+		"LastSync":      readOnly(int64(0)),
+		"SyncErrors":    readOnly([]string{}),
+	}
+}
+
+// controlProperties computes the values of the control interface properties.
+// NextEvent is empty when no event is coming up, and LastSync is 0 before
+// the first sync.
+func (a *App) controlProperties(events []calendar.Event, stale bool, syncErrors []string, lastSync, now time.Time) map[string]any {
+	next := map[string]dbus.Variant{}
+	ongoing := []map[string]dbus.Variant{}
+	for _, e := range a.upcomingEvents(events, now) {
+		if e.Start.After(now) {
+			next = newAgendaEvent(e).properties()
+			break
+		}
+		if e.End.After(now) {
+			ongoing = append(ongoing, newAgendaEvent(e).properties())
+		}
+	}
+
+	var last int64
+	if !lastSync.IsZero() {
+		last = lastSync.Unix()
+	}
+	if syncErrors == nil {
+		syncErrors = []string{}
+	}
+
+	return map[string]any{
+		"NextEvent":     next,
+		"OngoingEvents": ongoing,
+		"Stale":         stale,
+		"LastSync":      last,
+		"SyncErrors":    syncErrors,
+	}
+}
+
+// publishControlState updates the control interface properties, emitting
+// PropertiesChanged for the ones that changed, and emits EventsChanged when
+// the visible events changed.
+func (a *App) publishControlState(events []calendar.Event, stale bool, syncErrors []string, lastSync time.Time) {
+	if a.control == nil {
+		return
+	}
+
+	a.control.mu.Lock()
+	defer a.control.mu.Unlock()
+
+	for name, v := range a.controlProperties(events, stale, syncErrors, lastSync, time.Now()) {
+		a.control.setProperty(name, v)
+	}
+
+	agenda := make([]agendaEvent, 0, len(events))
+	for _, e := range events {
+		agenda = append(agenda, newAgendaEvent(e))
+	}
+	data, err := json.Marshal(agenda)
+	if err != nil || string(data) == a.control.lastEvents {
+		return
+	}
+	a.control.lastEvents = string(data)
+	a.control.emit("EventsChanged")
+}
+
+// setProperty sets a control interface property if its value changed.
+func (s *controlServer) setProperty(name string, v any) {
+	if reflect.DeepEqual(s.props.GetMust(controlInterface, name), v) {
+		return
+	}
+	s.props.SetMust(controlInterface, name, v)
+}
+
+// emit emits a signal on the control interface.
+func (s *controlServer) emit(name string, values ...any) {
+	if err := s.conn.Emit(dbus.ObjectPath(controlPath), controlInterface+"."+name, values...); err != nil {
+		slog.Debug("failed to emit control signal", "signal", name, "error", err)
+	}
 }
 
 func (s *controlServer) Close() {
@@ -116,6 +341,26 @@ func sendControlCommand(command string) error {
 	return nil
 }
 
+// callControl calls a method of the running instance, storing its result,
+// if any, in out.
+func callControl(method string, out any, args ...any) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	obj := conn.Object(controlBusName, dbus.ObjectPath(controlPath))
+	call := obj.Call(controlInterface+"."+method, 0, args...)
+	if call.Err != nil {
+		return call.Err
+	}
+	if out != nil {
+		return call.Store(out)
+	}
+	return nil
+}
+
 var controlCommandMethods = map[string]string{
 	"show":   "Show",
 	"hide":   "Hide",
@@ -136,7 +381,7 @@ var controlCommandNames = []string{
 
 var controlCommandDescriptions = map[string]string{
 	"show":   "Show the configured CalBar UI",
-	"hide":   "Hide the configured CalBar UI",
+	"hide":   "Hide the configured CalBar UI, or the given event",
 	"toggle": "Toggle the configured CalBar UI",
 	"search": "Show the configured CalBar UI and focus search when supported",
 	"sync":   "Trigger a calendar sync",
@@ -150,4 +395,83 @@ var controlMethods = []introspect.Method{
 	{Name: "Search"},
 	{Name: "Sync"},
 	{Name: "Quit"},
+	{
+		Name: "AddEvent",
+		Args: []introspect.Arg{
+			{Name: "text", Type: "s", Direction: "in"},
+			{Name: "description", Type: "s", Direction: "out"},
+		},
+	},
+	{
+		Name: "GetEvents",
+		Args: []introspect.Arg{
+			{Name: "from", Type: "x", Direction: "in"},
+			{Name: "to", Type: "x", Direction: "in"},
+			{Name: "events", Type: "s", Direction: "out"},
+		},
+	},
+	{
+		Name: "Join",
+		Args: []introspect.Arg{
+			{Name: "which", Type: "s", Direction: "in"},
+			{Name: "description", Type: "s", Direction: "out"},
+		},
+	},
+	{
+		Name: "JoinEvent",
+		Args: []introspect.Arg{
+			{Name: "uid", Type: "s", Direction: "in"},
+			{Name: "description", Type: "s", Direction: "out"},
+		},
+	},
+	{
+		Name: "OpenEvent",
+		Args: []introspect.Arg{
+			{Name: "uid", Type: "s", Direction: "in"},
+			{Name: "url", Type: "s", Direction: "out"},
+		},
+	},
+	{
+		Name: "HideEvent",
+		Args: []introspect.Arg{
+			{Name: "uid", Type: "s", Direction: "in"},
+		},
+	},
+	{
+		Name: "SnoozeEvent",
+		Args: []introspect.Arg{
+			{Name: "uid", Type: "s", Direction: "in"},
+			{Name: "seconds", Type: "x", Direction: "in"},
+			{Name: "description", Type: "s", Direction: "out"},
+		},
+	},
+	{
+		Name: "UnhideEvent",
+		Args: []introspect.Arg{
+			{Name: "uid", Type: "s", Direction: "in"},
+		},
+	},
+	{
+		Name: "GetEventDetails",
+		Args: []introspect.Arg{
+			{Name: "uid", Type: "s", Direction: "in"},
+			{Name: "details", Type: "s", Direction: "out"},
+		},
+	},
+	{
+		Name: "GetStatus",
+		Args: []introspect.Arg{
+			{Name: "status", Type: "s", Direction: "out"},
+		},
+	},
+}
+
+var controlSignals = []introspect.Signal{
+	{Name: "EventsChanged"},
+	{
+		Name: "StatusChanged",
+		Args: []introspect.Arg{
+			{Name: "status", Type: "s"},
+		},
+	},
 }
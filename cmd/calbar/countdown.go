@@ -0,0 +1,141 @@
+package main
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/notify"
+)
+
+// countdown is the notification kept up for an event in countdown mode,
+// updated in place as the event approaches.
+type countdown struct {
+	id      uint32
+	body    string
+	closeAt time.Time // Once the event started, when to close the notification
+}
+
+// sendCountdown shows notif as the event's countdown notification, replacing
+// the open one if any. A non-zero closeAt marks the event as started.
+func (a *App) sendCountdown(event calendar.Event, notif notify.Notification, closeAt time.Time) {
+	a.mu.RLock()
+	c, open := a.countdowns[event.UID]
+	a.mu.RUnlock()
+
+	notif.ReplacesID = c.id
+	notif.Timeout = -1 // Closed by updateCountdowns
+	id, err := a.notifier.Send(notif)
+	if err != nil {
+		slog.Warn("failed to send notification", "error", err)
+		return
+	}
+	if id == 0 {
+		return
+	}
+
+	a.mu.Lock()
+	if open && c.id != id {
+		// The notification server showed a new notification instead
+		delete(a.notificationIDs, c.id)
+	}
+	a.notificationIDs[id] = notificationTarget{uid: event.UID, meetingLink: meetingLink(event)}
+	a.countdowns[event.UID] = countdown{id: id, body: notif.Body, closeAt: closeAt}
+	a.mu.Unlock()
+
+	// Switch to "Started" on time rather than on the next check
+	if !open && closeAt.IsZero() {
+		time.AfterFunc(time.Until(event.Start), a.wakeNotifications)
+	}
+}
+
+// updateCountdowns updates the open countdown notifications: the remaining
+// time before the event starts, "Started" with the Join action once it has,
+// and closing them countdown_timeout later, or when the event is gone, no
+// longer busy (cancelled, say) or its reminders were dismissed.
+func (a *App) updateCountdowns(events []calendar.Event, now time.Time) {
+	a.mu.RLock()
+	countdowns := maps.Clone(a.countdowns)
+	a.mu.RUnlock()
+
+	for uid, c := range countdowns {
+		i := slices.IndexFunc(events, func(e calendar.Event) bool { return e.UID == uid })
+		if i < 0 || !a.isBusy(events[i]) || a.remindersDismissed(uid) {
+			a.closeCountdown(uid)
+			continue
+		}
+		e := events[i]
+
+		switch {
+		case !c.closeAt.IsZero():
+			if !now.Before(c.closeAt) {
+				a.closeCountdown(uid)
+			}
+		case !now.Before(e.Start):
+			a.sendCountdown(e, a.startedNotification(e), now.Add(a.cfg.Notifications.CountdownTimeout))
+		default:
+			if notif := a.reminderNotification(e, e.Start.Sub(now)); notif.Body != c.body {
+				a.sendCountdown(e, notif, time.Time{})
+			}
+		}
+	}
+}
+
+// startedNotification builds the countdown notification for an event that
+// has started.
+func (a *App) startedNotification(event calendar.Event) notify.Notification {
+	notif := notify.Notification{
+		Summary:   event.Summary,
+		Body:      "Started",
+		Location:  event.Location,
+		Organizer: event.Organizer,
+		Link:      meetingLink(event),
+		EventUID:  event.UID,
+		Urgency:   a.policy.Urgency(event, 0),
+	}
+	if a.dndOn.Load() {
+		notif.Urgency = notify.UrgencyCritical
+	}
+	if meetingLink(event) != "" {
+		notif.Actions = append(notif.Actions, notify.Action{Key: "join", Label: "Join Meeting"})
+	}
+	notif.Actions = append(notif.Actions, notify.Action{Key: "details", Label: "Open details"})
+	return notif
+}
+
+// closeCountdown closes the event's countdown notification, if one is open.
+func (a *App) closeCountdown(uid string) {
+	a.mu.Lock()
+	c, ok := a.countdowns[uid]
+	delete(a.countdowns, uid)
+	a.mu.Unlock()
+
+	if ok && a.notifier != nil {
+		if err := a.notifier.CloseNotification(c.id); err != nil {
+			slog.Debug("failed to close countdown notification", "uid", uid, "error", err)
+		}
+	}
+}
+
+// notificationClosed forgets the countdown of a notification the user or the
+// notification server closed, so the next update does not bring it back.
+func (a *App) notificationClosed(id uint32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for uid, c := range a.countdowns {
+		if c.id == id {
+			delete(a.countdowns, uid)
+		}
+	}
+}
+
+// wakeNotifications makes the notification loop check notifications now.
+func (a *App) wakeNotifications() {
+	select {
+	case a.notifyWake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/dnd"
+	"github.com/cpuguy83/calbar/internal/filter"
+	"github.com/cpuguy83/calbar/internal/notify"
+)
+
+// dndCheckInterval is the longest the do-not-disturb loop sleeps, so events
+// added by a sync are picked up.
+const dndCheckInterval = 30 * time.Second
+
+// dndTimeout bounds each call to the notification daemon's control program.
+const dndTimeout = 5 * time.Second
+
+// setupDND picks the do-not-disturb backend and compiles its match rule.
+func (a *App) setupDND() error {
+	cfg := a.cfg.DND
+	if cfg.Match != nil {
+		m, err := filter.Compile(*cfg.Match)
+		if err != nil {
+			return fmt.Errorf("match: %w", err)
+		}
+		a.dndMatch = m
+	}
+
+	name := cfg.Backend
+	if name == "auto" {
+		server, err := notificationServerName(a.notifier)
+		if err != nil {
+			slog.Debug("failed to get notification server", "error", err)
+		}
+		name, err = dnd.Detect(server)
+		if err != nil {
+			return err
+		}
+	}
+	b, err := dnd.New(name, cfg.MakoMode)
+	if err != nil {
+		return err
+	}
+	slog.Info("do-not-disturb during events", "backend", b.Name())
+	a.dnd = b
+	return nil
+}
+
+// notificationServerName returns the name of the notification server, using
+// n if notifications are enabled.
+func notificationServerName(n *notify.Notifier) (string, error) {
+	if n == nil {
+		var err error
+		if n, err = notify.New("CalBar"); err != nil {
+			return "", err
+		}
+		defer n.Close()
+	}
+	return n.ServerName()
+}
+
+// dndLoop turns do-not-disturb on when a matching event starts and restores
+// the previous state when none is in progress. It only acts when that
+// changes, so turning do-not-disturb off by hand during a meeting sticks.
+func (a *App) dndLoop() {
+	defer close(a.dndDone)
+
+	var wanted bool
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			now := time.Now()
+			a.mu.RLock()
+			events := a.visibleEvents()
+			a.mu.RUnlock()
+
+			if want := a.wantDND(events, now); want != wanted {
+				wanted = want
+				if want {
+					a.enableDND()
+				} else {
+					a.restoreDND()
+				}
+			}
+			timer.Reset(a.untilNextDNDChange(events, now))
+		case <-a.ctx.Done():
+			a.restoreDND()
+			return
+		}
+	}
+}
+
+// dndEvent reports whether an event turns on do-not-disturb: a timed event
+// that keeps you busy and matches the configured rule.
+func (a *App) dndEvent(e calendar.Event) bool {
+	return !e.AllDay && a.isBusy(e) && (a.dndMatch == nil || a.dndMatch.Matches(e))
+}
+
+// wantDND reports whether an event that turns on do-not-disturb is in
+// progress.
+func (a *App) wantDND(events []calendar.Event, now time.Time) bool {
+	for _, e := range events {
+		if !e.Start.After(now) && e.End.After(now) && a.dndEvent(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// untilNextDNDChange returns how long the loop can sleep before the next
+// matching event starts or ends, at most dndCheckInterval.
+func (a *App) untilNextDNDChange(events []calendar.Event, now time.Time) time.Duration {
+	next := dndCheckInterval
+	for _, e := range events {
+		if !a.dndEvent(e) {
+			continue
+		}
+		for _, t := range []time.Time{e.Start, e.End} {
+			if d := t.Sub(now); d > 0 && d < next {
+				next = d
+			}
+		}
+	}
+	return next
+}
+
+func (a *App) enableDND() {
+	ctx, cancel := context.WithTimeout(a.ctx, dndTimeout)
+	defer cancel()
+
+	restore, err := a.dnd.Enable(ctx)
+	if err != nil {
+		slog.Warn("failed to turn on do-not-disturb", "backend", a.dnd.Name(), "error", err)
+		return
+	}
+	slog.Debug("turned on do-not-disturb", "backend", a.dnd.Name())
+	a.dndRestore = restore
+	a.dndOn.Store(true)
+}
+
+// restoreDND restores the do-not-disturb state from before enableDND.
+func (a *App) restoreDND() {
+	if a.dndRestore == nil {
+		return
+	}
+	// Not a.ctx, which is cancelled when quitting
+	ctx, cancel := context.WithTimeout(context.Background(), dndTimeout)
+	defer cancel()
+
+	if err := a.dndRestore(ctx); err != nil {
+		slog.Warn("failed to restore do-not-disturb", "backend", a.dnd.Name(), "error", err)
+	} else {
+		slog.Debug("restored do-not-disturb", "backend", a.dnd.Name())
+	}
+	a.dndRestore = nil
+	a.dndOn.Store(false)
+}
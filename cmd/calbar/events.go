@@ -0,0 +1,361 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/links"
+	"github.com/cpuguy83/calbar/internal/ui"
+)
+
+// joinEarly is how long before its start an upcoming meeting takes
+// precedence over one in progress for "calbar join".
+const joinEarly = 5 * time.Minute
+
+// meetingLink returns the link to join an event's online meeting, or "".
+func meetingLink(e calendar.Event) string {
+	if e.Meeting.URL != "" {
+		return e.Meeting.URL
+	}
+	return links.DetectFromEvent(e.Location, e.Description, e.URL)
+}
+
+// eventByUID returns the event with the given UID, hidden or not.
+func (a *App) eventByUID(uid string) (calendar.Event, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	i := slices.IndexFunc(a.events, func(e calendar.Event) bool { return e.UID == uid })
+	if i < 0 {
+		return calendar.Event{}, fmt.Errorf("no event with UID %q", uid)
+	}
+	return a.events[i], nil
+}
+
+// eventByRef returns the event with the given UID or, for a number n that is
+// not a UID, the nth event "calbar agenda" lists at now.
+func (a *App) eventByRef(ref string, now time.Time) (calendar.Event, error) {
+	if e, err := a.eventByUID(ref); err == nil {
+		return e, nil
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil {
+		return calendar.Event{}, fmt.Errorf("no event with UID %q", ref)
+	}
+
+	a.mu.RLock()
+	events := a.visibleEvents()
+	a.mu.RUnlock()
+
+	i := 0
+	for _, e := range events {
+		if !e.End.After(now) {
+			continue
+		}
+		if i++; i == n {
+			return e, nil
+		}
+	}
+	return calendar.Event{}, fmt.Errorf("no event %d in the agenda", n)
+}
+
+// joinTarget picks the meeting to join: "current" is the meeting in
+// progress that started last, "next" the first one that has not started,
+// and "" the next one if it starts within joinEarly, else the current one,
+// else the next one.
+func (a *App) joinTarget(which string, now time.Time) (calendar.Event, error) {
+	a.mu.RLock()
+	events := a.visibleEvents()
+	a.mu.RUnlock()
+
+	var current, next *calendar.Event
+	for _, e := range a.upcomingEvents(events, now) {
+		if meetingLink(e) == "" {
+			continue
+		}
+		if e.Start.After(now) {
+			if next == nil {
+				next = &e
+			}
+			continue
+		}
+		if e.End.After(now) {
+			current = &e
+		}
+	}
+
+	switch which {
+	case "current":
+		if current == nil {
+			return calendar.Event{}, fmt.Errorf("no meeting in progress")
+		}
+		return *current, nil
+	case "next":
+		if next == nil {
+			return calendar.Event{}, fmt.Errorf("no upcoming meeting")
+		}
+		return *next, nil
+	case "":
+		switch {
+		case next != nil && next.Start.Sub(now) <= joinEarly:
+			return *next, nil
+		case current != nil:
+			return *current, nil
+		case next != nil:
+			return *next, nil
+		}
+		return calendar.Event{}, fmt.Errorf("no meeting to join")
+	default:
+		return calendar.Event{}, fmt.Errorf("unknown meeting %q (use current or next)", which)
+	}
+}
+
+// joinEvent opens an event's meeting link and returns a description of the
+// event.
+func (a *App) joinEvent(e calendar.Event) (string, error) {
+	link := meetingLink(e)
+	if link == "" {
+		return "", fmt.Errorf("%s has no meeting link", describeEvent(e))
+	}
+	slog.Debug("joining meeting", "uid", e.UID, "url", link)
+	if err := links.Open(link); err != nil {
+		return "", fmt.Errorf("open meeting link: %w", err)
+	}
+	a.runJoinHooks(e)
+	return describeEvent(e), nil
+}
+
+// openEvent opens an event's web link and returns it.
+func (a *App) openEvent(e calendar.Event) (string, error) {
+	if e.URL == "" {
+		return "", fmt.Errorf("%s has no web link", describeEvent(e))
+	}
+	slog.Debug("opening event", "uid", e.UID, "url", e.URL)
+	if err := links.Open(e.URL); err != nil {
+		return "", fmt.Errorf("open web link: %w", err)
+	}
+	return e.URL, nil
+}
+
+// snoozeEvent reminds about an event again after d and returns a
+// description of it.
+func (a *App) snoozeEvent(e calendar.Event, d time.Duration, now time.Time) (string, error) {
+	if a.notifier == nil {
+		return "", fmt.Errorf("notifications are disabled")
+	}
+	if d <= 0 {
+		return "", fmt.Errorf("snooze duration must be positive")
+	}
+	if !e.End.After(now) {
+		return "", fmt.Errorf("%s has ended", describeEvent(e))
+	}
+	a.snoozeReminder(e.UID, d)
+	return describeEvent(e), nil
+}
+
+// isHidden reports whether the user hid the event with the given UID.
+func (a *App) isHidden(uid string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return slices.ContainsFunc(a.hiddenEntries, func(h hiddenEntry) bool { return h.uid == uid })
+}
+
+// formatEventDetails formats an event's details as plain text, as shown by
+// "calbar details".
+func formatEventDetails(e calendar.Event, notificationBefore []time.Duration) string {
+	var b strings.Builder
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%-14s %s\n", label+":", value)
+		}
+	}
+
+	fmt.Fprintln(&b, describeEvent(e))
+	if e.IsCancelled() {
+		line("Status", "Cancelled")
+	}
+	line("Location", e.Location)
+	if link := meetingLink(e); link != "" {
+		service := e.Meeting.Service
+		if service == "" {
+			service = links.Service(link)
+		}
+		line("Meeting", fmt.Sprintf("%s (%s)", link, service))
+	}
+	line("Meeting ID", e.Meeting.ID)
+	line("Passcode", e.Meeting.Passcode)
+	line("Dial-in", e.Meeting.DialIn)
+	line("Organizer", e.Organizer)
+	if summary := e.AttendeeSummary(); summary != "" {
+		line("Attendees", summary)
+		for _, att := range e.Attendees {
+			fmt.Fprintf(&b, "%-14s %s\n", "", ui.FormatAttendee(att))
+		}
+	}
+	if e.CanRespond() {
+		line("Your response", e.Response.Label())
+	}
+	if reminders := ui.FormatReminderDetails(e, notificationBefore); reminders != "" {
+		for i, r := range strings.Split(reminders, "\n") {
+			label := ""
+			if i == 0 {
+				label = "Reminders:"
+			}
+			fmt.Fprintf(&b, "%-14s %s\n", label, r)
+		}
+	}
+	line("Tags", strings.Join(e.Tags, ", "))
+	line("Source", e.Source)
+	line("Web link", e.URL)
+	line("UID", e.UID)
+	if desc := strings.TrimSpace(e.Description); desc != "" {
+		fmt.Fprintf(&b, "\n%s\n", desc)
+	}
+	return b.String()
+}
+
+// runJoinCommand implements "calbar join".
+func runJoinCommand(cli cliOptions) error {
+	fs := flag.NewFlagSet("calbar join", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	next := fs.Bool("next", false, "")
+	current := fs.Bool("current", false, "")
+	if err := fs.Parse(cli.commandArgs); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+	if *next && *current {
+		return usageErrorf("-next and -current are mutually exclusive")
+	}
+	if fs.NArg() > 1 || (fs.NArg() == 1 && (*next || *current)) {
+		return usageErrorf("expected a UID or agenda number, -next or -current")
+	}
+
+	var desc string
+	var err error
+	switch {
+	case fs.NArg() == 1:
+		err = callControl("JoinEvent", &desc, fs.Arg(0))
+	case *next:
+		err = callControl("Join", &desc, "next")
+	case *current:
+		err = callControl("Join", &desc, "current")
+	default:
+		err = callControl("Join", &desc, "")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("Joining " + desc)
+	return nil
+}
+
+// runHideCommand implements "calbar hide": without an event it hides the UI
+// like the other control commands.
+func runHideCommand(cli cliOptions) error {
+	uid, err := parseUIDArg("hide", cli.commandArgs, false)
+	if err != nil {
+		return err
+	}
+	if uid == "" {
+		return sendControlCommand("hide")
+	}
+	return callControl("HideEvent", nil, uid)
+}
+
+// runUnhideCommand implements "calbar unhide".
+func runUnhideCommand(cli cliOptions) error {
+	uid, err := parseUIDArg("unhide", cli.commandArgs, true)
+	if err != nil {
+		return err
+	}
+	return callControl("UnhideEvent", nil, uid)
+}
+
+// runOpenCommand implements "calbar open".
+func runOpenCommand(cli cliOptions) error {
+	uid, err := parseUIDArg("open", cli.commandArgs, true)
+	if err != nil {
+		return err
+	}
+	var url string
+	if err := callControl("OpenEvent", &url, uid); err != nil {
+		return err
+	}
+	fmt.Println("Opening " + url)
+	return nil
+}
+
+// runSnoozeCommand implements "calbar snooze".
+func runSnoozeCommand(cli cliOptions) error {
+	fs := flag.NewFlagSet("calbar snooze", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	d := fs.Duration("for", 5*time.Minute, "")
+	if err := fs.Parse(cli.commandArgs); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("expected an event UID or agenda number (see calbar agenda)")
+	}
+	if *d <= 0 {
+		return usageErrorf("-for must be positive")
+	}
+
+	var desc string
+	if err := callControl("SnoozeEvent", &desc, fs.Arg(0), int64(d.Seconds())); err != nil {
+		return err
+	}
+	fmt.Printf("Reminding again in %s: %s\n", formatDuration(*d), desc)
+	return nil
+}
+
+// runDetailsCommand implements "calbar details".
+func runDetailsCommand(cli cliOptions) error {
+	uid, err := parseUIDArg("details", cli.commandArgs, true)
+	if err != nil {
+		return err
+	}
+	var details string
+	if err := callControl("GetEventDetails", &details, uid); err != nil {
+		return err
+	}
+	fmt.Print(details)
+	return nil
+}
+
+// parseUIDArg parses the arguments of a command that takes an event UID or
+// agenda number.
+func parseUIDArg(command string, args []string, required bool) (string, error) {
+	fs := flag.NewFlagSet("calbar "+command, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return "", usageError{err}
+	}
+	if *help {
+		return "", errHelp
+	}
+	switch {
+	case fs.NArg() > 1:
+		return "", usageErrorf("expected one event")
+	case fs.NArg() == 0 && required:
+		return "", usageErrorf("expected an event UID or agenda number (see calbar agenda)")
+	case fs.NArg() == 0:
+		return "", nil
+	}
+	return fs.Arg(0), nil
+}
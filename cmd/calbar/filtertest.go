@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/filter"
+	"github.com/cpuguy83/calbar/internal/sync"
+)
+
+// filterTestTimeout bounds how long fetching sources for "calbar filter test"
+// may take.
+const filterTestTimeout = 2 * time.Minute
+
+// runFilterCommand implements "calbar filter test".
+func runFilterCommand(cli cliOptions) error {
+	fs := flag.NewFlagSet("calbar filter", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	rule := fs.String("rule", "", "")
+	snapshot := fs.Bool("snapshot", false, "")
+	if err := fs.Parse(cli.commandArgs); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+
+	// Options may also follow "test"
+	if fs.NArg() == 0 || fs.Arg(0) != "test" {
+		return usageErrorf("expected \"test\"")
+	}
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+	if fs.NArg() != 0 {
+		return usageErrorf("test takes no arguments")
+	}
+
+	var adhoc *filter.Matcher
+	if *rule != "" {
+		m, err := filter.Compile(config.FilterRule{Expr: *rule})
+		if err != nil {
+			return usageErrorf("-rule: %v", err)
+		}
+		adhoc = m
+	}
+
+	cfg, _, err := loadCLIConfig(cli.configPath)
+	if err != nil {
+		return err
+	}
+
+	var explanations []sync.Explanation
+	if *snapshot {
+		events, err := calendar.ReadICS(cfg.Sync.Output)
+		if err != nil {
+			return fmt.Errorf("read snapshot: %w", err)
+		}
+		explanations, err = explainSnapshot(cfg, events)
+		if err != nil {
+			return err
+		}
+	} else {
+		syncer, err := sync.NewSyncer(cfg)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), filterTestTimeout)
+		defer cancel()
+
+		var failures []sync.SourceFailure
+		explanations, failures, err = syncer.Explain(ctx)
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", failure)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	printExplanations(os.Stdout, explanations, adhoc)
+	return nil
+}
+
+// explainSnapshot explains events read back from the ICS output. Transforms
+// have already been applied to them, and events hidden by the filters at the
+// time of the sync are not in the snapshot.
+func explainSnapshot(cfg *config.Config, events []calendar.Event) ([]sync.Explanation, error) {
+	global, err := filter.New(cfg.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("filters: %w", err)
+	}
+
+	sourceFilters := make(map[string]*filter.Filter, len(cfg.Sources))
+	for _, src := range cfg.Sources {
+		f, err := filter.New(src.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("source %q filters: %w", src.Name, err)
+		}
+		sourceFilters[src.Name] = f
+	}
+	noRules := &filter.Filter{}
+
+	explanations := make([]sync.Explanation, 0, len(events))
+	for _, e := range events {
+		// CalDAV events are labeled "<source>/<calendar>"
+		f, ok := sourceFilters[e.Source]
+		if !ok {
+			name, _, _ := strings.Cut(e.Source, "/")
+			if f, ok = sourceFilters[name]; !ok {
+				f = noRules
+			}
+		}
+
+		x := sync.Explanation{Event: e, Source: f.Explain(e)}
+		if x.Source.Included {
+			d := global.Explain(e)
+			x.Global = &d
+		}
+		explanations = append(explanations, x)
+	}
+	return explanations, nil
+}
+
+// printExplanations prints each event with its fate and the rules that
+// decided it, followed by a summary.
+func printExplanations(w io.Writer, explanations []sync.Explanation, adhoc *filter.Matcher) {
+	var included, adhocMatches int
+	for _, x := range explanations {
+		kept := x.Source.Included && x.Global != nil && x.Global.Included
+		mark := "-"
+		if kept {
+			mark = "+"
+			included++
+		}
+
+		fmt.Fprintf(w, "%s %s [%s]\n", mark, describeEvent(x.Event), x.Event.Source)
+		fmt.Fprintf(w, "    source filter: %s\n", x.Source.Reason)
+		if x.Global != nil {
+			fmt.Fprintf(w, "    global filter: %s\n", x.Global.Reason)
+		}
+		if adhoc != nil {
+			result := "no match"
+			if adhoc.Matches(x.Event) {
+				result = "matches"
+				adhocMatches++
+			}
+			fmt.Fprintf(w, "    -rule: %s\n", result)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d events: %d included, %d excluded", len(explanations), included, len(explanations)-included)
+	if adhoc != nil {
+		fmt.Fprintf(w, ", %d matching -rule", adhocMatches)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/hooks"
+)
+
+// hookCatchUp is how late a reminder, start or end hook still runs, so hooks
+// missed while the machine slept or a sync was slow run once CalBar notices,
+// but those of events long past do not run at startup.
+const hookCatchUp = 2 * time.Minute
+
+// hookCheckInterval is the longest the hook loop sleeps, so events added by
+// a sync are picked up.
+const hookCheckInterval = 30 * time.Second
+
+// hookRun is a hook due to run for an event.
+type hookRun struct {
+	kind  hooks.Kind
+	event calendar.Event
+}
+
+// newHookRunner creates the hook runner. Commands get the event on stdin in
+// the GetEvents JSON format.
+func newHookRunner(a *App) (*hooks.Runner, error) {
+	return hooks.New(a.cfg.Hooks, func(e calendar.Event) ([]byte, error) {
+		return json.Marshal(newAgendaEvent(e))
+	})
+}
+
+// hookLoop runs reminder, start and end hooks as they come due.
+func (a *App) hookLoop() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			now := time.Now()
+			a.mu.RLock()
+			events := a.visibleEvents()
+			a.mu.RUnlock()
+
+			for _, run := range a.dueHooks(events, now) {
+				a.hooks.Run(a.ctx, run.kind, run.event)
+			}
+			timer.Reset(a.untilNextHook(events, now))
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
+// dueHooks returns the hooks due at now that have not run yet, and records
+// them as run. Each runs once per occurrence of a timed event: end hooks come
+// first so that back-to-back meetings undo the previous one before starting
+// the next. Only events whose start hook ran get their end hook, also when
+// they are hidden, cancelled, declined or removed before they end. All-day
+// events run no hooks, like they never turn on do-not-disturb.
+// Must be called from the hook loop only.
+func (a *App) dueHooks(events []calendar.Event, now time.Time) []hookRun {
+	due := func(t time.Time) bool {
+		return !t.After(now) && now.Sub(t) < hookCatchUp
+	}
+
+	fire := func(key string) bool {
+		if _, ok := a.hooksRun[key]; ok {
+			return false
+		}
+		a.hooksRun[key] = now
+		return true
+	}
+
+	var ends, reminders, starts []hookRun
+	current := make(map[string]bool)
+	for _, e := range events {
+		if e.AllDay || !a.isBusy(e) {
+			continue
+		}
+		occurrence := fmt.Sprintf("%s-%d", e.UID, e.Start.Unix())
+		current[occurrence] = true
+		if _, ok := a.hooksStarted[occurrence]; ok {
+			a.hooksStarted[occurrence] = e // Pick up a new end time
+		}
+
+		// An event that is already over does not start
+		if due(e.Start) && e.End.After(now) && fire(occurrence+"-start") {
+			starts = append(starts, hookRun{hooks.Start, e})
+			a.hooksStarted[occurrence] = e
+		}
+		for _, trigger := range a.notificationTriggers(e) {
+			key := fmt.Sprintf("%s-reminder-%d", occurrence, trigger.Unix())
+			if due(trigger) && !a.remindersDismissed(e.UID) && fire(key) {
+				reminders = append(reminders, hookRun{hooks.Reminder, e})
+			}
+		}
+	}
+
+	// End the started events that have ended, also while the machine slept,
+	// or that are no longer listed or busy
+	for occurrence, e := range a.hooksStarted {
+		if current[occurrence] && now.Before(e.End) {
+			continue
+		}
+		delete(a.hooksStarted, occurrence)
+		if fire(occurrence + "-end") {
+			ends = append(ends, hookRun{hooks.End, e})
+		}
+	}
+
+	// Forget runs that can no longer come due again
+	for key, t := range a.hooksRun {
+		if now.Sub(t) > hookCatchUp+time.Hour {
+			delete(a.hooksRun, key)
+		}
+	}
+
+	return slices.Concat(ends, reminders, starts)
+}
+
+// untilNextHook returns how long the hook loop can sleep before the next
+// reminder, start or end, at most hookCheckInterval.
+func (a *App) untilNextHook(events []calendar.Event, now time.Time) time.Duration {
+	next := hookCheckInterval
+	consider := func(t time.Time) {
+		if d := t.Sub(now); d > 0 && d < next {
+			next = d
+		}
+	}
+	for _, e := range events {
+		if e.AllDay {
+			continue
+		}
+		consider(e.Start)
+		consider(e.End)
+		for _, trigger := range a.notificationTriggers(e) {
+			consider(trigger)
+		}
+	}
+	return next
+}
+
+// runJoinHooks runs the join hooks for an event joined from CalBar.
+func (a *App) runJoinHooks(e calendar.Event) {
+	if a.hooks != nil {
+		a.hooks.Run(a.ctx, hooks.Join, e)
+	}
+}
+
+// eventForMeetingLink returns the visible event with the given meeting link
+// that is in progress or starts next, for links opened from the UI.
+func (a *App) eventForMeetingLink(link string, now time.Time) (calendar.Event, bool) {
+	a.mu.RLock()
+	events := a.visibleEvents()
+	a.mu.RUnlock()
+
+	for _, e := range events {
+		if e.End.After(now) && meetingLink(e) == link {
+			return e, true
+		}
+	}
+	return calendar.Event{}, false
+}
@@ -3,28 +3,49 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"sort"
 	gosync "sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cpuguy83/calbar/internal/calendar"
 	"github.com/cpuguy83/calbar/internal/clipboard"
 	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/dnd"
+	"github.com/cpuguy83/calbar/internal/filter"
+	"github.com/cpuguy83/calbar/internal/hooks"
 	"github.com/cpuguy83/calbar/internal/links"
 	"github.com/cpuguy83/calbar/internal/notify"
+	"github.com/cpuguy83/calbar/internal/server"
 	"github.com/cpuguy83/calbar/internal/sync"
 	"github.com/cpuguy83/calbar/internal/tray"
 	"github.com/cpuguy83/calbar/internal/ui"
 	"github.com/cpuguy83/calbar/internal/ui/menu"
 )
 
+// notificationTarget is the event a sent notification refers to, used to handle
+// its action buttons.
+type notificationTarget struct {
+	uid         string
+	meetingLink string
+}
+
+// notificationResponses maps notification action keys to invitation responses.
+var notificationResponses = map[string]calendar.PartStat{
+	"accept":    calendar.PartStatAccepted,
+	"tentative": calendar.PartStatTentative,
+	"decline":   calendar.PartStatDeclined,
+}
+
 // hiddenEntry tracks a hidden event UID and when it was hidden.
 type hiddenEntry struct {
 	uid    string
@@ -43,6 +64,11 @@ func main() {
 		return
 	}
 
+	if _, ok := localCommands[cli.command]; ok {
+		setupLogging(cli.verbose)
+		os.Exit(runLocalCommand(cli))
+	}
+
 	if cli.command != "" {
 		help, err := parseControlCommand(cli.command, cli.commandArgs)
 		if err != nil {
@@ -101,7 +127,7 @@ func parseCLI(args []string) (cliOptions, error) {
 			return cliOptions{}, fmt.Errorf("help takes at most one command")
 		}
 		if len(remaining) == 2 {
-			if _, ok := controlCommandMethods[remaining[1]]; !ok {
+			if !isCommand(remaining[1]) {
 				return cliOptions{}, fmt.Errorf("unknown command %q", remaining[1])
 			}
 			cli.helpCommand = remaining[1]
@@ -110,7 +136,7 @@ func parseCLI(args []string) (cliOptions, error) {
 	}
 	cli.command = remaining[0]
 	cli.commandArgs = remaining[1:]
-	if _, ok := controlCommandMethods[cli.command]; !ok {
+	if !isCommand(cli.command) {
 		return cliOptions{}, fmt.Errorf("unknown command %q", cli.command)
 	}
 	return cli, nil
@@ -165,9 +191,12 @@ func printBaseUsage(w io.Writer) {
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Commands:")
 	for _, name := range controlCommandNames {
-		fmt.Fprintf(w, "  %-8s %s\n", name, controlCommandDescriptions[name])
+		fmt.Fprintf(w, "  %-13s %s\n", name, controlCommandDescriptions[name])
+	}
+	for _, name := range localCommandNames {
+		fmt.Fprintf(w, "  %-13s %s\n", name, localCommands[name].description)
 	}
-	fmt.Fprintln(w, "  help     Show help for calbar or a command")
+	fmt.Fprintln(w, "  help          Show help for calbar or a command")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Options:")
 	fmt.Fprintln(w, "  -config string")
@@ -179,6 +208,10 @@ func printBaseUsage(w io.Writer) {
 }
 
 func printCommandUsage(w io.Writer, command string) {
+	if cmd, ok := localCommands[command]; ok {
+		printLocalCommandUsage(w, command, cmd)
+		return
+	}
 	desc := controlCommandDescriptions[command]
 	if desc == "" {
 		desc = "Control the active CalBar instance"
@@ -225,7 +258,14 @@ func runDaemon(configPath string) error {
 		configPath:      resolvedConfigPath,
 		quitCh:          make(chan struct{}),
 		notifiedEvents:  make(map[string]time.Time),
-		notificationIDs: make(map[uint32]string),
+		notificationIDs: make(map[uint32]notificationTarget),
+		snoozes:         make(map[string]time.Time),
+		dismissed:       make(map[string]time.Time),
+		countdowns:      make(map[string]countdown),
+		hooksRun:        make(map[string]time.Time),
+		hooksStarted:    make(map[string]calendar.Event),
+		cancelledSeen:   loadCancelledSeen(cancelledPath(cfg.Sync.Output)),
+		notifyWake:      make(chan struct{}, 1),
 	}
 
 	return app.Run()
@@ -247,20 +287,40 @@ type App struct {
 	tray       *tray.Tray
 	ui         ui.UI
 	notifier   *notify.Notifier
+	policy     *notify.Policy // When and how urgently to notify, from the notification config
 	syncer     *sync.Syncer
 	control    *controlServer
+	server     *server.Server
+	hooks      *hooks.Runner
+	dnd        *dnd.Backend                // Nil unless dnd is enabled
+	dndMatch   *filter.Matcher             // Events that turn on do-not-disturb; nil matches all
+	dndRestore func(context.Context) error // Restores do-not-disturb while CalBar has it on; dnd loop only
+	dndOn      atomic.Bool
+	dndDone    chan struct{} // Closed when the dnd loop has restored do-not-disturb and exited
 
 	mu            gosync.RWMutex
 	events        []calendar.Event
-	hiddenEntries []hiddenEntry // UIDs hidden by user, sorted by hide time (oldest first)
+	hiddenEntries []hiddenEntry        // UIDs hidden by user, sorted by hide time (oldest first)
+	cancelledSeen map[string]time.Time // UID -> when the event was first seen cancelled
+	conflictsSeen map[string]bool      // Conflict keys at the last sync; nil before the first
 	lastSync      time.Time
 	lastSyncErr   error
 	syncErrors    []string
 	syncing       bool
 
+	// updateUI runs on several goroutines with the menu backend
+	barMu         gosync.Mutex
+	lastBarStatus string // Status bar state last emitted over D-Bus, as JSON; guarded by barMu
+
 	// Notification tracking
 	notifiedEvents  map[string]time.Time
-	notificationIDs map[uint32]string // notification ID -> meeting URL
+	notificationIDs map[uint32]notificationTarget
+	snoozes         map[string]time.Time      // UID -> when to remind again; guarded by mu
+	dismissed       map[string]time.Time      // UID -> end of the event whose reminders were dismissed; guarded by mu
+	countdowns      map[string]countdown      // UID -> open countdown notification; guarded by mu
+	notifyWake      chan struct{}             // Check notifications now, when a snooze ends
+	hooksRun        map[string]time.Time      // Hook run key -> when it ran; hook loop only
+	hooksStarted    map[string]calendar.Event // Occurrence -> event whose start hook ran and end hook has not; hook loop only
 
 	// Context for background goroutines
 	ctx    context.Context
@@ -285,8 +345,9 @@ func (a *App) selectBackend() (ui.UI, error) {
 			TimeRange:          a.cfg.UI.TimeRange,
 			EventEndGrace:      a.cfg.UI.EventEndGrace,
 			HoverDismissDelay:  *a.cfg.UI.HoverDismissDelay,
-			NotificationBefore: a.cfg.Notifications.Before,
+			NotificationBefore: a.policy.Before,
 			CSSFile:            a.cfg.UI.CSSFile,
+			DimTentative:       *a.cfg.Availability.DimTentative,
 		}), nil
 
 	case "menu":
@@ -296,7 +357,8 @@ func (a *App) selectBackend() (ui.UI, error) {
 			Args:               a.cfg.UI.Menu.Args,
 			TimeRange:          a.cfg.UI.TimeRange,
 			EventEndGrace:      a.cfg.UI.EventEndGrace,
-			NotificationBefore: a.cfg.Notifications.Before,
+			NotificationBefore: a.policy.Before,
+			DimTentative:       *a.cfg.Availability.DimTentative,
 		})
 
 	case "auto", "":
@@ -307,8 +369,9 @@ func (a *App) selectBackend() (ui.UI, error) {
 				TimeRange:          a.cfg.UI.TimeRange,
 				EventEndGrace:      a.cfg.UI.EventEndGrace,
 				HoverDismissDelay:  *a.cfg.UI.HoverDismissDelay,
-				NotificationBefore: a.cfg.Notifications.Before,
+				NotificationBefore: a.policy.Before,
 				CSSFile:            a.cfg.UI.CSSFile,
+				DimTentative:       *a.cfg.Availability.DimTentative,
 			}), nil
 		}
 		slog.Info("GTK not available, falling back to menu backend")
@@ -317,7 +380,8 @@ func (a *App) selectBackend() (ui.UI, error) {
 			Args:               a.cfg.UI.Menu.Args,
 			TimeRange:          a.cfg.UI.TimeRange,
 			EventEndGrace:      a.cfg.UI.EventEndGrace,
-			NotificationBefore: a.cfg.Notifications.Before,
+			NotificationBefore: a.policy.Before,
+			DimTentative:       *a.cfg.Availability.DimTentative,
 		})
 
 	default:
@@ -342,6 +406,16 @@ func (a *App) activate() error {
 		return fmt.Errorf("no calendar sources configured")
 	}
 
+	a.policy, err = notify.NewPolicy(a.cfg.Notifications)
+	if err != nil {
+		return fmt.Errorf("notifications: %w", err)
+	}
+
+	a.hooks, err = newHookRunner(a)
+	if err != nil {
+		return fmt.Errorf("hooks: %w", err)
+	}
+
 	// Initialize tray
 	a.tray, err = tray.New()
 	if err != nil {
@@ -364,14 +438,25 @@ func (a *App) activate() error {
 		return fmt.Errorf("start control server: %w", err)
 	}
 
+	if err := a.startServer(); err != nil {
+		slog.Warn("failed to start HTTP server", "error", err)
+	}
+
 	// Set up action handler
 	a.ui.OnAction(func(action ui.Action) {
 		switch action.Type {
 		case ui.ActionOpenURL:
 			slog.Debug("opening URL", "url", action.URL)
 			links.Open(action.URL)
+			if e, ok := a.eventForMeetingLink(action.URL, time.Now()); ok {
+				a.runJoinHooks(e)
+			}
 		case ui.ActionSync:
 			a.triggerSync()
+		case ui.ActionRespond:
+			a.respondToEvent(action.UID, action.Response)
+		case ui.ActionQuickAdd:
+			a.quickAddFromUI(action.Text)
 		}
 	})
 
@@ -410,18 +495,8 @@ func (a *App) activate() error {
 			slog.Warn("failed to initialize notifications", "error", err)
 		} else {
 			// Watch for notification actions (e.g., "Join Meeting" button)
-			a.notifier.WatchActions(func(id uint32, actionKey string) {
-				if actionKey == "join" {
-					a.mu.RLock()
-					url := a.notificationIDs[id]
-					a.mu.RUnlock()
-
-					if url != "" {
-						slog.Debug("opening meeting from notification", "url", url)
-						links.Open(url)
-					}
-				}
-			})
+			a.notifier.WatchActions(a.handleNotificationAction)
+			a.notifier.WatchClosed(a.notificationClosed)
 		}
 	}
 
@@ -444,6 +519,21 @@ func (a *App) activate() error {
 	// Start notification checker goroutine
 	go a.notificationLoop()
 
+	// Start do-not-disturb goroutine
+	if a.cfg.DND.Enabled {
+		if err := a.setupDND(); err != nil {
+			slog.Warn("do-not-disturb during events disabled", "error", err)
+		} else {
+			a.dndDone = make(chan struct{})
+			go a.dndLoop()
+		}
+	}
+
+	// Start hook scheduler goroutine
+	if len(a.cfg.Hooks) > 0 {
+		go a.hookLoop()
+	}
+
 	slog.Info("calbar running",
 		"sources", a.syncer.SourceCount(),
 		"sync_interval", a.syncer.Interval(),
@@ -478,6 +568,13 @@ func (a *App) cleanup() {
 	if a.cancel != nil {
 		a.cancel()
 	}
+	if a.dndDone != nil {
+		// Wait for do-not-disturb to be restored
+		select {
+		case <-a.dndDone:
+		case <-time.After(2 * dndTimeout):
+		}
+	}
 	if a.tray != nil {
 		a.tray.Stop()
 	}
@@ -487,6 +584,9 @@ func (a *App) cleanup() {
 	if a.control != nil {
 		a.control.Close()
 	}
+	if a.server != nil {
+		a.server.Close()
+	}
 }
 
 // hideEvent hides an event by UID (ephemeral, until restart).
@@ -523,10 +623,62 @@ func (a *App) unhideEvent(uid string) {
 	a.scheduleUIUpdate()
 }
 
-// visibleEvents returns events that are not hidden by the user.
+// respondToEvent sends an invitation response for the event with the given UID
+// through its source. The local copy is updated once the server accepts it.
+func (a *App) respondToEvent(uid string, status calendar.PartStat) {
+	a.mu.RLock()
+	i := slices.IndexFunc(a.events, func(e calendar.Event) bool { return e.UID == uid })
+	var event calendar.Event
+	if i >= 0 {
+		event = a.events[i]
+	}
+	a.mu.RUnlock()
+
+	if i < 0 {
+		slog.Warn("cannot respond to unknown event", "uid", uid)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(a.ctx, 30*time.Second)
+		defer cancel()
+
+		if err := a.syncer.Respond(ctx, event, status); err != nil {
+			slog.Warn("failed to respond to invitation", "summary", event.Summary, "response", status, "error", err)
+			if errors.Is(err, calendar.ErrConflict) {
+				a.triggerSync()
+			}
+			if a.notifier != nil {
+				a.notifier.Send(notify.Notification{
+					Summary: "Could not respond to " + event.Summary,
+					Body:    err.Error(),
+					Urgency: notify.UrgencyNormal,
+				})
+			}
+			return
+		}
+
+		a.mu.Lock()
+		for i := range a.events {
+			if a.events[i].UID == uid {
+				a.events[i].Response = status
+			}
+		}
+		a.mu.Unlock()
+		a.scheduleUIUpdate()
+
+		// Refresh server state (e.g. CalDAV ETags) for later writes
+		a.triggerSync()
+	}()
+}
+
+// visibleEvents returns events that are not hidden by the user, leaving out
+// declined invitations unless configured otherwise and cancelled events once
+// their window has passed.
 // Must be called with at least RLock held.
 func (a *App) visibleEvents() []calendar.Event {
-	if len(a.hiddenEntries) == 0 {
+	hideDeclined := *a.cfg.Availability.HideDeclined
+	if len(a.hiddenEntries) == 0 && len(a.cancelledSeen) == 0 && !hideDeclined {
 		return a.events
 	}
 	// Build a set of hidden UIDs for O(1) lookup
@@ -534,8 +686,15 @@ func (a *App) visibleEvents() []calendar.Event {
 	for _, h := range a.hiddenEntries {
 		hiddenSet[h.uid] = struct{}{}
 	}
+	cancelledCutoff := time.Now().Add(-*a.cfg.UI.CancelledWindow)
 	visible := make([]calendar.Event, 0, len(a.events))
 	for _, e := range a.events {
+		if hideDeclined && e.Response == calendar.PartStatDeclined {
+			continue
+		}
+		if seen, ok := a.cancelledSeen[e.UID]; ok && e.IsCancelled() && !seen.After(cancelledCutoff) {
+			continue
+		}
 		if _, hidden := hiddenSet[e.UID]; !hidden {
 			visible = append(visible, e)
 		}
@@ -543,6 +702,38 @@ func (a *App) visibleEvents() []calendar.Event {
 	return visible
 }
 
+// isBusy reports whether an event should count toward the imminent tray
+// state and notifications. Cancelled and declined events never do, even
+// when declined events are listed, and events shown as free do not unless
+// configured otherwise.
+func (a *App) isBusy(e calendar.Event) bool {
+	if e.IsCancelled() || e.Response == calendar.PartStatDeclined {
+		return false
+	}
+	return !e.IsFree() || !*a.cfg.Availability.IgnoreFree
+}
+
+// trackCancelled records when each cancelled event was first seen, so it can
+// be shown struck through for ui.cancelled_window before being hidden. It
+// reports whether the set of cancelled events changed.
+// Must be called with Lock held.
+func (a *App) trackCancelled(now time.Time) bool {
+	seen := make(map[string]time.Time)
+	for _, e := range a.events {
+		if !e.IsCancelled() {
+			continue
+		}
+		if t, ok := a.cancelledSeen[e.UID]; ok {
+			seen[e.UID] = t
+		} else {
+			seen[e.UID] = now
+		}
+	}
+	changed := !maps.Equal(seen, a.cancelledSeen)
+	a.cancelledSeen = seen
+	return changed
+}
+
 // hiddenEvents returns events that are hidden by the user, sorted by hide time (most recent first).
 // Must be called with at least RLock held.
 func (a *App) hiddenEvents() []calendar.Event {
@@ -647,6 +838,9 @@ func formatSyncFailures(failures []sync.SourceFailure, err error) []string {
 func (a *App) onSyncComplete(events []calendar.Event, failures []sync.SourceFailure, err error) {
 	a.mu.Lock()
 	syncErrors := formatSyncFailures(failures, err)
+	var output []calendar.Event
+	var newConflicts []calendar.Conflict
+	var cancelled map[string]time.Time
 	if err != nil {
 		slog.Warn("sync failed", "error", err)
 		a.lastSyncErr = err
@@ -678,6 +872,11 @@ func (a *App) onSyncComplete(events []calendar.Event, failures []sync.SourceFail
 
 		// Merge and sort
 		a.events = calendar.Merge(merged)
+		if a.trackCancelled(time.Now()) {
+			cancelled = maps.Clone(a.cancelledSeen)
+		}
+		newConflicts = a.trackConflicts(time.Now())
+		output = slices.Clone(a.events)
 		a.lastSyncErr = nil
 		a.syncErrors = syncErrors
 
@@ -689,6 +888,19 @@ func (a *App) onSyncComplete(events []calendar.Event, failures []sync.SourceFail
 	a.mu.Unlock()
 	a.endSync()
 
+	a.notifyConflicts(newConflicts, time.Now())
+
+	if output != nil && a.cfg.Sync.Output != "" {
+		if err := calendar.WriteICS(a.cfg.Sync.Output, output); err != nil {
+			slog.Warn("failed to write ICS output", "path", a.cfg.Sync.Output, "error", err)
+		}
+	}
+	if path := cancelledPath(a.cfg.Sync.Output); cancelled != nil && path != "" {
+		if err := saveCancelledSeen(path, cancelled); err != nil {
+			slog.Warn("failed to save cancelled events", "path", path, "error", err)
+		}
+	}
+
 	// Update UI - schedule on appropriate thread
 	a.scheduleUIUpdate()
 }
@@ -698,9 +910,9 @@ func (a *App) updateUI() {
 	a.mu.RLock()
 	events := a.visibleEvents()
 	hidden := a.hiddenEvents()
-	lastSync := a.lastSync
-	lastSyncErr := a.lastSyncErr
+	isStale := a.isStale()
 	syncErrors := slices.Clone(a.syncErrors)
+	lastSync := a.lastSync
 	a.mu.RUnlock()
 
 	// Update UI with events
@@ -708,7 +920,6 @@ func (a *App) updateUI() {
 	a.ui.SetHiddenEvents(hidden)
 
 	// Update stale state
-	isStale := len(syncErrors) > 0 || lastSyncErr != nil || time.Since(lastSync) > 2*a.syncer.Interval()
 	a.ui.SetStale(isStale)
 	a.ui.SetSyncErrors(syncErrors)
 
@@ -722,6 +933,17 @@ func (a *App) updateUI() {
 
 	// Update tooltip
 	a.updateTrayTooltip()
+
+	// Tell status bar modules and other D-Bus clients
+	a.publishBarStatus()
+	a.publishControlState(events, isStale, syncErrors, lastSync)
+}
+
+// isStale reports whether the events may be out of date because the last
+// sync failed, in whole or in part, or is overdue.
+// Must be called with at least RLock held.
+func (a *App) isStale() bool {
+	return len(a.syncErrors) > 0 || a.lastSyncErr != nil || time.Since(a.lastSync) > 2*a.syncer.Interval()
 }
 
 // updateTrayState updates the tray icon based on upcoming events.
@@ -730,7 +952,16 @@ func (a *App) updateTrayState() {
 	events := a.visibleEvents()
 	a.mu.RUnlock()
 
-	now := time.Now()
+	if a.hasImminent(events, time.Now()) {
+		a.tray.SetState(tray.StateImminent)
+		return
+	}
+	a.tray.SetState(tray.StateNormal)
+}
+
+// hasImminent reports whether a busy event starts within the next 15
+// minutes.
+func (a *App) hasImminent(events []calendar.Event, now time.Time) bool {
 	eventEndGrace := a.cfg.UI.EventEndGrace
 
 	for _, e := range events {
@@ -739,14 +970,16 @@ func (a *App) updateTrayState() {
 			continue
 		}
 
+		if !a.isBusy(e) {
+			continue
+		}
+
 		startsIn := e.Start.Sub(now)
 		if startsIn > 0 && startsIn <= 15*time.Minute {
-			a.tray.SetState(tray.StateImminent)
-			return
+			return true
 		}
 	}
-
-	a.tray.SetState(tray.StateNormal)
+	return false
 }
 
 // updateTrayTooltip updates the tray tooltip with the next event.
@@ -756,14 +989,42 @@ func (a *App) updateTrayTooltip() {
 	a.mu.RUnlock()
 
 	now := time.Now()
+	next := a.upcomingEvents(events, now)
+	if len(next) == 0 {
+		a.tray.SetTooltip("No upcoming events")
+		return
+	}
+
+	e := next[0]
+	startsIn := e.Start.Sub(now)
+	var timeStr string
+	if startsIn < 0 {
+		timeStr = "Now"
+	} else if startsIn < time.Hour {
+		timeStr = fmt.Sprintf("in %d min", int(startsIn.Minutes()))
+	} else {
+		timeStr = e.Start.Format("3:04 PM")
+	}
+
+	a.tray.SetTooltip(fmt.Sprintf("%s - %s", e.Summary, timeStr))
+}
+
+// upcomingEvents returns the events the tray tooltip and status bar modules
+// can show, soonest first: timed events that are not cancelled, start within
+// the UI time range and have not ended.
+func (a *App) upcomingEvents(events []calendar.Event, now time.Time) []calendar.Event {
 	cutoff := now.Add(a.cfg.UI.TimeRange)
 	eventEndGrace := a.cfg.UI.EventEndGrace
 
+	var upcoming []calendar.Event
 	for _, e := range events {
 		// Skip all-day events for tooltip
 		if e.AllDay {
 			continue
 		}
+		if e.IsCancelled() {
+			continue
+		}
 		// Keep events visible for a grace period after they end
 		if e.End.Add(eventEndGrace).Before(now) {
 			continue
@@ -771,22 +1032,9 @@ func (a *App) updateTrayTooltip() {
 		if e.Start.After(cutoff) {
 			continue
 		}
-
-		startsIn := e.Start.Sub(now)
-		var timeStr string
-		if startsIn < 0 {
-			timeStr = "Now"
-		} else if startsIn < time.Hour {
-			timeStr = fmt.Sprintf("in %d min", int(startsIn.Minutes()))
-		} else {
-			timeStr = e.Start.Format("3:04 PM")
-		}
-
-		a.tray.SetTooltip(fmt.Sprintf("%s - %s", e.Summary, timeStr))
-		return
+		upcoming = append(upcoming, e)
 	}
-
-	a.tray.SetTooltip("No upcoming events")
+	return upcoming
 }
 
 // notificationLoop checks for upcoming events and sends notifications.
@@ -800,6 +1048,8 @@ func (a *App) notificationLoop() {
 			a.checkNotifications()
 			// Also update tray state periodically
 			a.scheduleUIUpdate()
+		case <-a.notifyWake:
+			a.checkNotifications()
 		case <-a.ctx.Done():
 			return
 		}
@@ -824,6 +1074,13 @@ func (a *App) checkNotifications() {
 		if e.End.Add(eventEndGrace).Before(now) {
 			continue
 		}
+		if !a.isBusy(e) || a.remindersDismissed(e.UID) {
+			continue
+		}
+
+		if a.takeSnooze(e.UID, now) {
+			a.sendNotification(e, e.Start.Sub(now))
+		}
 
 		for _, trigger := range a.notificationTriggers(e) {
 			if trigger.Before(now) || trigger.After(now.Add(time.Minute)) {
@@ -841,6 +1098,10 @@ func (a *App) checkNotifications() {
 		}
 	}
 
+	if a.cfg.Notifications.Countdown {
+		a.updateCountdowns(events, now)
+	}
+
 	// Cleanup old entries
 	cutoff := now.Add(-24 * time.Hour)
 	for k, t := range a.notifiedEvents {
@@ -848,18 +1109,12 @@ func (a *App) checkNotifications() {
 			delete(a.notifiedEvents, k)
 		}
 	}
+	a.pruneReminders(now)
+	a.notifier.CleanupOldNotifications(24 * time.Hour)
 }
 
 func (a *App) notificationTriggers(event calendar.Event) []time.Time {
-	if a.cfg.Notifications.Before != nil {
-		triggers := make([]time.Time, 0, len(a.cfg.Notifications.Before))
-		for _, before := range a.cfg.Notifications.Before {
-			triggers = append(triggers, event.Start.Add(-before))
-		}
-		return dedupeTimes(triggers)
-	}
-
-	return dedupeTimes(event.NotifyAt)
+	return dedupeTimes(a.policy.Triggers(event))
 }
 
 func dedupeTimes(times []time.Time) []time.Time {
@@ -882,30 +1137,13 @@ func dedupeTimes(times []time.Time) []time.Time {
 	return result
 }
 
-// sendNotification sends a notification for an event.
+// sendNotification sends a reminder for an event. In countdown mode it
+// updates the event's countdown notification instead, if one is open.
 func (a *App) sendNotification(event calendar.Event, startsIn time.Duration) {
-	body := fmt.Sprintf("Starts in %s", formatDuration(startsIn))
-
-	notif := notify.Notification{
-		Summary:  event.Summary,
-		Body:     body,
-		EventUID: event.UID,
-		Urgency:  notify.UrgencyNormal,
-	}
-
-	// Add join action if meeting link detected
-	meetingLink := event.Meeting.URL
-	if meetingLink == "" {
-		meetingLink = links.DetectFromEvent(event.Location, event.Description, event.URL)
-	}
-	if meetingLink != "" {
-		notif.Actions = []notify.Action{
-			{Key: "join", Label: "Join Meeting"},
-		}
-	}
-
-	if startsIn <= 5*time.Minute {
-		notif.Urgency = notify.UrgencyCritical
+	notif := a.reminderNotification(event, startsIn)
+	if a.cfg.Notifications.Countdown {
+		a.sendCountdown(event, notif, time.Time{})
+		return
 	}
 
 	id, err := a.notifier.Send(notif)
@@ -914,14 +1152,70 @@ func (a *App) sendNotification(event calendar.Event, startsIn time.Duration) {
 		return
 	}
 
-	// Track notification ID -> meeting URL for join action
-	if meetingLink != "" && id != 0 {
+	// Track notification ID -> event for its actions
+	if id != 0 {
 		a.mu.Lock()
-		a.notificationIDs[id] = meetingLink
+		a.notificationIDs[id] = notificationTarget{uid: event.UID, meetingLink: meetingLink(event)}
 		a.mu.Unlock()
 	}
 }
 
+// reminderNotification builds the reminder for an event that starts in
+// startsIn.
+func (a *App) reminderNotification(event calendar.Event, startsIn time.Duration) notify.Notification {
+	notif := notify.Notification{
+		Summary:   event.Summary,
+		Body:      fmt.Sprintf("Starts in %s", formatDuration(startsIn)),
+		Location:  event.Location,
+		Organizer: event.Organizer,
+		Link:      meetingLink(event),
+		EventUID:  event.UID,
+		Urgency:   a.policy.Urgency(event, startsIn),
+	}
+	if a.dndOn.Load() {
+		// Daemons that let anything through do-not-disturb let critical
+		// notifications through
+		notif.Urgency = notify.UrgencyCritical
+	}
+
+	notif.Actions = reminderActions(event, a.notifier.MaxActions())
+	return notif
+}
+
+// reminderActions returns the actions of a reminder, most important first,
+// keeping at most max of them when max is positive. The invitation responses
+// are offered together or not at all.
+func reminderActions(event calendar.Event, max int) []notify.Action {
+	var actions []notify.Action
+
+	// Add join action if meeting link detected
+	if meetingLink(event) != "" {
+		actions = append(actions, notify.Action{Key: "join", Label: "Join Meeting"})
+	}
+	actions = append(actions,
+		notify.Action{Key: "snooze-5m", Label: "Snooze 5m"},
+		notify.Action{Key: "dismiss", Label: "Dismiss for this event"},
+	)
+
+	// Offer invitation responses until the user has answered
+	if event.CanRespond() && event.Response == calendar.PartStatNeedsAction && (max <= 0 || len(actions)+3 <= max) {
+		actions = append(actions,
+			notify.Action{Key: "accept", Label: "Accept"},
+			notify.Action{Key: "tentative", Label: "Tentative"},
+			notify.Action{Key: "decline", Label: "Decline"},
+		)
+	}
+
+	actions = append(actions,
+		notify.Action{Key: "snooze-1m", Label: "Snooze 1m"},
+		notify.Action{Key: "details", Label: "Open details"},
+	)
+	if max > 0 && len(actions) > max {
+		actions = actions[:max]
+	}
+	return actions
+}
+
 // formatDuration formats a duration for display.
 func formatDuration(d time.Duration) string {
 	if d < 0 {
@@ -2,12 +2,17 @@ package main
 
 import (
 	"bytes"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/cpuguy83/calbar/internal/calendar"
 	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/filter"
+	"github.com/cpuguy83/calbar/internal/notify"
+	"github.com/godbus/dbus/v5"
 )
 
 func TestParseCLI(t *testing.T) {
@@ -29,6 +34,10 @@ func TestParseCLI(t *testing.T) {
 		{name: "command", args: []string{"show"}, wantCommand: "show"},
 		{name: "global flags before command", args: []string{"--config", "test.yaml", "toggle"}, wantConfig: "test.yaml", wantCommand: "toggle"},
 		{name: "command args preserved", args: []string{"search", "-v"}, wantCommand: "search", wantArgs: []string{"-v"}},
+		{name: "local command", args: []string{"secret", "set", "Work"}, wantCommand: "secret", wantArgs: []string{"set", "Work"}},
+		{name: "help local command", args: []string{"help", "secret"}, wantHelp: true, wantHelpCmd: "secret"},
+		{name: "hide event", args: []string{"hide", "abc"}, wantCommand: "hide", wantArgs: []string{"abc"}},
+		{name: "add command", args: []string{"add", "Focus", "2pm-4pm", "tomorrow"}, wantCommand: "add", wantArgs: []string{"Focus", "2pm-4pm", "tomorrow"}},
 		{name: "unknown command", args: []string{"wat"}, wantErr: true},
 	}
 
@@ -109,11 +118,49 @@ func TestUsageOutput(t *testing.T) {
 	}
 }
 
+func TestDescribeEvent(t *testing.T) {
+	start := time.Date(2026, 10, 15, 14, 0, 0, 0, time.Local)
+	day := time.Date(2026, 10, 22, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		event calendar.Event
+		want  string
+	}{
+		{
+			event: calendar.Event{Summary: "Focus", Start: start, End: start.Add(2 * time.Hour)},
+			want:  `"Focus" Thu Oct 15 14:00–16:00`,
+		},
+		{
+			event: calendar.Event{Summary: "Offsite", Start: day, End: day.AddDate(0, 0, 1), AllDay: true},
+			want:  `"Offsite" Thu Oct 22 (all day)`,
+		},
+		{
+			event: calendar.Event{Summary: "Conference", Start: day, End: day.AddDate(0, 0, 3), AllDay: true},
+			want:  `"Conference" Thu Oct 22 – Sat Oct 24 (all day)`,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := describeEvent(tt.event); got != tt.want {
+			t.Errorf("describeEvent() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func newNotificationTestApp(t *testing.T, cfg config.NotificationConfig) *App {
+	t.Helper()
+	policy, err := notify.NewPolicy(cfg)
+	if err != nil {
+		t.Fatalf("NewPolicy error: %v", err)
+	}
+	return &App{cfg: &config.Config{Notifications: cfg}, policy: policy}
+}
+
 func TestNotificationTriggers_UsesEventRemindersByDefault(t *testing.T) {
 	start := time.Now().Add(2 * time.Hour).Truncate(time.Second)
 	trigger := start.Add(-15 * time.Minute)
 
-	a := &App{cfg: &config.Config{Notifications: config.NotificationConfig{Enabled: true}}}
+	a := newNotificationTestApp(t, config.NotificationConfig{Enabled: true})
 	event := calendar.Event{Start: start, NotifyAt: []time.Time{trigger, trigger}}
 
 	got := a.notificationTriggers(event)
@@ -130,7 +177,7 @@ func TestNotificationTriggers_BeforeOverridesEventReminders(t *testing.T) {
 	eventTrigger := start.Add(-30 * time.Minute)
 	override := 10 * time.Minute
 
-	a := &App{cfg: &config.Config{Notifications: config.NotificationConfig{Enabled: true, Before: []time.Duration{override}}}}
+	a := newNotificationTestApp(t, config.NotificationConfig{Enabled: true, Before: []time.Duration{override}})
 	event := calendar.Event{Start: start, NotifyAt: []time.Time{eventTrigger}}
 
 	got := a.notificationTriggers(event)
@@ -148,7 +195,7 @@ func TestNotificationTriggers_EmptyBeforeSuppressesEventReminders(t *testing.T)
 	start := time.Now().Add(2 * time.Hour).Truncate(time.Second)
 	eventTrigger := start.Add(-30 * time.Minute)
 
-	a := &App{cfg: &config.Config{Notifications: config.NotificationConfig{Enabled: true, Before: []time.Duration{}}}}
+	a := newNotificationTestApp(t, config.NotificationConfig{Enabled: true, Before: []time.Duration{}})
 	event := calendar.Event{Start: start, NotifyAt: []time.Time{eventTrigger}}
 
 	got := a.notificationTriggers(event)
@@ -156,3 +203,761 @@ func TestNotificationTriggers_EmptyBeforeSuppressesEventReminders(t *testing.T)
 		t.Fatalf("expected no triggers, got %d", len(got))
 	}
 }
+
+func TestNotificationTriggers_TransformRemindersWinOverBefore(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	transformTrigger := start.Add(-time.Hour)
+
+	a := newNotificationTestApp(t, config.NotificationConfig{Enabled: true, Before: []time.Duration{10 * time.Minute}})
+	event := calendar.Event{Start: start, NotifyAt: []time.Time{transformTrigger}, NotifySet: true}
+
+	got := a.notificationTriggers(event)
+	if len(got) != 1 || !got[0].Equal(transformTrigger) {
+		t.Fatalf("triggers = %v, want [%s]", got, transformTrigger)
+	}
+}
+
+func TestNotificationTriggers_RulesOverrideBefore(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+
+	a := newNotificationTestApp(t, config.NotificationConfig{
+		Enabled: true,
+		Before:  []time.Duration{10 * time.Minute},
+		Rules: []config.NotificationRule{
+			{Match: config.FilterRule{Field: "source", Exact: "Holidays"}, Before: []time.Duration{}},
+		},
+	})
+
+	if got := a.notificationTriggers(calendar.Event{Source: "Holidays", Start: start}); len(got) != 0 {
+		t.Fatalf("expected no triggers for Holidays, got %v", got)
+	}
+	got := a.notificationTriggers(calendar.Event{Source: "Work", Start: start})
+	if len(got) != 1 || !got[0].Equal(start.Add(-10*time.Minute)) {
+		t.Fatalf("triggers = %v, want the global 10m offset", got)
+	}
+}
+
+func TestVisibleEvents_CancelledWindow(t *testing.T) {
+	hideDeclined := true
+	window := time.Hour
+	cfg := &config.Config{
+		UI:           config.UIConfig{CancelledWindow: &window},
+		Availability: config.AvailabilityConfig{HideDeclined: &hideDeclined},
+	}
+
+	now := time.Now()
+	a := &App{cfg: cfg, events: []calendar.Event{
+		{UID: "recent", Status: calendar.StatusCancelled},
+		{UID: "old", Status: calendar.StatusCancelled},
+		{UID: "confirmed", Status: calendar.StatusConfirmed},
+		{UID: "declined", Response: calendar.PartStatDeclined},
+		{UID: "new", Status: calendar.StatusCancelled},
+	}}
+	a.cancelledSeen = map[string]time.Time{
+		"recent": now.Add(-time.Minute),
+		"old":    now.Add(-2 * time.Hour),
+		"gone":   now.Add(-time.Minute),
+	}
+	a.trackCancelled(now)
+	if _, ok := a.cancelledSeen["gone"]; ok {
+		t.Fatal("events no longer present should stop being tracked")
+	}
+
+	var got []string
+	for _, e := range a.visibleEvents() {
+		got = append(got, e.UID)
+	}
+	want := []string{"recent", "confirmed", "new"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("visibleEvents() = %v, want %v", got, want)
+	}
+
+	if a.isBusy(calendar.Event{Status: calendar.StatusCancelled}) {
+		t.Fatal("cancelled events should not count as busy")
+	}
+}
+
+func TestIsBusy_DeclinedShown(t *testing.T) {
+	hideDeclined, ignoreFree := false, true
+	window := time.Hour
+	a := newNotificationTestApp(t, config.NotificationConfig{Enabled: true, Before: []time.Duration{5 * time.Minute}})
+	a.cfg.UI.CancelledWindow = &window
+	a.cfg.Availability.HideDeclined = &hideDeclined
+	a.cfg.Availability.IgnoreFree = &ignoreFree
+
+	now := time.Now()
+	declined := calendar.Event{UID: "declined", Start: now.Add(2 * time.Minute), End: now.Add(time.Hour), Response: calendar.PartStatDeclined}
+	a.events = []calendar.Event{declined}
+
+	if got := a.visibleEvents(); len(got) != 1 {
+		t.Fatalf("visibleEvents() = %d events, want the declined event listed", len(got))
+	}
+	if a.isBusy(declined) {
+		t.Error("declined events should not count as busy")
+	}
+	if a.hasImminent(a.events, now) {
+		t.Error("a declined event should not make the tray imminent")
+	}
+}
+
+func TestSnoozeAndDismissReminders(t *testing.T) {
+	now := time.Now()
+	a := &App{
+		events: []calendar.Event{
+			{UID: "standup", Start: now.Add(10 * time.Minute), End: now.Add(25 * time.Minute)},
+			{UID: "review", Start: now.Add(20 * time.Minute), End: now.Add(time.Hour)},
+		},
+		snoozes:   make(map[string]time.Time),
+		dismissed: make(map[string]time.Time),
+	}
+
+	a.snoozeReminder("standup", time.Minute)
+	if a.takeSnooze("standup", now) {
+		t.Fatal("snooze should not be due before it ends")
+	}
+	if !a.takeSnooze("standup", now.Add(2*time.Minute)) {
+		t.Fatal("snooze should be due after it ends")
+	}
+	if a.takeSnooze("standup", now.Add(3*time.Minute)) {
+		t.Fatal("snooze should only fire once")
+	}
+
+	a.snoozeReminder("review", 5*time.Minute)
+	a.dismissReminders("review")
+	if !a.remindersDismissed("review") || a.remindersDismissed("standup") {
+		t.Fatal("only the dismissed event should have its reminders dismissed")
+	}
+	if a.takeSnooze("review", now.Add(10*time.Minute)) {
+		t.Fatal("dismissing should cancel a pending snooze")
+	}
+
+	a.pruneReminders(now.Add(30 * time.Minute))
+	if !a.remindersDismissed("review") {
+		t.Fatal("dismissal should last until the event ends")
+	}
+	a.pruneReminders(now.Add(2 * time.Hour))
+	if a.remindersDismissed("review") {
+		t.Fatal("dismissal should be forgotten once the event ends")
+	}
+}
+
+func TestCountdownNotifications(t *testing.T) {
+	a := newNotificationTestApp(t, config.NotificationConfig{Enabled: true, Countdown: true})
+	event := calendar.Event{UID: "standup", Summary: "Standup", Meeting: calendar.MeetingDetails{URL: "https://meet.google.com/abc-defg-hij"}}
+
+	actionKeys := func(n notify.Notification) string {
+		var keys []string
+		for _, action := range n.Actions {
+			keys = append(keys, action.Key)
+		}
+		return strings.Join(keys, ",")
+	}
+
+	reminder := a.reminderNotification(event, 4*time.Minute+30*time.Second)
+	if reminder.Body != "Starts in 4 minutes" {
+		t.Errorf("reminder body = %q", reminder.Body)
+	}
+	if got, want := actionKeys(reminder), "join,snooze-5m,dismiss,snooze-1m,details"; got != want {
+		t.Errorf("reminder actions = %s, want %s", got, want)
+	}
+
+	started := a.startedNotification(event)
+	if started.Body != "Started" {
+		t.Errorf("started body = %q", started.Body)
+	}
+	if got, want := actionKeys(started), "join,details"; got != want {
+		t.Errorf("started actions = %s, want %s", got, want)
+	}
+
+	a.countdowns = map[string]countdown{"standup": {id: 7}, "review": {id: 8}}
+	a.notificationClosed(7)
+	if _, ok := a.countdowns["standup"]; ok {
+		t.Error("closed countdown should be forgotten")
+	}
+	if _, ok := a.countdowns["review"]; !ok {
+		t.Error("other countdowns should be kept")
+	}
+}
+
+func TestCancelledSeenPersisted(t *testing.T) {
+	path := cancelledPath(filepath.Join(t.TempDir(), "calendar.ics"))
+	if filepath.Base(path) != "calendar.cancelled.json" {
+		t.Fatalf("cancelledPath() = %s", path)
+	}
+	if seen := loadCancelledSeen(path); seen != nil {
+		t.Fatalf("loadCancelledSeen() = %v before anything was saved", seen)
+	}
+
+	hideDeclined := true
+	window := time.Hour
+	cfg := &config.Config{
+		UI:           config.UIConfig{CancelledWindow: &window},
+		Availability: config.AvailabilityConfig{HideDeclined: &hideDeclined},
+	}
+	now := time.Now()
+	a := &App{cfg: cfg, events: []calendar.Event{{UID: "standup", Status: calendar.StatusCancelled}}}
+	if !a.trackCancelled(now.Add(-2 * time.Hour)) {
+		t.Fatal("trackCancelled() should report a newly cancelled event")
+	}
+	if a.trackCancelled(now) {
+		t.Fatal("trackCancelled() should report no change when nothing was cancelled")
+	}
+	if err := saveCancelledSeen(path, a.cancelledSeen); err != nil {
+		t.Fatal(err)
+	}
+
+	// After a restart the event stays hidden
+	restarted := &App{cfg: cfg, events: a.events, cancelledSeen: loadCancelledSeen(path)}
+	restarted.trackCancelled(now)
+	if got := restarted.visibleEvents(); len(got) != 0 {
+		t.Errorf("visibleEvents() = %v, want the cancelled event hidden after a restart", got)
+	}
+}
+
+func TestReminderActions(t *testing.T) {
+	link := calendar.MeetingDetails{URL: "https://meet.google.com/abc-defg-hij"}
+	invite := calendar.RemoteRef{ID: "id-1"}
+
+	tests := []struct {
+		name  string
+		event calendar.Event
+		max   int
+		want  string
+	}{
+		{"all actions", calendar.Event{Meeting: link, Response: calendar.PartStatNeedsAction, Remote: invite}, 0,
+			"join,snooze-5m,dismiss,accept,tentative,decline,snooze-1m,details"},
+		{"capped invitation", calendar.Event{Meeting: link, Response: calendar.PartStatNeedsAction, Remote: invite}, 3,
+			"join,snooze-5m,dismiss"},
+		{"responses fit", calendar.Event{Response: calendar.PartStatNeedsAction, Remote: invite}, 5,
+			"snooze-5m,dismiss,accept,tentative,decline"},
+		{"responses do not fit", calendar.Event{Response: calendar.PartStatNeedsAction, Remote: invite}, 4,
+			"snooze-5m,dismiss,snooze-1m,details"},
+		{"capped without link", calendar.Event{}, 3, "snooze-5m,dismiss,snooze-1m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			for _, action := range reminderActions(tt.event, tt.max) {
+				keys = append(keys, action.Key)
+			}
+			if got := strings.Join(keys, ","); got != tt.want {
+				t.Errorf("reminderActions() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueHooks(t *testing.T) {
+	enabled := true
+	a := newNotificationTestApp(t, config.NotificationConfig{Enabled: true, Before: []time.Duration{5 * time.Minute}})
+	a.cfg.Availability.IgnoreFree = &enabled
+	a.hooksRun = make(map[string]time.Time)
+	a.hooksStarted = make(map[string]calendar.Event)
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	events := []calendar.Event{
+		{UID: "review", Summary: "Review", Start: now.Add(-time.Hour), End: now},
+		{UID: "standup", Summary: "Standup", Start: now, End: now.Add(15 * time.Minute)},
+		{UID: "planning", Summary: "Planning", Start: now.Add(5 * time.Minute), End: now.Add(time.Hour)},
+		{UID: "old", Summary: "Old", Start: now.Add(-2 * time.Hour), End: now.Add(-10 * time.Minute)},
+		{UID: "cancelled", Summary: "Cancelled", Start: now, End: now.Add(time.Hour), Status: calendar.StatusCancelled},
+	}
+
+	describe := func(runs []hookRun) string {
+		var out []string
+		for _, r := range runs {
+			out = append(out, string(r.kind)+" "+r.event.UID)
+		}
+		return strings.Join(out, ", ")
+	}
+
+	// The standup reminder was due 5 minutes ago, too late to run, and the
+	// review ended without its start hook having run
+	want := "reminder planning, start standup"
+	if got := describe(a.dueHooks(events, now)); got != want {
+		t.Errorf("dueHooks() = %s, want %s", got, want)
+	}
+	if got := describe(a.dueHooks(events, now.Add(time.Minute))); got != "" {
+		t.Errorf("dueHooks() a minute later = %s, want nothing", got)
+	}
+	if got := describe(a.dueHooks(events, now.Add(5*time.Minute))); got != "start planning" {
+		t.Errorf("dueHooks() at the next start = %s, want start planning", got)
+	}
+
+	// Planning is cancelled after it started: its end hook runs right away
+	cancelled := slices.Clone(events)
+	cancelled[2].Status = calendar.StatusCancelled
+	if got := describe(a.dueHooks(cancelled, now.Add(10*time.Minute))); got != "end planning" {
+		t.Errorf("dueHooks() after cancelling a started event = %s, want end planning", got)
+	}
+
+	// Standup ended while the machine slept, and planning does not end twice
+	if got := describe(a.dueHooks(events, now.Add(3*time.Hour))); got != "end standup" {
+		t.Errorf("dueHooks() after sleeping = %s, want end standup", got)
+	}
+
+	if got := a.untilNextHook(events, now.Add(time.Minute)); got != hookCheckInterval {
+		t.Errorf("untilNextHook() = %s, want %s", got, hookCheckInterval)
+	}
+	if got := a.untilNextHook(events, now.Add(4*time.Minute+50*time.Second)); got != 10*time.Second {
+		t.Errorf("untilNextHook() before the next start = %s, want 10s", got)
+	}
+
+	// All-day events run no hooks
+	day := time.Date(2026, 3, 3, 0, 0, 0, 0, time.Local)
+	holiday := []calendar.Event{{UID: "holiday", Summary: "Holiday", Start: day, End: day.AddDate(0, 0, 1), AllDay: true}}
+	if got := describe(a.dueHooks(holiday, day)); got != "" {
+		t.Errorf("dueHooks() for an all-day event = %s, want nothing", got)
+	}
+	if got := a.untilNextHook(holiday, day.Add(-10*time.Second)); got != hookCheckInterval {
+		t.Errorf("untilNextHook() before an all-day event = %s, want %s", got, hookCheckInterval)
+	}
+}
+
+func TestWantDND(t *testing.T) {
+	enabled := true
+	cfg := &config.Config{Availability: config.AvailabilityConfig{IgnoreFree: &enabled}}
+	match, err := filter.Compile(config.FilterRule{Expr: "has_meeting"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &App{cfg: cfg, dndMatch: match}
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	meeting := calendar.MeetingDetails{URL: "https://meet.google.com/abc-defg-hij"}
+	tests := []struct {
+		name  string
+		event calendar.Event
+		want  bool
+	}{
+		{"meeting in progress", calendar.Event{Start: now.Add(-time.Minute), End: now.Add(time.Hour), Meeting: meeting}, true},
+		{"meeting starting now", calendar.Event{Start: now, End: now.Add(time.Hour), Meeting: meeting}, true},
+		{"meeting ended", calendar.Event{Start: now.Add(-time.Hour), End: now, Meeting: meeting}, false},
+		{"upcoming meeting", calendar.Event{Start: now.Add(time.Minute), End: now.Add(time.Hour), Meeting: meeting}, false},
+		{"no meeting link", calendar.Event{Start: now.Add(-time.Minute), End: now.Add(time.Hour)}, false},
+		{"all day", calendar.Event{Start: now.Add(-time.Hour), End: now.Add(time.Hour), AllDay: true, Meeting: meeting}, false},
+		{"cancelled", calendar.Event{Start: now.Add(-time.Minute), End: now.Add(time.Hour), Meeting: meeting, Status: calendar.StatusCancelled}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.wantDND([]calendar.Event{tt.event}, now); got != tt.want {
+				t.Errorf("wantDND() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	events := []calendar.Event{{Start: now.Add(10 * time.Second), End: now.Add(time.Hour), Meeting: meeting}}
+	if got := a.untilNextDNDChange(events, now); got != 10*time.Second {
+		t.Errorf("untilNextDNDChange() = %s, want 10s", got)
+	}
+}
+
+func TestTrackConflicts(t *testing.T) {
+	hideDeclined := true
+	window := time.Hour
+	cfg := &config.Config{
+		Sync:          config.SyncConfig{TimeRange: 14 * 24 * time.Hour},
+		UI:            config.UIConfig{CancelledWindow: &window},
+		Availability:  config.AvailabilityConfig{HideDeclined: &hideDeclined},
+		Notifications: config.NotificationConfig{Conflicts: 3 * 24 * time.Hour},
+	}
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local)
+	event := func(uid string, start time.Duration) calendar.Event {
+		return calendar.Event{UID: uid, Summary: uid, Start: now.Add(start), End: now.Add(start + time.Hour)}
+	}
+
+	a := &App{cfg: cfg, events: []calendar.Event{event("standup", time.Hour), event("review", 90*time.Minute)}}
+	if got := a.trackConflicts(now); len(got) != 0 {
+		t.Fatalf("first sync reported %d conflicts, want none", len(got))
+	}
+
+	// A sync adds a conflict tomorrow and another next week
+	a.events = append(a.events,
+		event("1:1", 25*time.Hour), event("planning", 25*time.Hour+30*time.Minute),
+		event("offsite", 7*24*time.Hour), event("retro", 7*24*time.Hour),
+	)
+	got := a.trackConflicts(now)
+	if len(got) != 1 || got[0].A.UID != "1:1" || got[0].B.UID != "planning" {
+		t.Fatalf("trackConflicts() = %v, want 1:1/planning", got)
+	}
+	if got := a.trackConflicts(now); len(got) != 0 {
+		t.Fatalf("unchanged sync reported %d conflicts, want none", len(got))
+	}
+
+	notif := conflictNotification(got[0], now)
+	if want := "Tue 9:30 AM: planning overlaps 1:1"; notif.Body != want {
+		t.Errorf("notification body = %q, want %q", notif.Body, want)
+	}
+}
+
+func TestExplainSnapshot(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 30, 0, 0, time.Local)
+	cfg := &config.Config{
+		Sources: []config.SourceConfig{{
+			Name:    "Work",
+			Filters: config.FilterConfig{Rules: []config.FilterRule{{Field: "title", Contains: "OOO", Exclude: true}}},
+		}},
+		Filters: config.FilterConfig{Rules: []config.FilterRule{{Field: "title", Contains: "standup", CaseInsensitive: true}}},
+	}
+	events := []calendar.Event{
+		{Summary: "Daily Standup", Source: "Work/Team", Start: start, End: start.Add(15 * time.Minute)},
+		{Summary: "OOO", Source: "Work", Start: start, End: start.Add(time.Hour)},
+		{Summary: "Lunch", Source: "Home", Start: start, End: start.Add(time.Hour)},
+	}
+
+	explanations, err := explainSnapshot(cfg, events)
+	if err != nil {
+		t.Fatalf("explainSnapshot error: %v", err)
+	}
+
+	adhoc, err := filter.Compile(config.FilterRule{Expr: `source == Home`})
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+
+	var out bytes.Buffer
+	printExplanations(&out, explanations, adhoc)
+	want := `+ "Daily Standup" Mon Oct 19 09:30–09:45 [Work/Team]
+    source filter: included: no exclude rule matched
+    global filter: included by rule 0: title contains "standup" (case-insensitive)
+    -rule: no match
+- "OOO" Mon Oct 19 09:30–10:30 [Work]
+    source filter: excluded by rule 0: title contains "OOO"
+    -rule: no match
+- "Lunch" Mon Oct 19 09:30–10:30 [Home]
+    source filter: included: no rules
+    global filter: not included: matched no include rule
+    -rule: matches
+
+3 events: 1 included, 2 excluded, 1 matching -rule
+`
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestAgenda(t *testing.T) {
+	hideDeclined := true
+	window := time.Hour
+	cfg := &config.Config{
+		UI:           config.UIConfig{CancelledWindow: &window},
+		Availability: config.AvailabilityConfig{HideDeclined: &hideDeclined},
+	}
+
+	from := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+	to := from.Add(24 * time.Hour)
+	a := &App{cfg: cfg, events: []calendar.Event{
+		{UID: "ended", Start: from.Add(-2 * time.Hour), End: from.Add(-time.Hour)},
+		{UID: "ongoing", Start: from.Add(-30 * time.Minute), End: from.Add(30 * time.Minute)},
+		{UID: "hidden", Start: from.Add(time.Hour), End: from.Add(2 * time.Hour)},
+		{UID: "declined", Start: from.Add(time.Hour), End: from.Add(2 * time.Hour), Response: calendar.PartStatDeclined},
+		{UID: "meeting", Start: from.Add(2 * time.Hour), End: from.Add(3 * time.Hour), Location: "https://zoom.us/j/123"},
+		{UID: "later", Start: to, End: to.Add(time.Hour)},
+	}}
+	a.hiddenEntries = []hiddenEntry{{uid: "hidden", hidden: from}}
+
+	got := a.agenda(from, to)
+	var uids []string
+	for _, e := range got {
+		uids = append(uids, e.UID)
+	}
+	if want := "ongoing,meeting"; strings.Join(uids, ",") != want {
+		t.Fatalf("agenda() = %v, want %s", uids, want)
+	}
+	if got[1].MeetingURL != "https://zoom.us/j/123" || got[1].MeetingService != "Zoom" {
+		t.Fatalf("meeting = %q (%q), want the detected Zoom link", got[1].MeetingURL, got[1].MeetingService)
+	}
+}
+
+func TestEventByRef(t *testing.T) {
+	hideDeclined := true
+	window := time.Hour
+	cfg := &config.Config{
+		UI:           config.UIConfig{CancelledWindow: &window},
+		Availability: config.AvailabilityConfig{HideDeclined: &hideDeclined},
+	}
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+	a := &App{cfg: cfg, events: []calendar.Event{
+		{UID: "review", Summary: "Review", Start: now.Add(-time.Hour), End: now},
+		{UID: "standup", Summary: "Standup", Start: now.Add(-15 * time.Minute), End: now.Add(15 * time.Minute)},
+		{UID: "3", Summary: "Numbered", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+		{UID: "lunch", Summary: "Lunch", Start: now.Add(3 * time.Hour), End: now.Add(4 * time.Hour)},
+	}}
+
+	tests := []struct {
+		ref  string
+		want string
+		err  string
+	}{
+		{ref: "review", want: "review"},
+		{ref: "1", want: "standup"},
+		{ref: "2", want: "3"},
+		{ref: "3", want: "3"}, // A UID wins over an agenda number
+		{ref: "4", err: "no event 4 in the agenda"},
+		{ref: "nope", err: `no event with UID "nope"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			e, err := a.eventByRef(tt.ref, now)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("eventByRef(%q) error = %v, want %q", tt.ref, err, tt.err)
+				}
+				return
+			}
+			if err != nil || e.UID != tt.want {
+				t.Fatalf("eventByRef(%q) = %q, %v, want %q", tt.ref, e.UID, err, tt.want)
+			}
+		})
+	}
+
+	a.notifier = nil
+	if _, err := a.snoozeEvent(a.events[1], 5*time.Minute, now); err == nil || err.Error() != "notifications are disabled" {
+		t.Errorf("snoozeEvent() without notifications error = %v", err)
+	}
+}
+
+func TestPrintAgenda(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+	events := []agendaEvent{
+		{Summary: "Standup", Start: now.Add(-15 * time.Minute), End: now.Add(15 * time.Minute), MeetingURL: "https://meet.google.com/abc"},
+		{Summary: "Lunch", Start: now.Add(3 * time.Hour), End: now.Add(4 * time.Hour), Location: "Cafe"},
+		{Summary: "Offsite", Start: time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local), End: time.Date(2026, 10, 21, 0, 0, 0, 0, time.Local), AllDay: true},
+	}
+
+	var buf bytes.Buffer
+	printAgenda(&buf, events, now)
+	want := "Mon Oct 19\n" +
+		"  1  08:45–09:15  Standup  https://meet.google.com/abc\n" +
+		"  2  12:00–13:00  Lunch  (Cafe)\n" +
+		"\n" +
+		"Tue Oct 20\n" +
+		"  3  all day      Offsite\n"
+	if buf.String() != want {
+		t.Fatalf("printAgenda() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	ignoreFree := true
+	cfg := &config.Config{
+		UI:           config.UIConfig{TimeRange: 7 * 24 * time.Hour, EventEndGrace: 5 * time.Minute},
+		Availability: config.AvailabilityConfig{IgnoreFree: &ignoreFree},
+	}
+	a := &App{cfg: cfg}
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+
+	standup := calendar.Event{Summary: "Standup", Start: now.Add(210 * time.Second), End: now.Add(15 * time.Minute)}
+	review := calendar.Event{Summary: "Review", Start: now.Add(-15 * time.Minute), End: now.Add(45 * time.Minute)}
+	lunch := calendar.Event{Summary: "Lunch", Start: now.Add(3 * time.Hour), End: now.Add(4 * time.Hour), FreeBusy: calendar.FreeBusyFree}
+	offsite := calendar.Event{Summary: "Offsite", Start: now.Add(24 * time.Hour), End: now.Add(48 * time.Hour), AllDay: true}
+
+	tests := []struct {
+		name       string
+		events     []calendar.Event
+		stale      bool
+		text       string
+		tooltip    string
+		class      string
+		percentage int
+	}{
+		{
+			name:    "imminent",
+			events:  []calendar.Event{standup, lunch, offsite},
+			text:    "Standup in 4m",
+			tooltip: "9:03 AM  Standup\n12:00 PM  Lunch",
+			class:   "imminent",
+		},
+		{
+			name:       "ongoing",
+			events:     []calendar.Event{review, standup},
+			text:       "Review now",
+			tooltip:    "8:45 AM  Review\n9:03 AM  Standup",
+			class:      "ongoing",
+			percentage: 25,
+		},
+		{
+			name:    "later",
+			events:  []calendar.Event{lunch},
+			text:    "Lunch at 12:00 PM",
+			tooltip: "12:00 PM  Lunch",
+		},
+		{
+			name:    "stale",
+			events:  []calendar.Event{standup},
+			stale:   true,
+			text:    "Standup in 4m",
+			tooltip: "9:03 AM  Standup\nEvents may be out of date",
+			class:   "stale",
+		},
+		{
+			name:    "nothing",
+			events:  []calendar.Event{offsite},
+			tooltip: "No upcoming events",
+		},
+		{
+			name: "conflict",
+			events: []calendar.Event{
+				{UID: "review", Summary: "Review", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+				{UID: "1:1", Summary: "1:1", Start: now.Add(90 * time.Minute), End: now.Add(2 * time.Hour)},
+			},
+			text:    "Review at 10:00 AM",
+			tooltip: "10:00 AM  Review\n10:30 AM  1:1\n\n1 conflict\n10:30 AM  Review / 1:1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := a.statusFor(tt.events, tt.stale, now)
+			if got.Text != tt.text || got.Tooltip != tt.tooltip || got.Class != tt.class || got.Percentage != tt.percentage {
+				t.Fatalf("statusFor() = %+v, want text %q, tooltip %q, class %q, percentage %d", got, tt.text, tt.tooltip, tt.class, tt.percentage)
+			}
+		})
+	}
+}
+
+func TestFormatBarStatus(t *testing.T) {
+	s := barStatus{Text: "R&D sync in 4m", Summary: "R&D sync", Tooltip: "9:03 AM  R&D sync", Class: "imminent"}
+
+	got := formatBarStatus("waybar", s, false)
+	want := `{"text":"R&amp;D sync in 4m","alt":"imminent","tooltip":"9:03 AM  R&amp;D sync","class":"imminent","percentage":0}`
+	if got != want {
+		t.Errorf("waybar = %s, want %s", got, want)
+	}
+	if got := formatBarStatus("polybar", s, true); got != "R&D sync in 4m" {
+		t.Errorf("polybar = %q", got)
+	}
+	if got := formatBarStatus("i3blocks", s, true); got != "R&D sync in 4m\nR&D sync" {
+		t.Errorf("i3blocks = %q", got)
+	}
+	if got := formatBarStatus("i3blocks", s, false); got != "R&D sync in 4m" {
+		t.Errorf("i3blocks -follow = %q", got)
+	}
+}
+
+func TestControlProperties(t *testing.T) {
+	cfg := &config.Config{UI: config.UIConfig{TimeRange: 24 * time.Hour, EventEndGrace: 5 * time.Minute}}
+	a := &App{cfg: cfg}
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+	events := []calendar.Event{
+		{UID: "ongoing", Summary: "Review", Start: now.Add(-15 * time.Minute), End: now.Add(15 * time.Minute)},
+		{UID: "next", Summary: "Standup", Start: now.Add(30 * time.Minute), End: now.Add(45 * time.Minute), Location: "https://meet.google.com/abc-defg-hij"},
+		{UID: "later", Summary: "Lunch", Start: now.Add(3 * time.Hour), End: now.Add(4 * time.Hour)},
+	}
+
+	props := a.controlProperties(events, true, nil, now.Add(-time.Minute), now)
+
+	next := props["NextEvent"].(map[string]dbus.Variant)
+	if got := next["uid"].Value(); got != "next" {
+		t.Errorf("NextEvent uid = %v, want next", got)
+	}
+	if got := next["start"].Value(); got != now.Add(30*time.Minute).Unix() {
+		t.Errorf("NextEvent start = %v", got)
+	}
+	if got := next["meeting_service"].Value(); got != "Meet" {
+		t.Errorf("NextEvent meeting_service = %v, want Meet", got)
+	}
+
+	ongoing := props["OngoingEvents"].([]map[string]dbus.Variant)
+	if len(ongoing) != 1 || ongoing[0]["uid"].Value() != "ongoing" {
+		t.Errorf("OngoingEvents = %v, want the ongoing review", ongoing)
+	}
+	if props["Stale"] != true {
+		t.Errorf("Stale = %v, want true", props["Stale"])
+	}
+	if props["LastSync"] != now.Add(-time.Minute).Unix() {
+		t.Errorf("LastSync = %v", props["LastSync"])
+	}
+	if errs := props["SyncErrors"].([]string); errs == nil || len(errs) != 0 {
+		t.Errorf("SyncErrors = %#v, want an empty list", errs)
+	}
+
+	empty := a.controlProperties(nil, false, nil, time.Time{}, now)
+	if len(empty["NextEvent"].(map[string]dbus.Variant)) != 0 || empty["LastSync"] != int64(0) {
+		t.Errorf("controlProperties(nil) = %v, want no next event and LastSync 0", empty)
+	}
+}
+
+func TestJoinTarget(t *testing.T) {
+	hideDeclined := true
+	window := time.Hour
+	cfg := &config.Config{
+		UI:           config.UIConfig{TimeRange: 24 * time.Hour, EventEndGrace: 5 * time.Minute, CancelledWindow: &window},
+		Availability: config.AvailabilityConfig{HideDeclined: &hideDeclined},
+	}
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+	review := calendar.Event{UID: "review", Start: now.Add(-30 * time.Minute), End: now.Add(30 * time.Minute), Location: "https://zoom.us/j/1"}
+	focus := calendar.Event{UID: "focus", Start: now.Add(-10 * time.Minute), End: now.Add(time.Hour)}
+	standup := calendar.Event{UID: "standup", Start: now.Add(3 * time.Minute), End: now.Add(15 * time.Minute), Meeting: calendar.MeetingDetails{URL: "https://meet.google.com/abc"}}
+	later := calendar.Event{UID: "later", Start: now.Add(2 * time.Hour), End: now.Add(3 * time.Hour), Location: "https://zoom.us/j/2"}
+
+	tests := []struct {
+		name    string
+		events  []calendar.Event
+		which   string
+		want    string
+		wantErr bool
+	}{
+		{name: "about to start wins", events: []calendar.Event{review, focus, standup}, want: "standup"},
+		{name: "in progress", events: []calendar.Event{review, focus, later}, want: "review"},
+		{name: "falls back to next", events: []calendar.Event{focus, later}, want: "later"},
+		{name: "current", events: []calendar.Event{review, focus, standup}, which: "current", want: "review"},
+		{name: "next", events: []calendar.Event{review, later}, which: "next", want: "later"},
+		{name: "no current meeting", events: []calendar.Event{focus, standup}, which: "current", wantErr: true},
+		{name: "nothing to join", events: []calendar.Event{focus}, wantErr: true},
+		{name: "unknown", events: []calendar.Event{review}, which: "previous", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &App{cfg: cfg, events: tt.events}
+			got, err := a.joinTarget(tt.which, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("joinTarget() = %q, want error", got.UID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("joinTarget() error: %v", err)
+			}
+			if got.UID != tt.want {
+				t.Fatalf("joinTarget() = %q, want %q", got.UID, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatEventDetails(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+	event := calendar.Event{
+		UID:         "abc",
+		Summary:     "Standup",
+		Start:       start,
+		End:         start.Add(15 * time.Minute),
+		Location:    "Room 1",
+		Description: "Daily sync\n",
+		Organizer:   "lead@example.com",
+		Source:      "Work",
+		URL:         "https://calendar.example.com/event/abc",
+		Meeting:     calendar.MeetingDetails{URL: "https://zoom.us/j/123", ID: "123"},
+		Tags:        []string{"team"},
+	}
+
+	got := formatEventDetails(event, []time.Duration{})
+	want := "\"Standup\" Mon Oct 19 09:00–09:15\n" +
+		"Location:      Room 1\n" +
+		"Meeting:       https://zoom.us/j/123 (Zoom)\n" +
+		"Meeting ID:    123\n" +
+		"Organizer:     lead@example.com\n" +
+		"Reminders:     Notifications disabled for this event by config override\n" +
+		"Tags:          team\n" +
+		"Source:        Work\n" +
+		"Web link:      https://calendar.example.com/event/abc\n" +
+		"UID:           abc\n" +
+		"\n" +
+		"Daily sync\n"
+	if got != want {
+		t.Fatalf("formatEventDetails() =\n%s\nwant:\n%s", got, want)
+	}
+}
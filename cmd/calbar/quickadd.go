@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/notify"
+	"github.com/cpuguy83/calbar/internal/quickadd"
+	"github.com/godbus/dbus/v5"
+)
+
+// quickAddTimeout bounds how long creating an event may take. It stays below
+// the default D-Bus call timeout so "calbar add" gets a real error back.
+const quickAddTimeout = 20 * time.Second
+
+// runAddCommand implements "calbar add <text>".
+func runAddCommand(cli cliOptions) error {
+	fs := flag.NewFlagSet("calbar add", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "")
+	if err := fs.Parse(cli.commandArgs); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+
+	text := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(text) == "" {
+		return usageErrorf("expected event text")
+	}
+
+	if *dryRun {
+		cfg, _, err := loadCLIConfig(cli.configPath)
+		if err != nil {
+			return err
+		}
+		event, err := quickadd.Parse(text, time.Now(), cfg.QuickAdd.Duration)
+		if err != nil {
+			return err
+		}
+		fmt.Println(describeEvent(event))
+		return nil
+	}
+
+	desc, err := sendAddEvent(text)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Added " + desc)
+	return nil
+}
+
+// quickAdd parses text into an event, creates it in the configured quick-add
+// source and shows it right away, ahead of the next sync.
+func (a *App) quickAdd(ctx context.Context, text string) (calendar.Event, error) {
+	if a.cfg.QuickAdd.Source == "" {
+		return calendar.Event{}, fmt.Errorf("quick_add.source is not configured")
+	}
+
+	event, err := quickadd.Parse(text, time.Now(), a.cfg.QuickAdd.Duration)
+	if err != nil {
+		return calendar.Event{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, quickAddTimeout)
+	defer cancel()
+
+	created, err := a.syncer.CreateEvent(ctx, a.cfg.QuickAdd.Source, a.cfg.QuickAdd.Calendar, event)
+	if err != nil {
+		return calendar.Event{}, fmt.Errorf("create event in %s: %w", a.cfg.QuickAdd.Source, err)
+	}
+
+	a.mu.Lock()
+	a.events = calendar.Merge(a.events, []calendar.Event{created})
+	a.mu.Unlock()
+	a.scheduleUIUpdate()
+
+	return created, nil
+}
+
+// quickAddFromUI runs a quick-add from the popup in the background and
+// reports failures as a notification.
+func (a *App) quickAddFromUI(text string) {
+	go func() {
+		if _, err := a.quickAdd(a.ctx, text); err != nil {
+			slog.Warn("quick-add failed", "text", text, "error", err)
+			if a.notifier != nil {
+				a.notifier.Send(notify.Notification{
+					Summary: "Could not add event",
+					Body:    err.Error(),
+					Urgency: notify.UrgencyNormal,
+				})
+			}
+		}
+	}()
+}
+
+// describeEvent returns a one-line summary of an event's title and time.
+func describeEvent(event calendar.Event) string {
+	start := event.Start.Local()
+	if event.AllDay {
+		days := int(event.End.Sub(event.Start).Round(time.Hour) / (24 * time.Hour))
+		if days > 1 {
+			return fmt.Sprintf("%q %s – %s (all day)", event.Summary, start.Format("Mon Jan 2"), event.End.AddDate(0, 0, -1).Local().Format("Mon Jan 2"))
+		}
+		return fmt.Sprintf("%q %s (all day)", event.Summary, start.Format("Mon Jan 2"))
+	}
+	return fmt.Sprintf("%q %s %s–%s", event.Summary, start.Format("Mon Jan 2"), start.Format("15:04"), event.End.Local().Format("15:04"))
+}
+
+// sendAddEvent asks the running instance to quick-add an event and returns its
+// description.
+func sendAddEvent(text string) (string, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return "", fmt.Errorf("connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	var desc string
+	obj := conn.Object(controlBusName, dbus.ObjectPath(controlPath))
+	if err := obj.Call(controlInterface+".AddEvent", 0, text).Store(&desc); err != nil {
+		return "", fmt.Errorf("add event: %w", err)
+	}
+	return desc, nil
+}
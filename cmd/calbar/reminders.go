@@ -0,0 +1,119 @@
+package main
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/links"
+)
+
+// snoozeDurations maps notification snooze actions to how long they put off
+// the reminder.
+var snoozeDurations = map[string]time.Duration{
+	"snooze-1m": time.Minute,
+	"snooze-5m": 5 * time.Minute,
+}
+
+// handleNotificationAction handles an action button clicked on a reminder.
+func (a *App) handleNotificationAction(id uint32, actionKey string) {
+	a.mu.RLock()
+	target, ok := a.notificationIDs[id]
+	a.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	if d, ok := snoozeDurations[actionKey]; ok {
+		a.snoozeReminder(target.uid, d)
+		return
+	}
+	if status, ok := notificationResponses[actionKey]; ok {
+		a.respondToEvent(target.uid, status)
+		return
+	}
+	switch actionKey {
+	case "join":
+		if target.meetingLink != "" {
+			slog.Debug("opening meeting from notification", "url", target.meetingLink)
+			links.Open(target.meetingLink)
+			if e, err := a.eventByUID(target.uid); err == nil {
+				a.runJoinHooks(e)
+			}
+		}
+		a.closeCountdown(target.uid)
+	case "details":
+		a.ui.ShowDetails(target.uid)
+	case "dismiss":
+		a.dismissReminders(target.uid)
+		a.closeCountdown(target.uid)
+	}
+}
+
+// snoozeReminder reminds about an event again after d.
+func (a *App) snoozeReminder(uid string, d time.Duration) {
+	slog.Debug("snoozing reminder", "uid", uid, "for", d)
+	a.mu.Lock()
+	a.snoozes[uid] = time.Now().Add(d)
+	delete(a.dismissed, uid)
+	a.mu.Unlock()
+
+	time.AfterFunc(d, a.wakeNotifications)
+}
+
+// dismissReminders suppresses the remaining reminders for an event, including
+// a pending snooze. The event stays listed.
+func (a *App) dismissReminders(uid string) {
+	slog.Debug("dismissing reminders", "uid", uid)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	until := time.Now().Add(24 * time.Hour)
+	if i := slices.IndexFunc(a.events, func(e calendar.Event) bool { return e.UID == uid }); i >= 0 {
+		until = a.events[i].End
+	}
+	a.dismissed[uid] = until
+	delete(a.snoozes, uid)
+}
+
+// takeSnooze reports whether a snoozed reminder for the event is due, and if
+// so clears it.
+func (a *App) takeSnooze(uid string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	at, ok := a.snoozes[uid]
+	if !ok || at.After(now) {
+		return false
+	}
+	delete(a.snoozes, uid)
+	return true
+}
+
+// remindersDismissed reports whether the user dismissed the event's
+// reminders.
+func (a *App) remindersDismissed(uid string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	_, ok := a.dismissed[uid]
+	return ok
+}
+
+// pruneReminders forgets snoozes that are long overdue, which happens when
+// the event was removed or hidden, and dismissals of events that have ended.
+func (a *App) pruneReminders(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for uid, at := range a.snoozes {
+		if at.Before(now.Add(-24 * time.Hour)) {
+			delete(a.snoozes, uid)
+		}
+	}
+	for uid, until := range a.dismissed {
+		if until.Before(now) {
+			delete(a.dismissed, uid)
+		}
+	}
+}
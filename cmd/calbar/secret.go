@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/secret"
+)
+
+// runSecretCommand implements "calbar secret set <source>".
+func runSecretCommand(cli cliOptions) error {
+	fs := flag.NewFlagSet("calbar secret", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	help := addHelpFlags(fs)
+	if err := fs.Parse(cli.commandArgs); err != nil {
+		return usageError{err}
+	}
+	if *help {
+		return errHelp
+	}
+
+	args := fs.Args()
+	if len(args) == 0 || args[0] != "set" {
+		return usageErrorf("expected \"set <source>\"")
+	}
+	if len(args) != 2 {
+		return usageErrorf("set takes exactly one source name")
+	}
+	name := args[1]
+
+	cfg, _, err := loadCLIConfig(cli.configPath)
+	if err != nil {
+		return err
+	}
+	src, ok := findSource(cfg, name)
+	if !ok {
+		return fmt.Errorf("no source named %q in config", name)
+	}
+	if !src.Keyring {
+		fmt.Fprintf(os.Stderr, "note: source %q does not set \"keyring: true\"; add it to use the stored password\n", name)
+	}
+
+	password, err := readSecret(fmt.Sprintf("Password for %s: ", name))
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return fmt.Errorf("empty password")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	label := fmt.Sprintf("CalBar password for %s", name)
+	if err := secret.Set(ctx, label, secret.SourceAttributes(name, "password"), password); err != nil {
+		return fmt.Errorf("store password: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Stored password for %q in the secret service\n", name)
+	return nil
+}
+
+// readSecret reads a single line from stdin. When stdin is a terminal the prompt
+// is shown and echo is disabled while typing.
+func readSecret(prompt string) (string, error) {
+	if isTerminal(os.Stdin) {
+		fmt.Fprint(os.Stderr, prompt)
+		if setEcho(false) == nil {
+			defer func() {
+				setEcho(true)
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// setEcho toggles terminal echo on stdin using stty.
+func setEcho(on bool) error {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
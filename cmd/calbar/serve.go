@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/server"
+)
+
+// startServer starts the HTTP server if one is configured. The server
+// serves the same events as the UI, in the GetEvents JSON format.
+func (a *App) startServer() error {
+	if a.cfg.Server.Listen == "" {
+		return nil
+	}
+	srv, err := server.New(a.cfg.Server, server.Options{
+		Events: func() []calendar.Event {
+			a.mu.RLock()
+			defer a.mu.RUnlock()
+			return slices.Clone(a.visibleEvents())
+		},
+		EncodeJSON: func(events []calendar.Event) ([]byte, error) {
+			return json.Marshal(newAgendaEvents(events))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := srv.Start(); err != nil {
+		return err
+	}
+	a.server = srv
+	return nil
+}
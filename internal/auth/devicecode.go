@@ -12,6 +12,7 @@ import (
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/cpuguy83/calbar/internal/secret"
 )
 
 // DeviceCodeAuth provides authentication via device code flow.
@@ -32,17 +33,10 @@ func NewDeviceCodeAuth(clientID string, scopes []string) (*DeviceCodeAuth, error
 		clientID = DefaultClientID
 	}
 
-	// Set up cache file
-	cacheFile, err := getCacheFilePath()
-	if err != nil {
-		slog.Warn("could not determine cache file path", "error", err)
-	}
-
 	var opts []public.Option
 	opts = append(opts, public.WithAuthority(DefaultAuthority))
 
-	if cacheFile != "" {
-		accessor := &tokenCacheAccessor{path: cacheFile}
+	if accessor := newTokenCacheAccessor(); accessor != nil {
 		opts = append(opts, public.WithCache(accessor))
 	}
 
@@ -141,7 +135,75 @@ func (d *DeviceCodeAuth) Close() error {
 	return nil
 }
 
-// tokenCacheAccessor implements cache.ExportReplace for MSAL token caching.
+// msalCacheAttributes identifies the MSAL token cache item in the Secret Service.
+var msalCacheAttributes = map[string]string{
+	"application": secret.Application,
+	"type":        "msal-token-cache",
+}
+
+// newTokenCacheAccessor returns the token cache storage to use.
+// The Secret Service is preferred; a file in the user cache directory is used
+// when no Secret Service is running.
+func newTokenCacheAccessor() cache.ExportReplace {
+	cacheFile, err := getCacheFilePath()
+	if err != nil {
+		slog.Warn("could not determine cache file path", "error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := secret.Connect(ctx)
+	if err == nil {
+		c.Close()
+		slog.Debug("storing MSAL token cache in secret service")
+		return &keyringCacheAccessor{legacyPath: cacheFile}
+	}
+	slog.Debug("secret service not available, storing MSAL token cache in file", "error", err)
+
+	if cacheFile == "" {
+		return nil
+	}
+	return &tokenCacheAccessor{path: cacheFile}
+}
+
+// keyringCacheAccessor implements cache.ExportReplace backed by the Secret Service.
+// A token cache file written by older versions is imported on first use and
+// removed once the cache has been stored in the keyring.
+type keyringCacheAccessor struct {
+	legacyPath string
+}
+
+func (k *keyringCacheAccessor) Replace(ctx context.Context, cache cache.Unmarshaler, hints cache.ReplaceHints) error {
+	data, err := secret.Get(ctx, msalCacheAttributes)
+	if errors.Is(err, secret.ErrNotFound) && k.legacyPath != "" {
+		return (&tokenCacheAccessor{path: k.legacyPath}).Replace(ctx, cache, hints)
+	}
+	if err != nil {
+		return fmt.Errorf("read token cache from secret service: %w", err)
+	}
+	return cache.Unmarshal([]byte(data))
+}
+
+func (k *keyringCacheAccessor) Export(ctx context.Context, cache cache.Marshaler, hints cache.ExportHints) error {
+	data, err := cache.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := secret.Set(ctx, "CalBar Microsoft 365 token cache", msalCacheAttributes, string(data)); err != nil {
+		return fmt.Errorf("write token cache to secret service: %w", err)
+	}
+
+	if k.legacyPath != "" {
+		if err := os.Remove(k.legacyPath); err == nil {
+			slog.Info("migrated MSAL token cache to secret service", "path", k.legacyPath)
+		}
+	}
+	return nil
+}
+
+// tokenCacheAccessor implements cache.ExportReplace for MSAL token caching in a file.
 type tokenCacheAccessor struct {
 	path string
 }
@@ -1,9 +1,16 @@
 package calendar
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -51,10 +58,9 @@ func (s *CalDAVSource) Name() string {
 	return s.name
 }
 
-// Fetch retrieves events from the CalDAV server.
-func (s *CalDAVSource) Fetch(ctx context.Context, end time.Time) ([]Event, error) {
-	// Create HTTP client with basic auth
-	httpClient := &http.Client{
+// httpClient returns an HTTP client that authenticates with the source credentials.
+func (s *CalDAVSource) httpClient() *http.Client {
+	return &http.Client{
 		Timeout: 60 * time.Second,
 		Transport: &basicAuthTransport{
 			username: s.username,
@@ -62,6 +68,24 @@ func (s *CalDAVSource) Fetch(ctx context.Context, end time.Time) ([]Event, error
 			base:     http.DefaultTransport,
 		},
 	}
+}
+
+// resolveURL resolves a server-relative href against the source URL.
+func (s *CalDAVSource) resolveURL(href string) (*url.URL, error) {
+	base, err := url.Parse(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return nil, fmt.Errorf("parse href %q: %w", href, err)
+	}
+	return base.ResolveReference(ref), nil
+}
+
+// Fetch retrieves events from the CalDAV server.
+func (s *CalDAVSource) Fetch(ctx context.Context, end time.Time) ([]Event, error) {
+	httpClient := s.httpClient()
 
 	// Create CalDAV client
 	client, err := caldav.NewClient(httpClient, s.url)
@@ -86,6 +110,9 @@ func (s *CalDAVSource) Fetch(ctx context.Context, end time.Time) ([]Event, error
 		return nil, fmt.Errorf("find calendars: %w", err)
 	}
 
+	colors := s.calendarColors(ctx, httpClient, homeSet)
+	addrs := s.userAddresses(ctx, httpClient, principal)
+
 	var allEvents []Event
 
 	// Filter calendars if specific ones requested
@@ -94,18 +121,325 @@ func (s *CalDAVSource) Fetch(ctx context.Context, end time.Time) ([]Event, error
 			continue
 		}
 
-		events, err := s.fetchCalendarEvents(ctx, client, cal, end)
+		events, err := s.fetchCalendarEvents(ctx, client, cal, end, addrs)
 		if err != nil {
 			// Log but continue with other calendars
 			continue
 		}
 
+		if color := colors[davPath(cal.Path)]; color != "" {
+			for i := range events {
+				events[i].Color = color
+			}
+		}
+
 		allEvents = append(allEvents, events...)
 	}
 
 	return allEvents, nil
 }
 
+// calendarColors returns the calendar-color of each calendar in the home set,
+// keyed by calendar path. Colors are optional, so errors are only logged.
+func (s *CalDAVSource) calendarColors(ctx context.Context, client *http.Client, homeSet string) map[string]string {
+	target, err := s.resolveURL(homeSet)
+	if err != nil {
+		slog.Debug("resolve calendar home", "source", s.name, "error", err)
+		return nil
+	}
+
+	props, err := propfind(ctx, client, target, "1", propCalendarColor)
+	if err != nil {
+		slog.Debug("fetch calendar colors", "source", s.name, "error", err)
+		return nil
+	}
+
+	colors := make(map[string]string, len(props))
+	for path, values := range props {
+		if color := NormalizeColor(davPropText(values, propCalendarColor)); color != "" {
+			colors[path] = color
+		}
+	}
+	return colors
+}
+
+// userAddresses returns the user's calendar addresses (lowercase email
+// addresses) from the principal's calendar-user-address-set, falling back to
+// the username when it is an email address.
+func (s *CalDAVSource) userAddresses(ctx context.Context, client *http.Client, principal string) []string {
+	var addrs []string
+	add := func(addr string) {
+		addr = normalizeCalAddress(addr)
+		if strings.Contains(addr, "@") && !slices.Contains(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	if target, err := s.resolveURL(principal); err == nil {
+		props, err := propfind(ctx, client, target, "0", propCalendarUserAddressSet)
+		if err != nil {
+			slog.Debug("fetch calendar user addresses", "source", s.name, "error", err)
+		}
+		for _, values := range props {
+			for _, href := range davPropHrefs(values, propCalendarUserAddressSet) {
+				if strings.HasPrefix(strings.ToLower(href), "mailto:") {
+					add(href)
+				}
+			}
+		}
+	}
+	add(s.username)
+	return addrs
+}
+
+// normalizeCalAddress converts a calendar user address ("mailto:" URI or bare
+// email) to a lowercase email address for comparison.
+func normalizeCalAddress(addr string) string {
+	addr = strings.ToLower(strings.TrimSpace(addr))
+	return strings.TrimPrefix(addr, "mailto:")
+}
+
+// ownAttendee returns the ATTENDEE property of comp that matches one of the
+// user's addresses.
+func ownAttendee(comp *ics.Component, addrs []string) *ics.Prop {
+	attendees := comp.Props[ics.PropAttendee]
+	for i := range attendees {
+		if slices.Contains(addrs, normalizeCalAddress(attendees[i].Value)) {
+			return &attendees[i]
+		}
+	}
+	return nil
+}
+
+// attendeePartStat returns the participation status of an attendee property.
+// PARTSTAT defaults to NEEDS-ACTION (RFC 5545 section 3.2.12).
+func attendeePartStat(prop *ics.Prop) PartStat {
+	if v := prop.Params.Get(ics.ParamParticipationStatus); v != "" {
+		return PartStat(strings.ToUpper(v))
+	}
+	return PartStatNeedsAction
+}
+
+// Respond updates the user's PARTSTAT on the stored calendar object. The PUT is
+// conditional on the ETag from the last sync, and the server delivers the iTIP
+// REPLY to the organizer (RFC 6638 section 3.2.2). For recurring events the
+// response applies to every instance in the object.
+func (s *CalDAVSource) Respond(ctx context.Context, event Event, status PartStat) error {
+	if event.Remote.Path == "" {
+		return fmt.Errorf("event %q has no CalDAV object path", event.Summary)
+	}
+
+	httpClient := s.httpClient()
+	client, err := caldav.NewClient(httpClient, s.url)
+	if err != nil {
+		return fmt.Errorf("create caldav client: %w", err)
+	}
+
+	principal, err := client.FindCurrentUserPrincipal(ctx)
+	if err != nil {
+		return fmt.Errorf("find principal: %w", err)
+	}
+	addrs := s.userAddresses(ctx, httpClient, principal)
+
+	obj, err := client.GetCalendarObject(ctx, event.Remote.Path)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", event.Remote.Path, err)
+	}
+
+	updated := false
+	for _, comp := range obj.Data.Children {
+		if comp.Name != ics.CompEvent {
+			continue
+		}
+		attendee := ownAttendee(comp, addrs)
+		if attendee == nil {
+			continue
+		}
+		attendee.Params.Set(ics.ParamParticipationStatus, string(status))
+		// Servers set SCHEDULE-STATUS; clients must not send it back.
+		attendee.Params.Del("SCHEDULE-STATUS")
+		updated = true
+	}
+	if !updated {
+		return fmt.Errorf("not an attendee of %q", event.Summary)
+	}
+
+	etag := event.Remote.ETag
+	if etag == "" {
+		etag = obj.ETag
+	}
+	var ifMatch string
+	if etag != "" {
+		ifMatch = strconv.Quote(etag)
+	}
+	if _, err := s.putCalendarObject(ctx, httpClient, event.Remote.Path, obj.Data, "If-Match", ifMatch); err != nil {
+		return err
+	}
+
+	slog.Info("responded to invitation", "source", s.name, "summary", event.Summary, "response", status)
+	return nil
+}
+
+// putCalendarObject stores cal at path with an optional precondition header
+// ("If-Match" or "If-None-Match") and returns the new ETag, if the server sent
+// one. go-webdav's client does not support conditional PUTs.
+func (s *CalDAVSource) putCalendarObject(ctx context.Context, client *http.Client, path string, cal *ics.Calendar, precondition, value string) (string, error) {
+	var body bytes.Buffer
+	if err := ics.NewEncoder(&body).Encode(cal); err != nil {
+		return "", fmt.Errorf("encode calendar object: %w", err)
+	}
+
+	target, err := s.resolveURL(path)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), &body)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", ics.MIMEType+"; charset=utf-8")
+	if value != "" {
+		req.Header.Set(precondition, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("put %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return "", fmt.Errorf("put %s: %w", path, ErrConflict)
+	case resp.StatusCode/100 != 2:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("put %s: unexpected status %s: %s", path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	etag := resp.Header.Get("ETag")
+	if unquoted, err := strconv.Unquote(strings.TrimPrefix(etag, "W/")); err == nil {
+		etag = unquoted
+	}
+	return etag, nil
+}
+
+// Create stores a new event in the named calendar, or the first synced
+// calendar if calendarName is empty. The PUT uses If-None-Match so an existing
+// object is never overwritten.
+func (s *CalDAVSource) Create(ctx context.Context, event Event, calendarName string) (Event, error) {
+	httpClient := s.httpClient()
+	client, err := caldav.NewClient(httpClient, s.url)
+	if err != nil {
+		return Event{}, fmt.Errorf("create caldav client: %w", err)
+	}
+
+	principal, err := client.FindCurrentUserPrincipal(ctx)
+	if err != nil {
+		return Event{}, fmt.Errorf("find principal: %w", err)
+	}
+	homeSet, err := client.FindCalendarHomeSet(ctx, principal)
+	if err != nil {
+		return Event{}, fmt.Errorf("find calendar home: %w", err)
+	}
+	cals, err := client.FindCalendars(ctx, homeSet)
+	if err != nil {
+		return Event{}, fmt.Errorf("find calendars: %w", err)
+	}
+
+	cal, err := s.targetCalendar(cals, calendarName)
+	if err != nil {
+		return Event{}, err
+	}
+
+	if event.UID == "" {
+		event.UID = newEventUID()
+	}
+
+	comp := ics.NewComponent(ics.CompEvent)
+	comp.Props.SetText(ics.PropUID, event.UID)
+	comp.Props.SetDateTime(ics.PropDateTimeStamp, time.Now().UTC())
+	if event.AllDay {
+		comp.Props.SetDate(ics.PropDateTimeStart, event.Start)
+		comp.Props.SetDate(ics.PropDateTimeEnd, event.End)
+	} else {
+		comp.Props.SetDateTime(ics.PropDateTimeStart, event.Start.UTC())
+		comp.Props.SetDateTime(ics.PropDateTimeEnd, event.End.UTC())
+	}
+	comp.Props.SetText(ics.PropSummary, event.Summary)
+	if event.Location != "" {
+		comp.Props.SetText(ics.PropLocation, event.Location)
+	}
+	if event.Description != "" {
+		comp.Props.SetText(ics.PropDescription, event.Description)
+	}
+
+	data := ics.NewCalendar()
+	data.Props.SetText(ics.PropVersion, "2.0")
+	data.Props.SetText(ics.PropProductID, "-//calbar//calbar//EN")
+	data.Children = append(data.Children, comp)
+
+	path := strings.TrimSuffix(cal.Path, "/") + "/" + url.PathEscape(event.UID) + ".ics"
+	etag, err := s.putCalendarObject(ctx, httpClient, path, data, "If-None-Match", "*")
+	if err != nil {
+		return Event{}, err
+	}
+
+	event.Source = fmt.Sprintf("%s/%s", s.name, cal.Name)
+	event.Remote = RemoteRef{Path: path, ETag: etag}
+	if color := s.calendarColors(ctx, httpClient, homeSet)[davPath(cal.Path)]; color != "" {
+		event.Color = color
+	}
+
+	slog.Info("created event", "source", s.name, "calendar", cal.Name, "summary", event.Summary)
+	return event, nil
+}
+
+// targetCalendar picks the calendar new events are created in: the named
+// calendar, else the first configured calendar, else the first calendar that
+// holds events.
+func (s *CalDAVSource) targetCalendar(cals []caldav.Calendar, name string) (caldav.Calendar, error) {
+	holdsEvents := func(cal caldav.Calendar) bool {
+		return len(cal.SupportedComponentSet) == 0 || slices.Contains(cal.SupportedComponentSet, ics.CompEvent)
+	}
+	find := func(name string) (caldav.Calendar, bool) {
+		for _, cal := range cals {
+			if strings.EqualFold(cal.Name, name) && holdsEvents(cal) {
+				return cal, true
+			}
+		}
+		return caldav.Calendar{}, false
+	}
+
+	if name != "" {
+		if cal, ok := find(name); ok {
+			return cal, nil
+		}
+		return caldav.Calendar{}, fmt.Errorf("calendar %q not found in source %q", name, s.name)
+	}
+	for _, configured := range s.calendars {
+		if cal, ok := find(configured); ok {
+			return cal, nil
+		}
+	}
+	for _, cal := range cals {
+		if holdsEvents(cal) {
+			return cal, nil
+		}
+	}
+	return caldav.Calendar{}, fmt.Errorf("no event calendar found in source %q", s.name)
+}
+
+// newEventUID returns a random UID for a new event.
+func newEventUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	// Set version (4) and variant (RFC 4122)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x@calbar",
+		b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
 // shouldSyncCalendar checks if a calendar should be synced based on config.
 func (s *CalDAVSource) shouldSyncCalendar(name string) bool {
 	for _, c := range s.calendars {
@@ -117,7 +451,7 @@ func (s *CalDAVSource) shouldSyncCalendar(name string) bool {
 }
 
 // fetchCalendarEvents fetches events from a single calendar.
-func (s *CalDAVSource) fetchCalendarEvents(ctx context.Context, client *caldav.Client, cal caldav.Calendar, end time.Time) ([]Event, error) {
+func (s *CalDAVSource) fetchCalendarEvents(ctx context.Context, client *caldav.Client, cal caldav.Calendar, end time.Time, addrs []string) ([]Event, error) {
 	// Query for events from now to the configured end time
 	now := time.Now()
 
@@ -136,6 +470,7 @@ func (s *CalDAVSource) fetchCalendarEvents(ctx context.Context, client *caldav.C
 					"LOCATION",
 					"URL",
 					"ORGANIZER",
+					"ATTENDEE",
 				},
 				Comps: []caldav.CalendarCompRequest{{
 					Name:  ics.CompAlarm,
@@ -164,10 +499,13 @@ func (s *CalDAVSource) fetchCalendarEvents(ctx context.Context, client *caldav.C
 			continue
 		}
 
-		parsed, err := s.parseCalendarObject(obj.Data, cal.Name)
+		parsed, err := s.parseCalendarObject(obj.Data, cal.Name, addrs)
 		if err != nil {
 			continue
 		}
+		for i := range parsed {
+			parsed[i].Remote = RemoteRef{Path: obj.Path, ETag: obj.ETag}
+		}
 
 		events = append(events, parsed...)
 	}
@@ -176,7 +514,7 @@ func (s *CalDAVSource) fetchCalendarEvents(ctx context.Context, client *caldav.C
 }
 
 // parseCalendarObject parses a CalDAV calendar object into events.
-func (s *CalDAVSource) parseCalendarObject(data *ics.Calendar, calName string) ([]Event, error) {
+func (s *CalDAVSource) parseCalendarObject(data *ics.Calendar, calName string, addrs []string) ([]Event, error) {
 	var events []Event
 
 	for _, comp := range data.Children {
@@ -184,7 +522,7 @@ func (s *CalDAVSource) parseCalendarObject(data *ics.Calendar, calName string) (
 			continue
 		}
 
-		event, err := s.parseEventComponent(comp, calName)
+		event, err := s.parseEventComponent(comp, calName, addrs)
 		if err != nil {
 			continue
 		}
@@ -196,7 +534,7 @@ func (s *CalDAVSource) parseCalendarObject(data *ics.Calendar, calName string) (
 }
 
 // parseEventComponent converts an ICS VEVENT to our Event type.
-func (s *CalDAVSource) parseEventComponent(comp *ics.Component, calName string) (Event, error) {
+func (s *CalDAVSource) parseEventComponent(comp *ics.Component, calName string, addrs []string) (Event, error) {
 	normalizeComponentTimezones(comp)
 
 	event := Event{
@@ -237,6 +575,15 @@ func (s *CalDAVSource) parseEventComponent(comp *ics.Component, calName string)
 		}
 	}
 
+	event.Attendees = parseAttendees(comp)
+	event.Status = parseEventStatus(comp)
+	event.FreeBusy = parseFreeBusy(comp)
+
+	// Own participation status, unless the user organizes the event
+	if attendee := ownAttendee(comp, addrs); attendee != nil && !slices.Contains(addrs, normalizeCalAddress(event.Organizer)) {
+		event.Response = attendeePartStat(attendee)
+	}
+
 	// Start time
 	if prop := comp.Props.Get(ics.PropDateTimeStart); prop != nil {
 		t, err := prop.DateTime(time.Local)
@@ -0,0 +1,307 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ics "github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+func TestCalDAVCalendarColors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != "PROPFIND" || r.Header.Get("Depth") != "1" || !strings.Contains(string(body), "calendar-color") {
+			t.Errorf("unexpected request: %s depth=%q body=%s", r.Method, r.Header.Get("Depth"), body)
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusMultiStatus)
+		io.WriteString(w, `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:a="http://apple.com/ns/ical/">
+  <d:response>
+    <d:href>/dav/calendars/me/</d:href>
+    <d:propstat><d:prop><a:calendar-color/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/dav/calendars/me/work/</d:href>
+    <d:propstat><d:prop><a:calendar-color>#FF2968FF</a:calendar-color></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>http://example.com/dav/calendars/me/home</d:href>
+    <d:propstat><d:prop><a:calendar-color>#1badf8</a:calendar-color></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+</d:multistatus>`)
+	}))
+	defer srv.Close()
+
+	s := NewCalDAVSource("test", srv.URL+"/dav/", "user", "pass", nil)
+	colors := s.calendarColors(context.Background(), s.httpClient(), "/dav/calendars/me/")
+
+	want := map[string]string{
+		"/dav/calendars/me/work": "#ff2968",
+		"/dav/calendars/me/home": "#1badf8",
+	}
+	if len(colors) != len(want) {
+		t.Fatalf("colors = %v, want %v", colors, want)
+	}
+	for path, color := range want {
+		if colors[path] != color {
+			t.Errorf("colors[%q] = %q, want %q", path, colors[path], color)
+		}
+	}
+}
+
+// memCalDAVBackend is an in-memory caldav.Backend with a single user and
+// calendar, used to exercise the CalDAV client against a real server.
+type memCalDAVBackend struct {
+	mu      sync.Mutex
+	objects map[string]*caldav.CalendarObject
+	puts    int
+}
+
+const (
+	memPrincipal    = "/alice/"
+	memHomeSet      = "/alice/calendars/"
+	memCalendarPath = "/alice/calendars/work/"
+)
+
+func newMemCalDAVBackend() *memCalDAVBackend {
+	return &memCalDAVBackend{objects: make(map[string]*caldav.CalendarObject)}
+}
+
+func (b *memCalDAVBackend) add(t *testing.T, name, data string) {
+	t.Helper()
+	cal, err := ics.NewDecoder(strings.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	path := memCalendarPath + name
+	b.objects[path] = &caldav.CalendarObject{Path: path, ETag: "v1", Data: cal}
+}
+
+func (b *memCalDAVBackend) CurrentUserPrincipal(ctx context.Context) (string, error) {
+	return memPrincipal, nil
+}
+
+func (b *memCalDAVBackend) CalendarHomeSetPath(ctx context.Context) (string, error) {
+	return memHomeSet, nil
+}
+
+func (b *memCalDAVBackend) CreateCalendar(ctx context.Context, calendar *caldav.Calendar) error {
+	return webdav.NewHTTPError(http.StatusForbidden, nil)
+}
+
+func (b *memCalDAVBackend) ListCalendars(ctx context.Context) ([]caldav.Calendar, error) {
+	return []caldav.Calendar{{Path: memCalendarPath, Name: "Work", SupportedComponentSet: []string{ics.CompEvent}}}, nil
+}
+
+func (b *memCalDAVBackend) GetCalendar(ctx context.Context, path string) (*caldav.Calendar, error) {
+	if path != memCalendarPath {
+		return nil, webdav.NewHTTPError(http.StatusNotFound, nil)
+	}
+	return &caldav.Calendar{Path: memCalendarPath, Name: "Work", SupportedComponentSet: []string{ics.CompEvent}}, nil
+}
+
+func (b *memCalDAVBackend) GetCalendarObject(ctx context.Context, path string, req *caldav.CalendarCompRequest) (*caldav.CalendarObject, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	obj, ok := b.objects[path]
+	if !ok {
+		return nil, webdav.NewHTTPError(http.StatusNotFound, nil)
+	}
+	return obj, nil
+}
+
+func (b *memCalDAVBackend) ListCalendarObjects(ctx context.Context, path string, req *caldav.CalendarCompRequest) ([]caldav.CalendarObject, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var objs []caldav.CalendarObject
+	for _, obj := range b.objects {
+		objs = append(objs, *obj)
+	}
+	return objs, nil
+}
+
+func (b *memCalDAVBackend) QueryCalendarObjects(ctx context.Context, path string, query *caldav.CalendarQuery) ([]caldav.CalendarObject, error) {
+	return b.ListCalendarObjects(ctx, path, &query.CompRequest)
+}
+
+func (b *memCalDAVBackend) PutCalendarObject(ctx context.Context, path string, cal *ics.Calendar, opts *caldav.PutCalendarObjectOptions) (*caldav.CalendarObject, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cur, ok := b.objects[path]
+	if opts.IfMatch.IsSet() {
+		if !ok {
+			return nil, webdav.NewHTTPError(http.StatusPreconditionFailed, nil)
+		}
+		if match, err := opts.IfMatch.MatchETag(cur.ETag); err != nil || !match {
+			return nil, webdav.NewHTTPError(http.StatusPreconditionFailed, nil)
+		}
+	}
+	if opts.IfNoneMatch.IsWildcard() && ok {
+		return nil, webdav.NewHTTPError(http.StatusPreconditionFailed, nil)
+	}
+	b.puts++
+	obj := &caldav.CalendarObject{Path: path, ETag: fmt.Sprintf("v%d", b.puts+1), Data: cal}
+	b.objects[path] = obj
+	return obj, nil
+}
+
+func (b *memCalDAVBackend) DeleteCalendarObject(ctx context.Context, path string) error {
+	return webdav.NewHTTPError(http.StatusForbidden, nil)
+}
+
+func invitationICS(start time.Time) string {
+	return strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//EN",
+		"BEGIN:VEVENT",
+		"UID:planning-1",
+		"DTSTAMP:20260101T000000Z",
+		"DTSTART:" + start.UTC().Format("20060102T150405Z"),
+		"DTEND:" + start.Add(time.Hour).UTC().Format("20060102T150405Z"),
+		"SUMMARY:Planning",
+		"ORGANIZER:mailto:bob@example.com",
+		"ATTENDEE;PARTSTAT=ACCEPTED:mailto:bob@example.com",
+		"ATTENDEE;PARTSTAT=NEEDS-ACTION;RSVP=TRUE;SCHEDULE-STATUS=1.2:mailto:Alice@Example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+}
+
+func TestCalDAVRespond(t *testing.T) {
+	backend := newMemCalDAVBackend()
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	backend.add(t, "planning.ics", invitationICS(start))
+
+	srv := httptest.NewServer(&caldav.Handler{Backend: backend})
+	defer srv.Close()
+
+	s := NewCalDAVSource("dav", srv.URL, "alice@example.com", "secret", nil)
+	events, err := s.Fetch(context.Background(), start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Response != PartStatNeedsAction || !event.CanRespond() {
+		t.Fatalf("expected answerable invitation, got response %q", event.Response)
+	}
+	if event.Remote.Path != memCalendarPath+"planning.ics" || event.Remote.ETag != "v1" {
+		t.Fatalf("unexpected remote ref: %+v", event.Remote)
+	}
+
+	if err := s.Respond(context.Background(), event, PartStatAccepted); err != nil {
+		t.Fatalf("Respond error: %v", err)
+	}
+
+	stored := backend.objects[event.Remote.Path]
+	if stored.ETag == "v1" {
+		t.Fatal("expected object to be updated")
+	}
+	for _, attendee := range stored.Data.Events()[0].Props.Values(ics.PropAttendee) {
+		addr := normalizeCalAddress(attendee.Value)
+		partstat := attendee.Params.Get(ics.ParamParticipationStatus)
+		switch addr {
+		case "alice@example.com":
+			if partstat != "ACCEPTED" {
+				t.Errorf("alice PARTSTAT = %q, want ACCEPTED", partstat)
+			}
+			if attendee.Params.Get("SCHEDULE-STATUS") != "" {
+				t.Error("expected SCHEDULE-STATUS to be removed")
+			}
+		case "bob@example.com":
+			if partstat != "ACCEPTED" {
+				t.Errorf("bob PARTSTAT changed to %q", partstat)
+			}
+		}
+	}
+
+	// The event still carries the old ETag, so a second write must not
+	// overwrite the changed object.
+	err = s.Respond(context.Background(), event, PartStatDeclined)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for stale ETag, got %v", err)
+	}
+}
+
+func TestCalDAVOrganizerHasNoResponse(t *testing.T) {
+	s := NewCalDAVSource("dav", "http://example.com", "bob@example.com", "", nil)
+	cal, err := ics.NewDecoder(strings.NewReader(invitationICS(time.Now()))).Decode()
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	events, err := s.parseCalendarObject(cal, "Work", []string{"bob@example.com"})
+	if err != nil {
+		t.Fatalf("parseCalendarObject error: %v", err)
+	}
+	if len(events) != 1 || events[0].Response != "" {
+		t.Fatalf("expected organizer event without response, got %+v", events)
+	}
+}
+
+func TestCalDAVCreate(t *testing.T) {
+	backend := newMemCalDAVBackend()
+	srv := httptest.NewServer(&caldav.Handler{Backend: backend})
+	defer srv.Close()
+
+	s := NewCalDAVSource("dav", srv.URL, "alice@example.com", "secret", nil)
+	start := time.Now().Add(3 * time.Hour).Truncate(time.Minute)
+	created, err := s.Create(context.Background(), Event{
+		Summary: "Focus",
+		Start:   start,
+		End:     start.Add(time.Hour),
+	}, "work")
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if created.UID == "" || created.Source != "dav/Work" {
+		t.Fatalf("unexpected created event: %+v", created)
+	}
+	if !strings.HasPrefix(created.Remote.Path, memCalendarPath) || created.Remote.ETag == "" {
+		t.Fatalf("unexpected remote ref: %+v", created.Remote)
+	}
+
+	stored := backend.objects[created.Remote.Path]
+	if stored == nil {
+		t.Fatalf("no object stored at %s", created.Remote.Path)
+	}
+	vevent := stored.Data.Events()[0]
+	if got := vevent.Props.Get(ics.PropSummary).Value; got != "Focus" {
+		t.Errorf("SUMMARY = %q, want Focus", got)
+	}
+	if got, err := vevent.DateTimeStart(time.UTC); err != nil || !got.Equal(start) {
+		t.Errorf("DTSTART = %v (%v), want %v", got, err, start)
+	}
+
+	events, err := s.Fetch(context.Background(), start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if len(events) != 1 || events[0].UID != created.UID {
+		t.Fatalf("expected created event to be fetched, got %+v", events)
+	}
+
+	// Creating the same UID again must not overwrite the object.
+	_, err = s.Create(context.Background(), created, "")
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for existing UID, got %v", err)
+	}
+
+	if _, err := s.Create(context.Background(), created, "Personal"); err == nil {
+		t.Fatal("expected error for unknown calendar")
+	}
+}
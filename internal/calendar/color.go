@@ -0,0 +1,50 @@
+package calendar
+
+import (
+	"strings"
+
+	ics "github.com/emersion/go-ical"
+)
+
+// Calendar color properties, in order of preference.
+// COLOR is defined by RFC 7986; the Apple property is common in exported feeds.
+const (
+	propColor              = "COLOR"
+	propAppleCalendarColor = "X-APPLE-CALENDAR-COLOR"
+)
+
+// NormalizeColor converts a hex color ("#rgb", "#rrggbb" or "#rrggbbaa", with or
+// without the leading "#") to lowercase "#rrggbb". Any alpha component is dropped,
+// since calendar servers commonly append one. Returns "" if s is not a hex color.
+func NormalizeColor(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return ""
+		}
+	}
+
+	switch len(s) {
+	case 3:
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	case 6:
+	case 8:
+		s = s[:6]
+	default:
+		return ""
+	}
+	return "#" + strings.ToLower(s)
+}
+
+// componentColor returns the hex color set on a VCALENDAR or VEVENT component.
+// RFC 7986 also allows CSS color names for COLOR; those are ignored.
+func componentColor(comp *ics.Component) string {
+	for _, name := range []string{propColor, propAppleCalendarColor} {
+		if prop := comp.Props.Get(name); prop != nil {
+			if color := NormalizeColor(prop.Value); color != "" {
+				return color
+			}
+		}
+	}
+	return ""
+}
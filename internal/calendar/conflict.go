@@ -0,0 +1,61 @@
+package calendar
+
+import (
+	"slices"
+	"time"
+)
+
+// Conflict is a pair of overlapping events. A starts first.
+type Conflict struct {
+	A, B Event
+}
+
+// Key identifies the conflict across syncs.
+func (c Conflict) Key() string {
+	return c.A.UID + "\x00" + c.B.UID
+}
+
+// FindConflicts returns the pairs of events that overlap each other between
+// from and to, ordered by start time. Only timed events that make the user
+// busy count: all-day, free, declined and cancelled events never conflict.
+func FindConflicts(events []Event, from, to time.Time) []Conflict {
+	var busy []Event
+	for _, e := range events {
+		if e.AllDay || e.Duration() >= 24*time.Hour || e.IsFree() || e.IsCancelled() || e.Response == PartStatDeclined {
+			continue
+		}
+		if !e.End.After(from) || !e.Start.Before(to) {
+			continue
+		}
+		busy = append(busy, e)
+	}
+	slices.SortStableFunc(busy, func(a, b Event) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	var conflicts []Conflict
+	for i, a := range busy {
+		for _, b := range busy[i+1:] {
+			if !b.Start.Before(a.End) {
+				break
+			}
+			// The same meeting from two calendars is not a conflict
+			if a.UID == b.UID {
+				continue
+			}
+			conflicts = append(conflicts, Conflict{A: a, B: b})
+		}
+	}
+	return conflicts
+}
+
+// ConflictingEvents maps the UID of each event in a conflict to the events it
+// overlaps.
+func ConflictingEvents(conflicts []Conflict) map[string][]Event {
+	m := make(map[string][]Event)
+	for _, c := range conflicts {
+		m[c.A.UID] = append(m[c.A.UID], c.B)
+		m[c.B.UID] = append(m[c.B.UID], c.A)
+	}
+	return m
+}
@@ -0,0 +1,62 @@
+package calendar
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFindConflicts(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	event := func(uid string, start, end time.Time) Event {
+		return Event{UID: uid, Summary: uid, Start: start, End: end}
+	}
+
+	standup := event("standup", at(9, 0), at(9, 30))
+	review := event("review", at(9, 15), at(10, 0))
+	oneOnOne := event("1:1", at(9, 45), at(10, 15))
+	lunch := event("lunch", at(12, 0), at(13, 0))
+	backToBack := event("back-to-back", at(13, 0), at(13, 30))
+	focus := event("focus", at(12, 0), at(14, 0))
+	focus.FreeBusy = FreeBusyFree
+	declined := event("declined", at(12, 30), at(13, 30))
+	declined.Response = PartStatDeclined
+	cancelled := event("cancelled", at(12, 30), at(13, 30))
+	cancelled.Status = StatusCancelled
+	holiday := event("holiday", day, day.Add(24*time.Hour))
+	holiday.AllDay = true
+	tomorrow := event("tomorrow", at(33, 0), at(34, 0))
+	tomorrowToo := event("tomorrow-too", at(33, 30), at(34, 0))
+
+	events := []Event{lunch, review, standup, oneOnOne, backToBack, focus, declined, cancelled, holiday, tomorrow, tomorrowToo}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{"whole range", day, day.Add(48 * time.Hour), []string{"standup/review", "review/1:1", "tomorrow/tomorrow-too"}},
+		{"ended events are left out", at(9, 40), day.Add(24 * time.Hour), []string{"review/1:1"}},
+		{"events after the range are left out", day, at(9, 30), []string{"standup/review"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range FindConflicts(events, tt.from, tt.to) {
+				got = append(got, c.A.UID+"/"+c.B.UID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FindConflicts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	byUID := ConflictingEvents(FindConflicts(events, day, day.Add(24*time.Hour)))
+	if got := byUID["review"]; len(got) != 2 || got[0].UID != "standup" || got[1].UID != "1:1" {
+		t.Errorf("ConflictingEvents()[review] = %v, want standup and 1:1", got)
+	}
+	if _, ok := byUID["lunch"]; ok {
+		t.Error("ConflictingEvents() includes lunch, which has no conflicts")
+	}
+}
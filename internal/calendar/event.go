@@ -3,6 +3,10 @@ package calendar
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -29,9 +33,15 @@ type Event struct {
 	// AllDay indicates this is an all-day event.
 	AllDay bool
 
+	// Status is the overall status of the event as set by the organizer.
+	Status EventStatus
+
 	// Organizer is the email of the event organizer.
 	Organizer string
 
+	// Attendees are the invited participants, including the user.
+	Attendees []Attendee
+
 	// Source is the name of the calendar source this event came from.
 	Source string
 
@@ -44,8 +54,200 @@ type Event struct {
 	// NotifyAt contains absolute reminder times for this event instance.
 	NotifyAt []time.Time
 
+	// NotifySet indicates NotifyAt was set by a transform and takes
+	// precedence over the configured notification offsets.
+	NotifySet bool
+
 	// Stale indicates this event is from a failed sync and may be outdated.
 	Stale bool
+
+	// Color is the calendar or source color as "#rrggbb", if known.
+	Color string
+
+	// Tags are labels attached to the event by transforms or, for ICS
+	// output, read back from CATEGORIES.
+	Tags []string
+
+	// Response is the user's own response to the event when it is an
+	// invitation. It is empty for events the user organizes or whose
+	// attendees do not include the user.
+	Response PartStat
+
+	// FreeBusy is how the event affects the user's availability. It is empty
+	// if the source does not say.
+	FreeBusy FreeBusy
+
+	// Remote identifies the event on the server for sources that can write
+	// back to it (e.g. to respond to an invitation).
+	Remote RemoteRef
+}
+
+// RemoteRef identifies an event on its source server.
+type RemoteRef struct {
+	// ID is the server-side event identifier (Microsoft Graph event ID).
+	ID string
+
+	// Path is the CalDAV calendar object path.
+	Path string
+
+	// ETag is the CalDAV object ETag at the time of the last sync.
+	ETag string
+}
+
+// IsZero reports whether r does not reference a server object.
+func (r RemoteRef) IsZero() bool {
+	return r == RemoteRef{}
+}
+
+// PartStat is an attendee participation status (iCalendar PARTSTAT).
+type PartStat string
+
+const (
+	PartStatNeedsAction PartStat = "NEEDS-ACTION"
+	PartStatAccepted    PartStat = "ACCEPTED"
+	PartStatTentative   PartStat = "TENTATIVE"
+	PartStatDeclined    PartStat = "DECLINED"
+)
+
+// Label returns a short human-readable label for the status.
+func (p PartStat) Label() string {
+	switch p {
+	case PartStatNeedsAction:
+		return "Not responded"
+	case PartStatAccepted:
+		return "Accepted"
+	case PartStatTentative:
+		return "Tentative"
+	case PartStatDeclined:
+		return "Declined"
+	default:
+		return string(p)
+	}
+}
+
+// EventStatus is the overall status of an event (iCalendar STATUS).
+type EventStatus string
+
+const (
+	StatusConfirmed EventStatus = "CONFIRMED"
+	StatusTentative EventStatus = "TENTATIVE"
+	StatusCancelled EventStatus = "CANCELLED"
+)
+
+// IsCancelled reports whether the event was cancelled by the organizer.
+func (e *Event) IsCancelled() bool {
+	return e.Status == StatusCancelled
+}
+
+// FreeBusy is how an event shows on the user's schedule. The values follow
+// X-MICROSOFT-CDO-BUSYSTATUS.
+type FreeBusy string
+
+const (
+	FreeBusyFree             FreeBusy = "FREE"
+	FreeBusyTentative        FreeBusy = "TENTATIVE"
+	FreeBusyBusy             FreeBusy = "BUSY"
+	FreeBusyOOF              FreeBusy = "OOF"
+	FreeBusyWorkingElsewhere FreeBusy = "WORKINGELSEWHERE"
+)
+
+// Attendee is an invited participant of an event.
+type Attendee struct {
+	// Name is the display name (CN), if known.
+	Name string
+
+	// Email is the attendee's address, without "mailto:".
+	Email string
+
+	// Role is the iCalendar ROLE, e.g. RoleRequired.
+	Role string
+
+	// Status is the attendee's participation status. It is empty if the
+	// source does not track responses for this attendee.
+	Status PartStat
+}
+
+// Attendee roles (iCalendar ROLE values).
+const (
+	RoleChair          = "CHAIR"
+	RoleRequired       = "REQ-PARTICIPANT"
+	RoleOptional       = "OPT-PARTICIPANT"
+	RoleNonParticipant = "NON-PARTICIPANT"
+)
+
+// DisplayName returns the attendee's name, or the email if there is none.
+func (a Attendee) DisplayName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Email
+}
+
+// AttendeeSummary returns a short count of attendees by response, e.g.
+// "5 attendees: 3 accepted, 1 declined, 1 not responded". It returns "" if the
+// event has no attendees.
+func (e *Event) AttendeeSummary() string {
+	if len(e.Attendees) == 0 {
+		return ""
+	}
+
+	noun := "attendees"
+	if len(e.Attendees) == 1 {
+		noun = "attendee"
+	}
+	summary := fmt.Sprintf("%d %s", len(e.Attendees), noun)
+
+	counts := make(map[PartStat]int)
+	for _, a := range e.Attendees {
+		counts[a.Status]++
+	}
+	var parts []string
+	for _, status := range []PartStat{PartStatAccepted, PartStatTentative, PartStatDeclined, PartStatNeedsAction} {
+		if n := counts[status]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, strings.ToLower(status.Label())))
+		}
+	}
+	if len(parts) == 0 {
+		return summary
+	}
+	return summary + ": " + strings.Join(parts, ", ")
+}
+
+// IsFree reports whether the event leaves the user available, such as a
+// reminder or an event marked "show as free".
+func (e *Event) IsFree() bool {
+	return e.FreeBusy == FreeBusyFree
+}
+
+// ResolveResponse sets Response from the attendee matching one of the user's
+// identities (email addresses), for sources that cannot tell on their own.
+// It leaves Response alone if it is already set or the user is the organizer.
+func (e *Event) ResolveResponse(identities []string) {
+	if e.Response != "" || len(identities) == 0 {
+		return
+	}
+	isIdentity := func(addr string) bool {
+		return slices.ContainsFunc(identities, func(id string) bool {
+			return strings.EqualFold(strings.TrimSpace(id), addr)
+		})
+	}
+	if e.Organizer != "" && isIdentity(e.Organizer) {
+		return
+	}
+	for _, a := range e.Attendees {
+		if isIdentity(a.Email) {
+			e.Response = a.Status
+			if e.Response == "" {
+				e.Response = PartStatNeedsAction
+			}
+			return
+		}
+	}
+}
+
+// CanRespond reports whether the user can respond to this event as an invitee.
+func (e *Event) CanRespond() bool {
+	return e.Response != "" && !e.Remote.IsZero()
 }
 
 // MeetingDetails contains structured online meeting information.
@@ -103,3 +305,22 @@ type Source interface {
 	// Events should be fetched from now until the specified end time.
 	Fetch(ctx context.Context, end time.Time) ([]Event, error)
 }
+
+// ErrConflict is returned when a write is rejected because the event changed
+// on the server since it was last synced.
+var ErrConflict = errors.New("event was modified on the server since the last sync")
+
+// Responder is implemented by sources that can respond to invitations.
+type Responder interface {
+	// Respond sets the user's participation status on an event from this
+	// source and notifies the organizer.
+	Respond(ctx context.Context, event Event, status PartStat) error
+}
+
+// Creator is implemented by sources that can create new events.
+type Creator interface {
+	// Create stores a new event in the named calendar of this source, or the
+	// source's default calendar if calendarName is empty, and returns the event
+	// as it was stored.
+	Create(ctx context.Context, event Event, calendarName string) (Event, error)
+}
@@ -79,3 +79,81 @@ func TestIsEffectivelyAllDay(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"#3584E4", "#3584e4"},
+		{"3584e4", "#3584e4"},
+		{"#3584E4FF", "#3584e4"},
+		{"#abc", "#aabbcc"},
+		{" #abc ", "#aabbcc"},
+		{"turquoise", ""},
+		{"#12345", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeColor(tt.in); got != tt.want {
+			t.Errorf("NormalizeColor(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAttendeeSummary(t *testing.T) {
+	tests := []struct {
+		attendees []Attendee
+		want      string
+	}{
+		{nil, ""},
+		{[]Attendee{{Email: "a@example.com"}}, "1 attendee"},
+		{
+			[]Attendee{
+				{Email: "a@example.com", Status: PartStatAccepted},
+				{Email: "b@example.com", Status: PartStatAccepted},
+				{Email: "c@example.com", Status: PartStatDeclined},
+				{Email: "d@example.com", Status: PartStatNeedsAction},
+				{Email: "e@example.com", Status: PartStatTentative},
+			},
+			"5 attendees: 2 accepted, 1 tentative, 1 declined, 1 not responded",
+		},
+	}
+
+	for _, tt := range tests {
+		e := Event{Attendees: tt.attendees}
+		if got := e.AttendeeSummary(); got != tt.want {
+			t.Errorf("AttendeeSummary() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestResolveResponse(t *testing.T) {
+	identities := []string{"Me@Example.com", "me@work.example.com"}
+	attendees := []Attendee{
+		{Email: "bob@example.com", Status: PartStatAccepted},
+		{Email: "me@example.com", Status: PartStatDeclined},
+	}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  PartStat
+	}{
+		{"matching attendee", Event{Organizer: "bob@example.com", Attendees: attendees}, PartStatDeclined},
+		{"organizer", Event{Organizer: "me@example.com", Attendees: attendees}, ""},
+		{"already set", Event{Response: PartStatAccepted, Attendees: attendees}, PartStatAccepted},
+		{"not invited", Event{Attendees: attendees[:1]}, ""},
+		{"no status", Event{Attendees: []Attendee{{Email: "me@work.example.com"}}}, PartStatNeedsAction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.ResolveResponse(identities)
+			if tt.event.Response != tt.want {
+				t.Errorf("Response = %q, want %q", tt.event.Response, tt.want)
+			}
+		})
+	}
+}
@@ -82,6 +82,8 @@ func (s *ICSSource) parseICS(r io.Reader) ([]Event, error) {
 			return nil, fmt.Errorf("decode ICS: %w", err)
 		}
 
+		calColor := componentColor(cal.Component)
+
 		for _, comp := range cal.Children {
 			if comp.Name != ics.CompEvent {
 				continue
@@ -93,8 +95,14 @@ func (s *ICSSource) parseICS(r io.Reader) ([]Event, error) {
 				continue
 			}
 
+			color := componentColor(comp)
+			if color == "" {
+				color = calColor
+			}
+
 			// Filter events to the configured time range (now to end)
 			for _, event := range parsed {
+				event.Color = color
 				// Include events that end after now and start before end
 				if event.End.After(now) && event.Start.Before(s.end) {
 					events = append(events, event)
@@ -149,6 +157,9 @@ func (s *ICSSource) parseEvent(comp *ics.Component) ([]Event, error) {
 			base.Organizer = base.Organizer[7:]
 		}
 	}
+	base.Attendees = parseAttendees(comp)
+	base.Status = parseEventStatus(comp)
+	base.FreeBusy = parseFreeBusy(comp)
 
 	// Start time
 	var startTime time.Time
@@ -3,6 +3,7 @@ package calendar
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -29,6 +30,7 @@ func TestWriteReadICS_PreservesMeetingDetails(t *testing.T) {
 		Start:       start,
 		End:         start.Add(time.Hour),
 		Source:      "ms365",
+		Color:       "#3584e4",
 		Meeting: MeetingDetails{
 			URL:               "https://teams.microsoft.com/meet/22792173431568?p=d4qiBuwhjR0xQOLil6",
 			Service:           "Microsoft Teams Meeting",
@@ -54,6 +56,9 @@ func TestWriteReadICS_PreservesMeetingDetails(t *testing.T) {
 	if parsed[0].Meeting != events[0].Meeting {
 		t.Fatalf("unexpected meeting details: got %#v want %#v", parsed[0].Meeting, events[0].Meeting)
 	}
+	if parsed[0].Color != events[0].Color {
+		t.Fatalf("unexpected color: got %q want %q", parsed[0].Color, events[0].Color)
+	}
 }
 
 func TestParseICS_DescriptionUnescapesLiteralNewlines(t *testing.T) {
@@ -431,3 +436,254 @@ END:VCALENDAR`, start.Format("20060102T150405"), end.Format("20060102T150405"))
 		}
 	}
 }
+
+func TestParseICS_CalendarColor(t *testing.T) {
+	icsData := `BEGIN:VCALENDAR
+VERSION:2.0
+X-APPLE-CALENDAR-COLOR:#FF2968FF
+BEGIN:VEVENT
+UID:calendar-color
+SUMMARY:Uses calendar color
+DTSTART:20990101T100000Z
+DTEND:20990101T110000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:event-color
+SUMMARY:Uses own color
+COLOR:#00ff00
+DTSTART:20990101T120000Z
+DTEND:20990101T130000Z
+END:VEVENT
+END:VCALENDAR`
+
+	s := &ICSSource{name: "test", end: time.Date(2099, 2, 1, 0, 0, 0, 0, time.UTC)}
+	events, err := s.parseICS(strings.NewReader(icsData))
+	if err != nil {
+		t.Fatalf("parseICS error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	got := map[string]string{}
+	for _, e := range events {
+		got[e.UID] = e.Color
+	}
+	if got["calendar-color"] != "#ff2968" {
+		t.Errorf("calendar-color event color = %q, want %q", got["calendar-color"], "#ff2968")
+	}
+	if got["event-color"] != "#00ff00" {
+		t.Errorf("event-color event color = %q, want %q", got["event-color"], "#00ff00")
+	}
+}
+
+func TestParseEvent_Attendees(t *testing.T) {
+	icsData := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:test-attendees",
+		"SUMMARY:Planning",
+		"DTSTART:20260217T100000Z",
+		"DTEND:20260217T110000Z",
+		"ORGANIZER;CN=Bob:mailto:bob@example.com",
+		"ATTENDEE;CN=Bob;ROLE=CHAIR;PARTSTAT=ACCEPTED:mailto:bob@example.com",
+		"ATTENDEE;CN=\"Doe, Jane\";ROLE=REQ-PARTICIPANT;PARTSTAT=DECLINED:mailto:Jane@Example.com",
+		"ATTENDEE;ROLE=OPT-PARTICIPANT:MAILTO:sam@example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	cal, err := ics.NewDecoder(strings.NewReader(icsData)).Decode()
+	if err != nil {
+		t.Fatalf("failed to decode ICS: %v", err)
+	}
+
+	s := &ICSSource{name: "test", end: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	events, err := s.parseEvent(cal.Children[0])
+	if err != nil {
+		t.Fatalf("parseEvent error: %v", err)
+	}
+
+	want := []Attendee{
+		{Name: "Bob", Email: "bob@example.com", Role: RoleChair, Status: PartStatAccepted},
+		{Name: "Doe, Jane", Email: "Jane@Example.com", Role: RoleRequired, Status: PartStatDeclined},
+		{Email: "sam@example.com", Role: RoleOptional, Status: PartStatNeedsAction},
+	}
+	got := events[0].Attendees
+	if len(got) != len(want) {
+		t.Fatalf("expected %d attendees, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("attendee %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWriteReadICS_PreservesAttendees(t *testing.T) {
+	start := time.Date(2026, 5, 5, 12, 0, 0, 0, time.UTC)
+	events := []Event{{
+		UID:       "meeting-1",
+		Summary:   "Meeting",
+		Start:     start,
+		End:       start.Add(time.Hour),
+		Organizer: "bob@example.com",
+		Attendees: []Attendee{
+			{Name: "Doe, Jane", Email: "jane@example.com", Role: RoleRequired, Status: PartStatTentative},
+			{Email: "room-1@example.com", Role: RoleNonParticipant},
+		},
+	}}
+
+	path := filepath.Join(t.TempDir(), "events.ics")
+	if err := WriteICS(path, events); err != nil {
+		t.Fatalf("WriteICS error: %v", err)
+	}
+
+	parsed, err := ReadICS(path)
+	if err != nil {
+		t.Fatalf("ReadICS error: %v", err)
+	}
+	if len(parsed) != 1 || len(parsed[0].Attendees) != 2 {
+		t.Fatalf("unexpected attendees: %+v", parsed)
+	}
+	if got := parsed[0].Attendees[0]; got != events[0].Attendees[0] {
+		t.Errorf("attendee 0 = %+v, want %+v", got, events[0].Attendees[0])
+	}
+	// ATTENDEE without PARTSTAT defaults to NEEDS-ACTION (RFC 5545)
+	want := Attendee{Email: "room-1@example.com", Role: RoleNonParticipant, Status: PartStatNeedsAction}
+	if got := parsed[0].Attendees[1]; got != want {
+		t.Errorf("attendee 1 = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseEvent_FreeBusy(t *testing.T) {
+	tests := []struct {
+		props []string
+		want  FreeBusy
+	}{
+		{nil, ""},
+		{[]string{"TRANSP:TRANSPARENT"}, FreeBusyFree},
+		{[]string{"TRANSP:OPAQUE"}, FreeBusyBusy},
+		{[]string{"TRANSP:OPAQUE", "X-MICROSOFT-CDO-BUSYSTATUS:TENTATIVE"}, FreeBusyTentative},
+		{[]string{"X-MICROSOFT-CDO-BUSYSTATUS:oof"}, FreeBusyOOF},
+	}
+
+	for _, tt := range tests {
+		lines := []string{
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:test-freebusy",
+			"SUMMARY:Focus",
+			"DTSTART:20260217T100000Z",
+			"DTEND:20260217T110000Z",
+		}
+		lines = append(lines, tt.props...)
+		lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+		cal, err := ics.NewDecoder(strings.NewReader(strings.Join(lines, "\r\n"))).Decode()
+		if err != nil {
+			t.Fatalf("failed to decode ICS: %v", err)
+		}
+
+		s := &ICSSource{name: "test", end: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+		events, err := s.parseEvent(cal.Children[0])
+		if err != nil {
+			t.Fatalf("parseEvent error: %v", err)
+		}
+		if got := events[0].FreeBusy; got != tt.want {
+			t.Errorf("%v: FreeBusy = %q, want %q", tt.props, got, tt.want)
+		}
+	}
+}
+
+func TestWriteReadICS_PreservesStatusResponseAndFreeBusy(t *testing.T) {
+	start := time.Date(2026, 5, 5, 12, 0, 0, 0, time.UTC)
+	events := []Event{
+		{UID: "a", Summary: "Tentative", Start: start, End: start.Add(time.Hour), Response: PartStatTentative, FreeBusy: FreeBusyTentative},
+		{UID: "b", Summary: "Reminder", Start: start, End: start.Add(time.Hour), FreeBusy: FreeBusyFree},
+		{UID: "c", Summary: "Cancelled", Start: start, End: start.Add(time.Hour), Status: StatusCancelled},
+	}
+
+	path := filepath.Join(t.TempDir(), "events.ics")
+	if err := WriteICS(path, events); err != nil {
+		t.Fatalf("WriteICS error: %v", err)
+	}
+
+	parsed, err := ReadICS(path)
+	if err != nil {
+		t.Fatalf("ReadICS error: %v", err)
+	}
+	if len(parsed) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(parsed))
+	}
+	if parsed[2].Status != StatusCancelled {
+		t.Errorf("Status = %q, want %q", parsed[2].Status, StatusCancelled)
+	}
+	for i := range events {
+		if parsed[i].Response != events[i].Response || parsed[i].FreeBusy != events[i].FreeBusy {
+			t.Errorf("event %s: got response %q, free/busy %q; want %q, %q",
+				events[i].UID, parsed[i].Response, parsed[i].FreeBusy, events[i].Response, events[i].FreeBusy)
+		}
+	}
+}
+
+func TestWriteReadICS_PreservesTags(t *testing.T) {
+	start := time.Date(2026, 5, 5, 12, 0, 0, 0, time.UTC)
+	events := []Event{
+		{UID: "a", Summary: "Sync", Start: start, End: start.Add(time.Hour), Tags: []string{"work", "external, via FW"}},
+	}
+
+	path := filepath.Join(t.TempDir(), "events.ics")
+	if err := WriteICS(path, events); err != nil {
+		t.Fatalf("WriteICS error: %v", err)
+	}
+
+	parsed, err := ReadICS(path)
+	if err != nil {
+		t.Fatalf("ReadICS error: %v", err)
+	}
+	if len(parsed) != 1 || !slices.Equal(parsed[0].Tags, events[0].Tags) {
+		t.Fatalf("Tags = %q, want %q", parsed[0].Tags, events[0].Tags)
+	}
+}
+
+func TestParseEvent_Status(t *testing.T) {
+	tests := []struct {
+		prop string
+		want EventStatus
+	}{
+		{"", StatusConfirmed},
+		{"STATUS:CONFIRMED", StatusConfirmed},
+		{"STATUS:TENTATIVE", StatusTentative},
+		{"STATUS:cancelled", StatusCancelled},
+	}
+
+	for _, tt := range tests {
+		lines := []string{
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:test-status",
+			"SUMMARY:Sync",
+			"DTSTART:20260217T100000Z",
+			"DTEND:20260217T110000Z",
+		}
+		if tt.prop != "" {
+			lines = append(lines, tt.prop)
+		}
+		lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+		cal, err := ics.NewDecoder(strings.NewReader(strings.Join(lines, "\r\n"))).Decode()
+		if err != nil {
+			t.Fatalf("failed to decode ICS: %v", err)
+		}
+
+		s := &ICSSource{name: "test", end: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+		events, err := s.parseEvent(cal.Children[0])
+		if err != nil {
+			t.Fatalf("parseEvent error: %v", err)
+		}
+		if got := events[0].Status; got != tt.want {
+			t.Errorf("%q: Status = %q, want %q", tt.prop, got, tt.want)
+		}
+	}
+}
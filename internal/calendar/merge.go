@@ -21,6 +21,11 @@ const (
 	xCalbarMeetingPasscode          = "X-CALBAR-MEETING-PASSCODE"
 	xCalbarMeetingDialIn            = "X-CALBAR-MEETING-DIALIN"
 	xCalbarMeetingPhoneConferenceID = "X-CALBAR-MEETING-PHONE-CONFERENCE-ID"
+	xCalbarColor                    = "X-CALBAR-COLOR"
+	xCalbarResponse                 = "X-CALBAR-RESPONSE"
+
+	// xMicrosoftBusyStatus is how Outlook and Exchange export "show as".
+	xMicrosoftBusyStatus = "X-MICROSOFT-CDO-BUSYSTATUS"
 )
 
 // Merge combines events from multiple sources into a single slice.
@@ -48,7 +53,30 @@ func WriteICS(path string, events []Event) error {
 		return fmt.Errorf("create directory: %w", err)
 	}
 
-	// Create the ICS content
+	// Encode to buffer
+	var buf bytes.Buffer
+	if err := EncodeICS(&buf, events, time.Now()); err != nil {
+		return err
+	}
+
+	// Write to temp file
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	// Atomic rename
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath) // Clean up temp file on error
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// EncodeICS writes events to w as an ICS calendar, with stamp as the
+// DTSTAMP of every event.
+func EncodeICS(w io.Writer, events []Event, stamp time.Time) error {
 	cal := ics.NewCalendar()
 	cal.Props.SetText(ics.PropVersion, "2.0")
 	cal.Props.SetText(ics.PropProductID, "-//CalBar//CalBar//EN")
@@ -60,7 +88,7 @@ func WriteICS(path string, events []Event) error {
 		comp.Props.SetText(ics.PropSummary, event.Summary)
 
 		// DTSTAMP is required by the ICS spec
-		comp.Props.SetDateTime(ics.PropDateTimeStamp, time.Now())
+		comp.Props.SetDateTime(ics.PropDateTimeStamp, stamp)
 
 		if event.Description != "" {
 			comp.Props.SetText(ics.PropDescription, event.Description)
@@ -92,6 +120,23 @@ func WriteICS(path string, events []Event) error {
 		if event.Organizer != "" {
 			comp.Props.SetText(ics.PropOrganizer, "mailto:"+event.Organizer)
 		}
+		for _, attendee := range event.Attendees {
+			comp.Props.Add(attendeeProp(attendee))
+		}
+		if event.Status != "" {
+			comp.Props.SetText(ics.PropStatus, string(event.Status))
+		}
+		if event.Response != "" {
+			comp.Props.SetText(xCalbarResponse, string(event.Response))
+		}
+		if event.FreeBusy != "" {
+			transp := "OPAQUE"
+			if event.IsFree() {
+				transp = "TRANSPARENT"
+			}
+			comp.Props.SetText(ics.PropTransparency, transp)
+			comp.Props.SetText(xMicrosoftBusyStatus, string(event.FreeBusy))
+		}
 
 		// Set start/end times
 		if event.AllDay {
@@ -104,29 +149,21 @@ func WriteICS(path string, events []Event) error {
 
 		// Add custom property for source
 		comp.Props.SetText(xCalbarSource, event.Source)
+		if event.Color != "" {
+			comp.Props.SetText(xCalbarColor, event.Color)
+		}
+		if len(event.Tags) > 0 {
+			prop := ics.NewProp(ics.PropCategories)
+			prop.SetTextList(event.Tags)
+			comp.Props.Set(prop)
+		}
 
 		cal.Children = append(cal.Children, comp)
 	}
 
-	// Encode to buffer
-	var buf bytes.Buffer
-	enc := ics.NewEncoder(&buf)
-	if err := enc.Encode(cal); err != nil {
+	if err := ics.NewEncoder(w).Encode(cal); err != nil {
 		return fmt.Errorf("encode ICS: %w", err)
 	}
-
-	// Write to temp file
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o644); err != nil {
-		return fmt.Errorf("write temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, path); err != nil {
-		os.Remove(tmpPath) // Clean up temp file on error
-		return fmt.Errorf("rename temp file: %w", err)
-	}
-
 	return nil
 }
 
@@ -218,10 +255,27 @@ func parseEventComponent(comp *ics.Component) (Event, error) {
 		}
 	}
 
+	event.Attendees = parseAttendees(comp)
+
 	// Source (custom property)
 	if prop := comp.Props.Get(xCalbarSource); prop != nil {
 		event.Source = prop.Value
 	}
+	if prop := comp.Props.Get(xCalbarColor); prop != nil {
+		event.Color = NormalizeColor(prop.Value)
+	}
+	if prop := comp.Props.Get(xCalbarResponse); prop != nil {
+		event.Response = PartStat(prop.Value)
+	}
+	for _, prop := range comp.Props.Values(ics.PropCategories) {
+		tags, err := prop.TextList()
+		if err != nil {
+			continue
+		}
+		event.Tags = append(event.Tags, tags...)
+	}
+	event.Status = parseEventStatus(comp)
+	event.FreeBusy = parseFreeBusy(comp)
 	parseCalbarMeetingProps(comp, &event)
 
 	// Start time
@@ -280,6 +334,80 @@ func parseCalbarMeetingProps(comp *ics.Component, event *Event) {
 	}
 }
 
+// parseAttendees returns the ATTENDEE properties of a component.
+func parseAttendees(comp *ics.Component) []Attendee {
+	props := comp.Props.Values(ics.PropAttendee)
+	if len(props) == 0 {
+		return nil
+	}
+	attendees := make([]Attendee, 0, len(props))
+	for i := range props {
+		prop := &props[i]
+		attendees = append(attendees, Attendee{
+			Name:   prop.Params.Get(ics.ParamCommonName),
+			Email:  attendeeEmail(prop.Value),
+			Role:   prop.Params.Get(ics.ParamRole),
+			Status: attendeePartStat(prop),
+		})
+	}
+	return attendees
+}
+
+// parseEventStatus returns the STATUS of a component. Events without one are
+// treated as confirmed.
+func parseEventStatus(comp *ics.Component) EventStatus {
+	if prop := comp.Props.Get(ics.PropStatus); prop != nil {
+		switch status := EventStatus(strings.ToUpper(strings.TrimSpace(prop.Value))); status {
+		case StatusTentative, StatusCancelled:
+			return status
+		}
+	}
+	return StatusConfirmed
+}
+
+// parseFreeBusy returns how a component shows on the user's schedule. The
+// Microsoft busy status is preferred since it is more specific than TRANSP.
+func parseFreeBusy(comp *ics.Component) FreeBusy {
+	if prop := comp.Props.Get(xMicrosoftBusyStatus); prop != nil && prop.Value != "" {
+		return FreeBusy(strings.ToUpper(strings.TrimSpace(prop.Value)))
+	}
+	if prop := comp.Props.Get(ics.PropTransparency); prop != nil {
+		switch strings.ToUpper(strings.TrimSpace(prop.Value)) {
+		case "TRANSPARENT":
+			return FreeBusyFree
+		case "OPAQUE":
+			return FreeBusyBusy
+		}
+	}
+	return ""
+}
+
+// attendeeEmail strips the "mailto:" scheme from a calendar user address,
+// keeping the address's case for display.
+func attendeeEmail(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if len(addr) >= 7 && strings.EqualFold(addr[:7], "mailto:") {
+		return addr[7:]
+	}
+	return addr
+}
+
+// attendeeProp builds an ATTENDEE property for an attendee.
+func attendeeProp(a Attendee) *ics.Prop {
+	prop := ics.NewProp(ics.PropAttendee)
+	prop.Value = "mailto:" + a.Email
+	if a.Name != "" {
+		prop.Params.Set(ics.ParamCommonName, a.Name)
+	}
+	if a.Role != "" {
+		prop.Params.Set(ics.ParamRole, a.Role)
+	}
+	if a.Status != "" {
+		prop.Params.Set(ics.ParamParticipationStatus, string(a.Status))
+	}
+	return prop
+}
+
 func unescapeICSText(s string) string {
 	s = strings.ReplaceAll(s, `\\`, `\`)
 	s = strings.ReplaceAll(s, `\n`, "\n")
@@ -16,11 +16,11 @@ import (
 )
 
 const (
-	// MS Graph API endpoint for calendar events
-	graphCalendarEndpoint = "https://graph.microsoft.com/v1.0/me/calendarView"
+	// MS Graph API base URL
+	graphBaseURL = "https://graph.microsoft.com/v1.0"
 
-	// Required scope for reading calendars
-	calendarReadScope = "Calendars.Read"
+	// Required scope for reading calendars and responding to invitations
+	calendarReadWriteScope = "Calendars.ReadWrite"
 )
 
 // tokenProvider can acquire access tokens.
@@ -32,6 +32,7 @@ type tokenProvider interface {
 // MS365Source fetches events from Microsoft 365 calendar via Graph API.
 type MS365Source struct {
 	name     string
+	baseURL  string // Graph API base URL; overridden in tests
 	auth     tokenProvider
 	client   *http.Client
 	initOnce sync.Once
@@ -41,7 +42,8 @@ type MS365Source struct {
 // NewMS365Source creates a new MS365 calendar source.
 func NewMS365Source(name string) *MS365Source {
 	return &MS365Source{
-		name: name,
+		name:    name,
+		baseURL: graphBaseURL,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -52,7 +54,7 @@ func NewMS365Source(name string) *MS365Source {
 // Tries broker first, falls back to device code flow.
 func (s *MS365Source) initAuth(ctx context.Context) error {
 	s.initOnce.Do(func() {
-		scopes := []string{calendarReadScope}
+		scopes := []string{calendarReadWriteScope}
 
 		// Try broker first
 		broker := auth.NewBroker("", scopes)
@@ -81,15 +83,10 @@ func (s *MS365Source) Name() string {
 
 // Fetch retrieves events from Microsoft 365 calendar.
 func (s *MS365Source) Fetch(ctx context.Context, end time.Time) ([]Event, error) {
-	// Initialize auth on first fetch
-	if err := s.initAuth(ctx); err != nil {
-		return nil, err
-	}
-
-	// Get access token
-	token, err := s.auth.GetToken(ctx)
+	// Initializes auth on first use
+	token, err := s.accessToken(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("get token: %w", err)
+		return nil, err
 	}
 
 	// Fetch events from Graph API
@@ -97,14 +94,254 @@ func (s *MS365Source) Fetch(ctx context.Context, end time.Time) ([]Event, error)
 	// earlier today but should still be visible in the UI.
 	start := time.Now().Add(-24 * time.Hour)
 
-	events, err := s.fetchCalendarView(ctx, token.AccessToken, start, end)
+	events, err := s.fetchCalendarView(ctx, token, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("fetch calendar: %w", err)
 	}
 
+	if color := s.fetchCalendarColor(ctx, token); color != "" {
+		for i := range events {
+			events[i].Color = color
+		}
+	}
+
 	return events, nil
 }
 
+// accessToken initializes auth if needed and returns a Graph access token.
+func (s *MS365Source) accessToken(ctx context.Context) (string, error) {
+	if err := s.initAuth(ctx); err != nil {
+		return "", err
+	}
+	token, err := s.auth.GetToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get token: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+// graphResponseActions maps participation statuses to the Graph event
+// actions that set them.
+var graphResponseActions = map[PartStat]string{
+	PartStatAccepted:  "accept",
+	PartStatTentative: "tentativelyAccept",
+	PartStatDeclined:  "decline",
+}
+
+// Respond accepts, tentatively accepts or declines an invitation and sends
+// the response to the organizer.
+func (s *MS365Source) Respond(ctx context.Context, event Event, status PartStat) error {
+	action, ok := graphResponseActions[status]
+	if !ok {
+		return fmt.Errorf("unsupported response %q", status)
+	}
+	if event.Remote.ID == "" {
+		return fmt.Errorf("event %q has no Graph event ID", event.Summary)
+	}
+
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	body := strings.NewReader(`{"sendResponse":true}`)
+	reqURL := s.baseURL + "/me/events/" + url.PathEscape(event.Remote.ID) + "/" + action
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("graph API error: status %d: %s", resp.StatusCode, string(msg))
+	}
+
+	slog.Info("responded to invitation", "source", s.name, "summary", event.Summary, "response", status)
+	return nil
+}
+
+// Create adds a new event to the named calendar, or the default calendar if
+// calendarName is empty.
+func (s *MS365Source) Create(ctx context.Context, event Event, calendarName string) (Event, error) {
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return Event{}, err
+	}
+
+	reqURL := s.baseURL + "/me/events"
+	var color string
+	if calendarName != "" {
+		cal, err := s.findCalendar(ctx, token, calendarName)
+		if err != nil {
+			return Event{}, err
+		}
+		reqURL = s.baseURL + "/me/calendars/" + url.PathEscape(cal.ID) + "/events"
+		color = cal.color()
+	} else {
+		color = s.fetchCalendarColor(ctx, token)
+	}
+
+	ge := graphNewEvent{
+		Subject:  event.Summary,
+		IsAllDay: event.AllDay,
+		Start:    graphDateTimeOf(event.Start, event.AllDay),
+		End:      graphDateTimeOf(event.End, event.AllDay),
+	}
+	if event.Location != "" {
+		ge.Location = &graphLocation{DisplayName: event.Location}
+	}
+	if event.Description != "" {
+		ge.Body = &graphBody{ContentType: "text", Content: event.Description}
+	}
+	body, err := json.Marshal(ge)
+	if err != nil {
+		return Event{}, fmt.Errorf("encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(string(body)))
+	if err != nil {
+		return Event{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", `outlook.timezone="UTC", outlook.body-content-type="text"`)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Event{}, fmt.Errorf("create event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(resp.Body)
+		return Event{}, fmt.Errorf("graph API error: status %d: %s", resp.StatusCode, string(msg))
+	}
+
+	var created graphEvent
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return Event{}, fmt.Errorf("decode response: %w", err)
+	}
+	result, err := s.convertEvent(created)
+	if err != nil {
+		return Event{}, err
+	}
+	result.Color = color
+
+	slog.Info("created event", "source", s.name, "calendar", calendarName, "summary", event.Summary)
+	return result, nil
+}
+
+// findCalendar looks up one of the user's calendars by name.
+func (s *MS365Source) findCalendar(ctx context.Context, accessToken, name string) (graphCalendar, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/me/calendars?$select=id,name,color,hexColor", nil)
+	if err != nil {
+		return graphCalendar{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return graphCalendar{}, fmt.Errorf("list calendars: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return graphCalendar{}, fmt.Errorf("graph API error: status %d: %s", resp.StatusCode, string(msg))
+	}
+
+	var list struct {
+		Value []graphCalendar `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return graphCalendar{}, fmt.Errorf("decode calendars: %w", err)
+	}
+	for _, cal := range list.Value {
+		if strings.EqualFold(cal.Name, name) {
+			return cal, nil
+		}
+	}
+	return graphCalendar{}, fmt.Errorf("calendar %q not found in source %q", name, s.name)
+}
+
+// graphDateTimeOf formats a time for a Graph request. All-day events must
+// start and end at midnight, so they are sent as dates.
+func graphDateTimeOf(t time.Time, allDay bool) graphDateTime {
+	if allDay {
+		return graphDateTime{DateTime: t.Format("2006-01-02") + "T00:00:00", TimeZone: "UTC"}
+	}
+	return graphDateTime{DateTime: t.UTC().Format("2006-01-02T15:04:05"), TimeZone: "UTC"}
+}
+
+// graphCalendar is the subset of a Graph calendar resource we read.
+type graphCalendar struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Color    string `json:"color"`
+	HexColor string `json:"hexColor"`
+}
+
+// color returns the calendar's color as #rrggbb, or "" if it has none.
+func (c graphCalendar) color() string {
+	if color := NormalizeColor(c.HexColor); color != "" {
+		return color
+	}
+	return graphCalendarColors[c.Color]
+}
+
+// graphCalendarColors maps Graph calendarColor values to the hex colors Outlook
+// uses for them. "auto" has no fixed color.
+var graphCalendarColors = map[string]string{
+	"lightBlue":   "#6ba5e7",
+	"lightGreen":  "#77c26f",
+	"lightOrange": "#f09f4f",
+	"lightGray":   "#a0aeb2",
+	"lightYellow": "#e2c74a",
+	"lightTeal":   "#4ab8b3",
+	"lightPink":   "#e98ab7",
+	"lightBrown":  "#b98f6c",
+	"lightRed":    "#e27777",
+}
+
+// fetchCalendarColor returns the color of the default calendar.
+// Colors are optional, so errors are only logged.
+func (s *MS365Source) fetchCalendarColor(ctx context.Context, accessToken string) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/me/calendar?$select=color,hexColor", nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		slog.Debug("fetch MS365 calendar color", "error", err)
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		slog.Debug("fetch MS365 calendar color", "status", resp.StatusCode)
+		return ""
+	}
+
+	var cal graphCalendar
+	if err := json.NewDecoder(resp.Body).Decode(&cal); err != nil {
+		slog.Debug("decode MS365 calendar color", "error", err)
+		return ""
+	}
+	return cal.color()
+}
+
 // Close cleans up resources.
 func (s *MS365Source) Close() error {
 	if s.auth != nil {
@@ -140,6 +377,17 @@ type graphEvent struct {
 	ReminderMinutes  int                  `json:"reminderMinutesBeforeStart"`
 	ShowAs           string               `json:"showAs"`
 	ResponseStatus   *graphResponseStatus `json:"responseStatus,omitempty"`
+	Attendees        []graphAttendee      `json:"attendees,omitempty"`
+}
+
+// graphNewEvent is the body of a Graph request that creates an event.
+type graphNewEvent struct {
+	Subject  string         `json:"subject"`
+	Body     *graphBody     `json:"body,omitempty"`
+	Start    graphDateTime  `json:"start"`
+	End      graphDateTime  `json:"end"`
+	Location *graphLocation `json:"location,omitempty"`
+	IsAllDay bool           `json:"isAllDay"`
 }
 
 type graphBody struct {
@@ -193,6 +441,19 @@ type graphResponseStatus struct {
 	Time     string `json:"time"`
 }
 
+type graphAttendee struct {
+	Type         string               `json:"type"` // "required", "optional" or "resource"
+	Status       *graphResponseStatus `json:"status,omitempty"`
+	EmailAddress graphEmailAddress    `json:"emailAddress"`
+}
+
+// graphAttendeeRoles maps Graph attendee types to iCalendar roles.
+var graphAttendeeRoles = map[string]string{
+	"required": RoleRequired,
+	"optional": RoleOptional,
+	"resource": RoleNonParticipant,
+}
+
 // fetchCalendarView fetches events using the calendarView endpoint (handles recurrence expansion).
 func (s *MS365Source) fetchCalendarView(ctx context.Context, accessToken string, start, end time.Time) ([]Event, error) {
 	// Build URL with time range
@@ -202,9 +463,9 @@ func (s *MS365Source) fetchCalendarView(ctx context.Context, accessToken string,
 	params.Set("$orderby", "start/dateTime")
 	params.Set("$top", "500") // Fetch up to 500 events
 	// Request specific fields to get full details including body
-	params.Set("$select", "id,subject,bodyPreview,body,start,end,location,isAllDay,isCancelled,organizer,webLink,onlineMeetingUrl,onlineMeeting,showAs,responseStatus,seriesMasterId,recurrence,isReminderOn,reminderMinutesBeforeStart")
+	params.Set("$select", "id,subject,bodyPreview,body,start,end,location,isAllDay,isCancelled,organizer,attendees,webLink,onlineMeetingUrl,onlineMeeting,showAs,responseStatus,seriesMasterId,recurrence,isReminderOn,reminderMinutesBeforeStart")
 
-	reqURL := graphCalendarEndpoint + "?" + params.Encode()
+	reqURL := s.baseURL + "/me/calendarView?" + params.Encode()
 
 	var allEvents []Event
 
@@ -253,11 +514,6 @@ func (s *MS365Source) fetchPage(ctx context.Context, accessToken, reqURL string)
 
 	events := make([]Event, 0, len(graphResp.Value))
 	for _, ge := range graphResp.Value {
-		// Skip cancelled events
-		if ge.IsCancelled {
-			continue
-		}
-
 		event, err := s.convertEvent(ge)
 		if err != nil {
 			slog.Warn("skip event conversion error", "id", ge.ID, "error", err)
@@ -276,7 +532,16 @@ func (s *MS365Source) convertEvent(ge graphEvent) (Event, error) {
 		Source:  s.name,
 		AllDay:  ge.IsAllDay,
 		URL:     ge.WebLink,
+		Remote:  RemoteRef{ID: ge.ID},
 	}
+	if ge.ResponseStatus != nil {
+		event.Response = graphPartStat(ge.ResponseStatus.Response)
+	}
+	event.Status = StatusConfirmed
+	if ge.IsCancelled {
+		event.Status = StatusCancelled
+	}
+	event.FreeBusy = graphFreeBusy(ge.ShowAs)
 
 	// Parse start time first - needed for UID
 	start, err := parseGraphDateTime(ge.Start)
@@ -326,6 +591,18 @@ func (s *MS365Source) convertEvent(ge graphEvent) (Event, error) {
 		event.Organizer = ge.Organizer.EmailAddress.Address
 	}
 
+	for _, ga := range ge.Attendees {
+		attendee := Attendee{
+			Name:  ga.EmailAddress.Name,
+			Email: ga.EmailAddress.Address,
+			Role:  graphAttendeeRoles[ga.Type],
+		}
+		if ga.Status != nil {
+			attendee.Status = graphPartStat(ga.Status.Response)
+		}
+		event.Attendees = append(event.Attendees, attendee)
+	}
+
 	// Online meeting URL (Teams, etc.)
 	if ge.OnlineMeeting != nil && ge.OnlineMeeting.JoinURL != "" {
 		event.Meeting.URL = ge.OnlineMeeting.JoinURL
@@ -351,6 +628,42 @@ func (s *MS365Source) convertEvent(ge graphEvent) (Event, error) {
 	return event, nil
 }
 
+// graphPartStat converts a Graph responseType to a participation status.
+// Events the user organizes ("organizer") or that are not invitations
+// ("none") have no status.
+func graphPartStat(response string) PartStat {
+	switch response {
+	case "notResponded":
+		return PartStatNeedsAction
+	case "accepted":
+		return PartStatAccepted
+	case "tentativelyAccepted":
+		return PartStatTentative
+	case "declined":
+		return PartStatDeclined
+	default:
+		return ""
+	}
+}
+
+// graphFreeBusy maps a Graph showAs value to a free/busy state.
+func graphFreeBusy(showAs string) FreeBusy {
+	switch showAs {
+	case "free":
+		return FreeBusyFree
+	case "tentative":
+		return FreeBusyTentative
+	case "busy":
+		return FreeBusyBusy
+	case "oof":
+		return FreeBusyOOF
+	case "workingElsewhere":
+		return FreeBusyWorkingElsewhere
+	default:
+		return ""
+	}
+}
+
 // parseGraphDateTime parses a Graph API datetime value.
 // Times are stored in UTC; conversion to local happens at display time.
 func parseGraphDateTime(gdt graphDateTime) (time.Time, error) {
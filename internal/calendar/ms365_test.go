@@ -1,9 +1,15 @@
 package calendar
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/cpuguy83/calbar/internal/auth"
 )
 
 func TestConvertEvent_UsesGraphReminder(t *testing.T) {
@@ -170,3 +176,262 @@ ________________________________________________________________________________
 		t.Fatalf("expected real description content, got %q", event.Description)
 	}
 }
+
+// staticToken is a tokenProvider that always returns the same access token.
+type staticToken string
+
+func (t staticToken) GetToken(ctx context.Context) (*auth.Token, error) {
+	return &auth.Token{AccessToken: string(t)}, nil
+}
+
+func (staticToken) Close() error { return nil }
+
+// newTestMS365Source returns an MS365Source that talks to srv instead of Graph.
+func newTestMS365Source(srv *httptest.Server) *MS365Source {
+	s := NewMS365Source("work")
+	s.baseURL = srv.URL + "/v1.0"
+	s.client = srv.Client()
+	s.auth = staticToken("test-token")
+	s.initOnce.Do(func() {})
+	return s
+}
+
+func TestMS365Respond(t *testing.T) {
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Minute)
+
+	var gotPath, gotAuth string
+	var gotBody map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1.0/me/calendarView", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"value": []map[string]any{{
+				"id":             "AAMkAGI2-event",
+				"subject":        "Design review",
+				"start":          map[string]string{"dateTime": start.Format("2006-01-02T15:04:05"), "timeZone": "UTC"},
+				"end":            map[string]string{"dateTime": start.Add(time.Hour).Format("2006-01-02T15:04:05"), "timeZone": "UTC"},
+				"responseStatus": map[string]string{"response": "notResponded"},
+			}},
+		})
+	})
+	mux.HandleFunc("GET /v1.0/me/calendar", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("POST /v1.0/me/events/{id}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s := newTestMS365Source(srv)
+	events, err := s.Fetch(context.Background(), start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Fetch error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Remote.ID != "AAMkAGI2-event" {
+		t.Fatalf("unexpected remote ID: %q", event.Remote.ID)
+	}
+	if event.Response != PartStatNeedsAction || !event.CanRespond() {
+		t.Fatalf("expected answerable invitation, got response %q", event.Response)
+	}
+
+	if err := s.Respond(context.Background(), event, PartStatTentative); err != nil {
+		t.Fatalf("Respond error: %v", err)
+	}
+	if gotPath != "/v1.0/me/events/AAMkAGI2-event/tentativelyAccept" {
+		t.Fatalf("unexpected request path: %q", gotPath)
+	}
+	if gotAuth != "Bearer test-token" {
+		t.Fatalf("unexpected Authorization header: %q", gotAuth)
+	}
+	if gotBody["sendResponse"] != true {
+		t.Fatalf("expected sendResponse=true, got %v", gotBody)
+	}
+}
+
+func TestMS365RespondError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"code":"ErrorAccessDenied"}}`, http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	s := newTestMS365Source(srv)
+	event := Event{Summary: "Design review", Response: PartStatNeedsAction, Remote: RemoteRef{ID: "id-1"}}
+	err := s.Respond(context.Background(), event, PartStatDeclined)
+	if err == nil || !strings.Contains(err.Error(), "ErrorAccessDenied") {
+		t.Fatalf("expected Graph error, got %v", err)
+	}
+
+	if err := s.Respond(context.Background(), event, PartStatNeedsAction); err == nil {
+		t.Fatal("expected error for unsupported response")
+	}
+}
+
+func TestGraphPartStat(t *testing.T) {
+	tests := map[string]PartStat{
+		"notResponded":        PartStatNeedsAction,
+		"accepted":            PartStatAccepted,
+		"tentativelyAccepted": PartStatTentative,
+		"declined":            PartStatDeclined,
+		"organizer":           "",
+		"none":                "",
+	}
+	for in, want := range tests {
+		if got := graphPartStat(in); got != want {
+			t.Errorf("graphPartStat(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMS365Create(t *testing.T) {
+	start := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+
+	var gotPath string
+	var gotBody map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1.0/me/calendars", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value":[{"id":"cal-1","name":"Calendar"},{"id":"cal-2","name":"Focus time","hexColor":"#77C26F"}]}`))
+	})
+	mux.HandleFunc("POST /v1.0/me/calendars/{id}/events", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":      "AAMk-new",
+			"subject": gotBody["subject"],
+			"start":   gotBody["start"],
+			"end":     gotBody["end"],
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s := newTestMS365Source(srv)
+	event, err := s.Create(context.Background(), Event{
+		Summary: "Deep work",
+		Start:   start,
+		End:     start.Add(2 * time.Hour),
+	}, "focus time")
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	if gotPath != "/v1.0/me/calendars/cal-2/events" {
+		t.Fatalf("unexpected request path: %q", gotPath)
+	}
+	if gotBody["subject"] != "Deep work" {
+		t.Fatalf("unexpected subject: %v", gotBody["subject"])
+	}
+	if got := gotBody["start"].(map[string]any)["dateTime"]; got != "2026-10-15T14:00:00" {
+		t.Fatalf("unexpected start: %v", got)
+	}
+	if _, ok := gotBody["id"]; ok {
+		t.Fatal("request must not include read-only fields")
+	}
+
+	if event.Remote.ID != "AAMk-new" || event.Summary != "Deep work" || event.Source != "work" {
+		t.Fatalf("unexpected created event: %+v", event)
+	}
+	if !event.Start.Equal(start) || !event.End.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("unexpected times: %v - %v", event.Start, event.End)
+	}
+	if event.Color != "#77c26f" {
+		t.Fatalf("unexpected color: %q", event.Color)
+	}
+
+	if _, err := s.Create(context.Background(), event, "Missing"); err == nil {
+		t.Fatal("expected error for unknown calendar")
+	}
+}
+
+func TestConvertEvent_Attendees(t *testing.T) {
+	s := &MS365Source{name: "ms365"}
+
+	event, err := s.convertEvent(graphEvent{
+		ID:      "id-1",
+		Subject: "Design review",
+		Start:   graphDateTime{DateTime: "2026-05-05T12:00:00.0000000", TimeZone: "UTC"},
+		End:     graphDateTime{DateTime: "2026-05-05T13:00:00.0000000", TimeZone: "UTC"},
+		Attendees: []graphAttendee{
+			{
+				Type:         "required",
+				Status:       &graphResponseStatus{Response: "declined"},
+				EmailAddress: graphEmailAddress{Name: "Jane Doe", Address: "jane@example.com"},
+			},
+			{
+				Type:         "resource",
+				Status:       &graphResponseStatus{Response: "none"},
+				EmailAddress: graphEmailAddress{Name: "Room 1", Address: "room-1@example.com"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("convertEvent error: %v", err)
+	}
+
+	want := []Attendee{
+		{Name: "Jane Doe", Email: "jane@example.com", Role: RoleRequired, Status: PartStatDeclined},
+		{Name: "Room 1", Email: "room-1@example.com", Role: RoleNonParticipant},
+	}
+	if len(event.Attendees) != len(want) {
+		t.Fatalf("expected %d attendees, got %+v", len(want), event.Attendees)
+	}
+	for i := range want {
+		if event.Attendees[i] != want[i] {
+			t.Errorf("attendee %d = %+v, want %+v", i, event.Attendees[i], want[i])
+		}
+	}
+}
+
+func TestConvertEvent_ResponseAndShowAs(t *testing.T) {
+	s := &MS365Source{name: "ms365"}
+
+	event, err := s.convertEvent(graphEvent{
+		ID:             "id-1",
+		Subject:        "Sync",
+		Start:          graphDateTime{DateTime: "2026-05-05T12:00:00.0000000", TimeZone: "UTC"},
+		End:            graphDateTime{DateTime: "2026-05-05T13:00:00.0000000", TimeZone: "UTC"},
+		ShowAs:         "free",
+		ResponseStatus: &graphResponseStatus{Response: "declined"},
+	})
+	if err != nil {
+		t.Fatalf("convertEvent error: %v", err)
+	}
+	if event.Response != PartStatDeclined {
+		t.Errorf("Response = %q, want %q", event.Response, PartStatDeclined)
+	}
+	if event.FreeBusy != FreeBusyFree {
+		t.Errorf("FreeBusy = %q, want %q", event.FreeBusy, FreeBusyFree)
+	}
+	if event.Status != StatusConfirmed {
+		t.Errorf("Status = %q, want %q", event.Status, StatusConfirmed)
+	}
+}
+
+func TestConvertEvent_Cancelled(t *testing.T) {
+	s := &MS365Source{name: "ms365"}
+
+	event, err := s.convertEvent(graphEvent{
+		ID:          "id-1",
+		Subject:     "Canceled: Sync",
+		Start:       graphDateTime{DateTime: "2026-05-05T12:00:00.0000000", TimeZone: "UTC"},
+		End:         graphDateTime{DateTime: "2026-05-05T13:00:00.0000000", TimeZone: "UTC"},
+		IsCancelled: true,
+	})
+	if err != nil {
+		t.Fatalf("convertEvent error: %v", err)
+	}
+	if !event.IsCancelled() {
+		t.Errorf("Status = %q, want %q", event.Status, StatusCancelled)
+	}
+}
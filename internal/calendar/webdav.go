@@ -0,0 +1,119 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WebDAV properties that go-webdav does not expose.
+var (
+	propCalendarColor          = xml.Name{Space: "http://apple.com/ns/ical/", Local: "calendar-color"}
+	propCalendarUserAddressSet = xml.Name{Space: "urn:ietf:params:xml:ns:caldav", Local: "calendar-user-address-set"}
+)
+
+// davMultistatus is the subset of a WebDAV multistatus response we read.
+type davMultistatus struct {
+	Responses []davResponse `xml:"DAV: response"`
+}
+
+type davResponse struct {
+	Href     string        `xml:"DAV: href"`
+	Propstat []davPropstat `xml:"DAV: propstat"`
+}
+
+type davPropstat struct {
+	Prop   davProp `xml:"DAV: prop"`
+	Status string  `xml:"DAV: status"`
+}
+
+type davProp struct {
+	Values []davPropValue `xml:",any"`
+}
+
+// davPropValue is a single property value. Text holds character data for simple
+// properties; Hrefs holds nested DAV:href values for href-set properties.
+type davPropValue struct {
+	XMLName xml.Name
+	Text    string   `xml:",chardata"`
+	Hrefs   []string `xml:"DAV: href"`
+}
+
+// propfind issues a PROPFIND for props on target and returns the successful
+// property values keyed by response href.
+func propfind(ctx context.Context, client *http.Client, target *url.URL, depth string, props ...xml.Name) (map[string][]davPropValue, error) {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?><propfind xmlns="DAV:"><prop>`)
+	for _, p := range props {
+		fmt.Fprintf(&body, `<%s xmlns="%s"/>`, p.Local, p.Space)
+	}
+	body.WriteString(`</prop></propfind>`)
+
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", target.String(), &body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", depth)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("PROPFIND %s: unexpected status %s", target.Path, resp.Status)
+	}
+
+	var ms davMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("decode multistatus: %w", err)
+	}
+
+	result := make(map[string][]davPropValue, len(ms.Responses))
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstat {
+			if !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			result[davPath(r.Href)] = append(result[davPath(r.Href)], ps.Prop.Values...)
+		}
+	}
+	return result, nil
+}
+
+// davPath returns the path of an href with any trailing slash removed, so that
+// hrefs from different responses can be compared.
+func davPath(href string) string {
+	if u, err := url.Parse(href); err == nil {
+		href = u.Path
+	}
+	return strings.TrimSuffix(href, "/")
+}
+
+// davPropText returns the text of the named property, if present.
+func davPropText(values []davPropValue, name xml.Name) string {
+	for _, v := range values {
+		if v.XMLName == name {
+			return strings.TrimSpace(v.Text)
+		}
+	}
+	return ""
+}
+
+// davPropHrefs returns the DAV:href values of the named property, if present.
+func davPropHrefs(values []davPropValue, name xml.Name) []string {
+	for _, v := range values {
+		if v.XMLName == name {
+			return v.Hrefs
+		}
+	}
+	return nil
+}
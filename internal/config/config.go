@@ -2,23 +2,38 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/cpuguy83/calbar/internal/secret"
 	"gopkg.in/yaml.v3"
 )
 
+// hexColorRe matches the colors accepted for source accents.
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// secretLookupTimeout bounds Secret Service lookups, including any unlock prompt.
+const secretLookupTimeout = 2 * time.Minute
+
 // Config is the root configuration structure.
 type Config struct {
 	Sync          SyncConfig         `yaml:"sync"`
 	Sources       []SourceConfig     `yaml:"sources"`
 	Filters       FilterConfig       `yaml:"filters"`
+	Transforms    []TransformConfig  `yaml:"transforms"`
 	Notifications NotificationConfig `yaml:"notifications"`
 	UI            UIConfig           `yaml:"ui"`
+	QuickAdd      QuickAddConfig     `yaml:"quick_add"`
+	Availability  AvailabilityConfig `yaml:"availability"`
+	Server        ServerConfig       `yaml:"server"`
+	Hooks         []HookConfig       `yaml:"hooks"`
+	DND           DNDConfig          `yaml:"dnd"`
 }
 
 // SyncConfig configures the sync loop.
@@ -35,15 +50,21 @@ type SyncConfig struct {
 // Each sensitive field (url, username, password) has a corresponding _cmd variant
 // that executes a shell command to retrieve the value at runtime.
 // If both a field and its _cmd variant are set, the direct value takes precedence.
+//
+// The password can also be looked up in the Secret Service (GNOME Keyring, KeePassXC,
+// KWallet) with password_secret (explicit item attributes) or keyring (calbar's own
+// attributes, as stored by "calbar secret set").
 type SourceConnectionConfig struct {
-	Type        string   `yaml:"type"` // "ics", "caldav", "icloud", "ms365"
-	URL         string   `yaml:"url"`
-	URLCmd      string   `yaml:"url_cmd,omitempty"`
-	Username    string   `yaml:"username,omitempty"`
-	UsernameCmd string   `yaml:"username_cmd,omitempty"`
-	Password    string   `yaml:"password,omitempty"`
-	PasswordCmd string   `yaml:"password_cmd,omitempty"`
-	Calendars   []string `yaml:"calendars,omitempty"` // For CalDAV/iCloud/MS365: which calendars to sync
+	Type           string            `yaml:"type"` // "ics", "caldav", "icloud", "ms365"
+	URL            string            `yaml:"url"`
+	URLCmd         string            `yaml:"url_cmd,omitempty"`
+	Username       string            `yaml:"username,omitempty"`
+	UsernameCmd    string            `yaml:"username_cmd,omitempty"`
+	Password       string            `yaml:"password,omitempty"`
+	PasswordCmd    string            `yaml:"password_cmd,omitempty"`
+	PasswordSecret map[string]string `yaml:"password_secret,omitempty"` // Secret Service attributes of the password item
+	Keyring        bool              `yaml:"keyring,omitempty"`         // Look up the password stored by "calbar secret set"
+	Calendars      []string          `yaml:"calendars,omitempty"`       // For CalDAV/iCloud/MS365: which calendars to sync
 }
 
 // isEmpty returns true if no connection fields are set.
@@ -52,6 +73,7 @@ func (s *SourceConnectionConfig) isEmpty() bool {
 		s.URL == "" && s.URLCmd == "" &&
 		s.Username == "" && s.UsernameCmd == "" &&
 		s.Password == "" && s.PasswordCmd == "" &&
+		len(s.PasswordSecret) == 0 && !s.Keyring &&
 		len(s.Calendars) == 0
 }
 
@@ -60,9 +82,11 @@ func (s *SourceConnectionConfig) isEmpty() bool {
 // If config_cmd is set, inline connection fields (type, url, username, password, password_cmd, calendars)
 // must not be set — the command output provides them.
 type SourceConfig struct {
-	Name      string       `yaml:"name"`
-	ConfigCmd string       `yaml:"config_cmd,omitempty"` // Command that outputs connection config as YAML/JSON
-	Filters   FilterConfig `yaml:"filters,omitempty"`    // Per-source filters (include/exclude)
+	Name       string            `yaml:"name"`
+	ConfigCmd  string            `yaml:"config_cmd,omitempty"` // Command that outputs connection config as YAML/JSON
+	Filters    FilterConfig      `yaml:"filters,omitempty"`    // Per-source filters (include/exclude)
+	Transforms []TransformConfig `yaml:"transforms,omitempty"` // Per-source transforms, applied before the filters
+	Color      string            `yaml:"color,omitempty"`      // Accent color ("#rrggbb"); overrides calendar colors from the server
 
 	SourceConnectionConfig `yaml:",inline"` // Inline connection fields (mutually exclusive with config_cmd)
 }
@@ -74,16 +98,28 @@ type FilterConfig struct {
 }
 
 // FilterRule defines a single filter rule.
-// Use exactly one of: Contains, Exact, Prefix, Suffix, or Regex.
+// A rule is either a match on Field, a group (All, Any or Not), or an Expr.
+// Field matches use exactly one of: Contains, Exact, Prefix, Suffix, Regex,
+// In, Lt, Gt, or Between. Boolean fields (all_day, has_meeting) may omit the
+// operator to match when the field is true.
 type FilterRule struct {
-	Field           string `yaml:"field"`              // "title", "organizer", "source", "description", "location"
-	Contains        string `yaml:"contains,omitempty"` // Substring match
-	Exact           string `yaml:"exact,omitempty"`    // Exact string match
-	Prefix          string `yaml:"prefix,omitempty"`   // Starts with
-	Suffix          string `yaml:"suffix,omitempty"`   // Ends with
-	Regex           string `yaml:"regex,omitempty"`    // Regular expression
-	CaseInsensitive bool   `yaml:"case_insensitive"`
-	Exclude         bool   `yaml:"exclude,omitempty"` // If true, exclude matching events instead of including
+	Field           string   `yaml:"field"`              // "title", "organizer", "source", "description", "location", "attendee", "status", "meeting_service", "tag", "start_time", "weekday", "duration", "all_day", "has_meeting"
+	Contains        string   `yaml:"contains,omitempty"` // Substring match
+	Exact           string   `yaml:"exact,omitempty"`    // Exact string match
+	Prefix          string   `yaml:"prefix,omitempty"`   // Starts with
+	Suffix          string   `yaml:"suffix,omitempty"`   // Ends with
+	Regex           string   `yaml:"regex,omitempty"`    // Regular expression
+	In              []string `yaml:"in,omitempty"`       // Equal to any of the values
+	Lt              string   `yaml:"lt,omitempty"`       // Less than (start_time, weekday, duration)
+	Gt              string   `yaml:"gt,omitempty"`       // Greater than (start_time, weekday, duration)
+	Between         []string `yaml:"between,omitempty"`  // Inclusive [low, high] range (start_time, weekday, duration)
+	CaseInsensitive bool     `yaml:"case_insensitive"`
+	Exclude         bool     `yaml:"exclude,omitempty"` // If true, exclude matching events instead of including
+
+	All  []FilterRule `yaml:"all,omitempty"`  // Matches if every nested rule matches
+	Any  []FilterRule `yaml:"any,omitempty"`  // Matches if any nested rule matches
+	Not  *FilterRule  `yaml:"not,omitempty"`  // Matches if the nested rule does not
+	Expr string       `yaml:"expr,omitempty"` // Filter expression, e.g. title ~ "standup" && source == "Work"
 
 	// Deprecated: Use Contains, Exact, Prefix, Suffix, or Regex instead.
 	// Kept for backward compatibility. If set and no other match type is specified,
@@ -91,10 +127,45 @@ type FilterRule struct {
 	Match string `yaml:"match,omitempty"`
 }
 
+// TransformConfig configures a transform: the events it applies to and the
+// changes it makes to them. At least one change must be set.
+type TransformConfig struct {
+	Match          *FilterRule     `yaml:"match,omitempty"`           // Events to change (default: all); same syntax as a filter rule
+	Summary        *Replacement    `yaml:"summary,omitempty"`         // Rewrite the title
+	Location       *Replacement    `yaml:"location,omitempty"`        // Rewrite the location
+	NotifyBefore   []time.Duration `yaml:"notify_before,omitempty"`   // Reminder offsets for events without reminders of their own
+	NotifyOverride bool            `yaml:"notify_override,omitempty"` // Replace the event's own reminders with notify_before
+	Color          string          `yaml:"color,omitempty"`           // Accent color ("#rrggbb")
+	Tags           []string        `yaml:"tags,omitempty"`            // Tags to add
+	MeetingURL     string          `yaml:"meeting_url,omitempty"`     // Meeting URL to use instead of the detected one
+}
+
+// Replacement is a regular expression replacement. Replace may refer to
+// capture groups as $1 or ${name}.
+type Replacement struct {
+	Regex   string `yaml:"regex"`
+	Replace string `yaml:"replace"`
+}
+
 // NotificationConfig configures desktop notifications.
 type NotificationConfig struct {
-	Enabled bool            `yaml:"enabled"`
-	Before  []time.Duration `yaml:"before"`
+	Enabled bool               `yaml:"enabled"`
+	Before  []time.Duration    `yaml:"before"` // Replace event reminders with these offsets (empty = no notifications)
+	Rules   []NotificationRule `yaml:"rules"`  // Per-event overrides of Before and the urgency
+
+	Countdown        bool          `yaml:"countdown"`         // Keep one notification per event, updated with a live countdown
+	CountdownTimeout time.Duration `yaml:"countdown_timeout"` // How long the countdown stays up after the event starts (default: 5m)
+
+	Conflicts time.Duration `yaml:"conflicts"` // Notify when a sync adds a conflict starting within this long (default: 0 = off)
+}
+
+// NotificationRule overrides notification settings for events matching a
+// rule. Rules are checked in order, and for each setting the first matching
+// rule that sets it wins.
+type NotificationRule struct {
+	Match   FilterRule      `yaml:"match"`   // Events the rule applies to; same syntax as a filter rule
+	Before  []time.Duration `yaml:"before"`  // Reminder offsets (nil = unchanged, empty = no notifications)
+	Urgency string          `yaml:"urgency"` // "low", "normal" or "critical" (default: critical within 5m of the start, else normal)
 }
 
 // UIConfig configures the tray app UI.
@@ -107,6 +178,68 @@ type UIConfig struct {
 	Menu              MenuConfig     `yaml:"menu"`                // Menu-specific configuration
 	EventEndGrace     time.Duration  `yaml:"event_end_grace"`     // Keep events visible after they end (default: 5m)
 	HoverDismissDelay *time.Duration `yaml:"hover_dismiss_delay"` // Delay before dismiss on pointer-leave (default: 5s, 0 = never auto-dismiss)
+	CancelledWindow   *time.Duration `yaml:"cancelled_window"`    // Show cancelled events struck through for this long (default: 24h, 0 = hide right away)
+}
+
+// QuickAddConfig configures where quick-added events are created.
+type QuickAddConfig struct {
+	Source   string        `yaml:"source"`             // Name of a caldav, icloud or ms365 source
+	Calendar string        `yaml:"calendar,omitempty"` // Calendar within the source (default: first configured or default calendar)
+	Duration time.Duration `yaml:"duration"`           // Length of events without an end time (default: 1h)
+}
+
+// AvailabilityConfig configures how the user's own response and free/busy
+// state affect what is shown and announced.
+type AvailabilityConfig struct {
+	Identities   []string `yaml:"identities,omitempty"` // The user's email addresses, to find their response in ICS feeds
+	HideDeclined *bool    `yaml:"hide_declined"`        // Hide events the user declined (default: true)
+	DimTentative *bool    `yaml:"dim_tentative"`        // Dim events the user tentatively accepted (default: true)
+	IgnoreFree   *bool    `yaml:"ignore_free"`          // Skip "free" events for tray state and notifications (default: true)
+}
+
+// HookConfig runs commands when matching events reach a point in their
+// lifecycle. Commands run with sh -c.
+type HookConfig struct {
+	Match      *FilterRule   `yaml:"match,omitempty"`       // Events the hook runs for; same syntax as a filter rule (default: all)
+	OnReminder string        `yaml:"on_reminder,omitempty"` // Run when a reminder is due
+	OnStart    string        `yaml:"on_start,omitempty"`    // Run when the event starts
+	OnEnd      string        `yaml:"on_end,omitempty"`      // Run when the event ends
+	OnJoin     string        `yaml:"on_join,omitempty"`     // Run when you join the meeting from CalBar
+	Timeout    time.Duration `yaml:"timeout,omitempty"`     // Kill commands that run longer (default: 30s)
+}
+
+// DNDConfig turns on do-not-disturb in the notification daemon during
+// matching events.
+type DNDConfig struct {
+	Enabled  bool        `yaml:"enabled"`
+	Backend  string      `yaml:"backend"`         // "auto", "mako", "swaync", "dunst" or "gnome" (default: auto)
+	MakoMode string      `yaml:"mako_mode"`       // mako mode to add while on (default: do-not-disturb)
+	Match    *FilterRule `yaml:"match,omitempty"` // Events to turn it on during; same syntax as a filter rule (default: all)
+}
+
+// ServerConfig configures the optional HTTP server that serves the merged
+// calendar as ICS and JSON.
+type ServerConfig struct {
+	Listen   string                  `yaml:"listen"`              // "127.0.0.1:8765", or "unix:/path/to/socket" (default: disabled)
+	Token    string                  `yaml:"token,omitempty"`     // Require this token as "Authorization: Bearer <token>" or "?token=<token>"
+	TokenCmd string                  `yaml:"token_cmd,omitempty"` // Command that outputs the token
+	Profiles map[string]FilterConfig `yaml:"profiles,omitempty"`  // Extra filters, served under /<profile>/
+}
+
+// GetToken returns the server token, executing token_cmd if needed.
+// If both token and token_cmd are set, the direct value takes precedence.
+func (c *ServerConfig) GetToken() (string, error) {
+	if c.Token != "" {
+		return c.Token, nil
+	}
+	if c.TokenCmd == "" {
+		return "", nil
+	}
+	v, err := runCmd(c.TokenCmd)
+	if err != nil {
+		return "", fmt.Errorf("execute token_cmd: %w", err)
+	}
+	return v, nil
 }
 
 // MenuConfig configures the dmenu-style UI backend.
@@ -147,6 +280,11 @@ func LoadFrom(path string) (*Config, error) {
 		return nil, fmt.Errorf("read config file: %w", err)
 	}
 
+	return Parse(data)
+}
+
+// Parse parses configuration from YAML and applies defaults.
+func Parse(data []byte) (*Config, error) {
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parse config file: %w", err)
@@ -157,6 +295,9 @@ func LoadFrom(path string) (*Config, error) {
 
 	// Expand paths
 	cfg.Sync.Output = expandPath(cfg.Sync.Output)
+	if path, ok := strings.CutPrefix(cfg.Server.Listen, "unix:"); ok {
+		cfg.Server.Listen = "unix:" + expandPath(path)
+	}
 
 	return &cfg, nil
 }
@@ -176,6 +317,12 @@ func (c *Config) applyDefaults() {
 	if c.Filters.Mode == "" {
 		c.Filters.Mode = "or"
 	}
+	for name, f := range c.Server.Profiles {
+		if f.Mode == "" {
+			f.Mode = "or"
+			c.Server.Profiles[name] = f
+		}
+	}
 	if c.UI.TimeRange == 0 {
 		c.UI.TimeRange = 7 * 24 * time.Hour // Default: 7 days
 	}
@@ -195,6 +342,37 @@ func (c *Config) applyDefaults() {
 		d := 3 * time.Second
 		c.UI.HoverDismissDelay = &d // Default: 3 seconds
 	}
+	if c.UI.CancelledWindow == nil {
+		d := 24 * time.Hour
+		c.UI.CancelledWindow = &d // Default: 24 hours
+	}
+	if c.Notifications.CountdownTimeout == 0 {
+		c.Notifications.CountdownTimeout = 5 * time.Minute
+	}
+	if c.DND.Backend == "" {
+		c.DND.Backend = "auto"
+	}
+	if c.DND.MakoMode == "" {
+		c.DND.MakoMode = "do-not-disturb"
+	}
+	for i := range c.Hooks {
+		if c.Hooks[i].Timeout == 0 {
+			c.Hooks[i].Timeout = 30 * time.Second
+		}
+	}
+	if c.QuickAdd.Duration == 0 {
+		c.QuickAdd.Duration = time.Hour
+	}
+	enabled := true
+	if c.Availability.HideDeclined == nil {
+		c.Availability.HideDeclined = &enabled
+	}
+	if c.Availability.DimTentative == nil {
+		c.Availability.DimTentative = &enabled
+	}
+	if c.Availability.IgnoreFree == nil {
+		c.Availability.IgnoreFree = &enabled
+	}
 }
 
 // runCmd executes a shell command and returns its trimmed stdout.
@@ -239,18 +417,30 @@ func (s *SourceConnectionConfig) GetUsername() (string, error) {
 	return v, nil
 }
 
-// GetPassword returns the password for a source, executing password_cmd if needed.
-// If both password and password_cmd are set, the direct value takes precedence.
+// GetPassword returns the password for a source, executing password_cmd or
+// querying the Secret Service if needed.
+// Precedence is password, then password_cmd, then password_secret.
 func (s *SourceConnectionConfig) GetPassword() (string, error) {
 	if s.Password != "" {
 		return s.Password, nil
 	}
-	if s.PasswordCmd == "" {
+	if s.PasswordCmd != "" {
+		v, err := runCmd(s.PasswordCmd)
+		if err != nil {
+			return "", fmt.Errorf("execute password_cmd: %w", err)
+		}
+		return v, nil
+	}
+	if len(s.PasswordSecret) == 0 {
 		return "", nil
 	}
-	v, err := runCmd(s.PasswordCmd)
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretLookupTimeout)
+	defer cancel()
+
+	v, err := secret.Get(ctx, s.PasswordSecret)
 	if err != nil {
-		return "", fmt.Errorf("execute password_cmd: %w", err)
+		return "", fmt.Errorf("look up password_secret: %w", err)
 	}
 	return v, nil
 }
@@ -263,13 +453,21 @@ func (s *SourceConfig) Validate() error {
 		return fmt.Errorf("source name is required")
 	}
 
+	if s.Color != "" && !hexColorRe.MatchString(s.Color) {
+		return fmt.Errorf("source %q: color %q must be a hex color like \"#3584e4\"", s.Name, s.Color)
+	}
+
 	if s.ConfigCmd != "" {
 		if !s.SourceConnectionConfig.isEmpty() {
-			return fmt.Errorf("source %q: config_cmd and inline connection fields (type, url, url_cmd, username, username_cmd, password, password_cmd, calendars) are mutually exclusive", s.Name)
+			return fmt.Errorf("source %q: config_cmd and inline connection fields (type, url, url_cmd, username, username_cmd, password, password_cmd, password_secret, keyring, calendars) are mutually exclusive", s.Name)
 		}
 		return nil
 	}
 
+	if s.Keyring && len(s.PasswordSecret) > 0 {
+		return fmt.Errorf("source %q: keyring and password_secret are mutually exclusive", s.Name)
+	}
+
 	if s.Type == "" {
 		return fmt.Errorf("source %q: type is required when config_cmd is not set", s.Name)
 	}
@@ -280,8 +478,10 @@ func (s *SourceConfig) Validate() error {
 // ResolvedSource contains the fully resolved configuration for a calendar source,
 // with connection details either from inline fields or from config_cmd output.
 type ResolvedSource struct {
-	Name    string
-	Filters FilterConfig
+	Name       string
+	Filters    FilterConfig
+	Transforms []TransformConfig
+	Color      string
 	SourceConnectionConfig
 }
 
@@ -294,12 +494,15 @@ func (s *SourceConfig) Resolve() (*ResolvedSource, error) {
 	}
 
 	resolved := &ResolvedSource{
-		Name:    s.Name,
-		Filters: s.Filters,
+		Name:       s.Name,
+		Filters:    s.Filters,
+		Transforms: s.Transforms,
+		Color:      s.Color,
 	}
 
 	if s.ConfigCmd == "" {
 		resolved.SourceConnectionConfig = s.SourceConnectionConfig
+		resolved.resolveKeyring()
 		return resolved, nil
 	}
 
@@ -319,13 +522,22 @@ func (s *SourceConfig) Resolve() (*ResolvedSource, error) {
 	}
 
 	resolved.SourceConnectionConfig = conn
+	resolved.resolveKeyring()
 	return resolved, nil
 }
 
-// parseDuration parses a duration string with support for days (d) and weeks (w).
+// resolveKeyring turns keyring: true into the Secret Service attributes that
+// "calbar secret set" stores the source's password under.
+func (r *ResolvedSource) resolveKeyring() {
+	if r.Keyring && len(r.PasswordSecret) == 0 {
+		r.PasswordSecret = secret.SourceAttributes(r.Name, "password")
+	}
+}
+
+// ParseDuration parses a duration string with support for days (d) and weeks (w).
 // Examples: "14d" (14 days), "2w" (2 weeks), "5m" (5 minutes), "1h" (1 hour).
 // Falls back to time.ParseDuration for standard Go duration formats.
-func parseDuration(s string) (time.Duration, error) {
+func ParseDuration(s string) (time.Duration, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return 0, nil
@@ -385,14 +597,14 @@ func (c *SyncConfig) UnmarshalYAML(node *yaml.Node) error {
 	}
 
 	if raw.Interval != "" {
-		d, err := parseDuration(raw.Interval)
+		d, err := ParseDuration(raw.Interval)
 		if err != nil {
 			return fmt.Errorf("parse interval: %w", err)
 		}
 		c.Interval = d
 	}
 	if raw.TimeRange != "" {
-		d, err := parseDuration(raw.TimeRange)
+		d, err := ParseDuration(raw.TimeRange)
 		if err != nil {
 			return fmt.Errorf("parse time_range: %w", err)
 		}
@@ -405,20 +617,109 @@ func (c *SyncConfig) UnmarshalYAML(node *yaml.Node) error {
 // UnmarshalYAML implements custom unmarshaling for notification config.
 func (c *NotificationConfig) UnmarshalYAML(node *yaml.Node) error {
 	var raw struct {
-		Enabled bool     `yaml:"enabled"`
-		Before  []string `yaml:"before"`
+		Enabled          bool               `yaml:"enabled"`
+		Before           []string           `yaml:"before"`
+		Rules            []NotificationRule `yaml:"rules"`
+		Countdown        bool               `yaml:"countdown"`
+		CountdownTimeout string             `yaml:"countdown_timeout"`
+		Conflicts        string             `yaml:"conflicts"`
 	}
 	if err := node.Decode(&raw); err != nil {
 		return err
 	}
 
 	c.Enabled = raw.Enabled
-	for _, s := range raw.Before {
+	c.Rules = raw.Rules
+	c.Countdown = raw.Countdown
+	if raw.CountdownTimeout != "" {
+		d, err := ParseDuration(raw.CountdownTimeout)
+		if err != nil {
+			return fmt.Errorf("parse countdown_timeout: %w", err)
+		}
+		c.CountdownTimeout = d
+	}
+	if raw.Conflicts != "" {
+		d, err := ParseDuration(raw.Conflicts)
+		if err != nil {
+			return fmt.Errorf("parse conflicts: %w", err)
+		}
+		c.Conflicts = d
+	}
+	before, err := parseBefore(raw.Before)
+	if err != nil {
+		return err
+	}
+	c.Before = before
+	return nil
+}
+
+// UnmarshalYAML implements custom unmarshaling for notification rules.
+func (r *NotificationRule) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Match   FilterRule `yaml:"match"`
+		Before  []string   `yaml:"before"`
+		Urgency string     `yaml:"urgency"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	r.Match = raw.Match
+	r.Urgency = raw.Urgency
+	before, err := parseBefore(raw.Before)
+	if err != nil {
+		return err
+	}
+	r.Before = before
+	return nil
+}
+
+// parseBefore parses notification offsets, keeping an explicitly empty list
+// (which turns notifications off) distinct from an unset one.
+func parseBefore(values []string) ([]time.Duration, error) {
+	if values == nil {
+		return nil, nil
+	}
+	before := make([]time.Duration, 0, len(values))
+	for _, s := range values {
 		d, err := time.ParseDuration(s)
 		if err != nil {
-			return fmt.Errorf("parse notification before duration %q: %w", s, err)
+			return nil, fmt.Errorf("parse notification before duration %q: %w", s, err)
 		}
-		c.Before = append(c.Before, d)
+		before = append(before, d)
+	}
+	return before, nil
+}
+
+// UnmarshalYAML implements custom unmarshaling for transform config.
+func (c *TransformConfig) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Match          *FilterRule  `yaml:"match"`
+		Summary        *Replacement `yaml:"summary"`
+		Location       *Replacement `yaml:"location"`
+		NotifyBefore   []string     `yaml:"notify_before"`
+		NotifyOverride bool         `yaml:"notify_override"`
+		Color          string       `yaml:"color"`
+		Tags           []string     `yaml:"tags"`
+		MeetingURL     string       `yaml:"meeting_url"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	c.Match = raw.Match
+	c.Summary = raw.Summary
+	c.Location = raw.Location
+	c.NotifyOverride = raw.NotifyOverride
+	c.Color = raw.Color
+	c.Tags = raw.Tags
+	c.MeetingURL = raw.MeetingURL
+	for _, s := range raw.NotifyBefore {
+		d, err := ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("parse notify_before duration %q: %w", s, err)
+		}
+		c.NotifyBefore = append(c.NotifyBefore, d)
 	}
 	return nil
 }
@@ -434,6 +735,7 @@ func (c *UIConfig) UnmarshalYAML(node *yaml.Node) error {
 		Menu              MenuConfig `yaml:"menu"`
 		EventEndGrace     string     `yaml:"event_end_grace"`
 		HoverDismissDelay *string    `yaml:"hover_dismiss_delay"`
+		CancelledWindow   *string    `yaml:"cancelled_window"`
 	}
 	if err := node.Decode(&raw); err != nil {
 		return err
@@ -460,6 +762,13 @@ func (c *UIConfig) UnmarshalYAML(node *yaml.Node) error {
 		}
 		c.HoverDismissDelay = &d
 	}
+	if raw.CancelledWindow != nil {
+		d, err := ParseDuration(*raw.CancelledWindow)
+		if err != nil {
+			return fmt.Errorf("parse cancelled_window: %w", err)
+		}
+		c.CancelledWindow = &d
+	}
 	c.MaxEvents = raw.MaxEvents
 	c.Theme = raw.Theme
 	c.Backend = raw.Backend
@@ -467,3 +776,55 @@ func (c *UIConfig) UnmarshalYAML(node *yaml.Node) error {
 	c.Menu = raw.Menu
 	return nil
 }
+
+// UnmarshalYAML implements custom unmarshaling for hooks.
+func (c *HookConfig) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Match      *FilterRule `yaml:"match"`
+		OnReminder string      `yaml:"on_reminder"`
+		OnStart    string      `yaml:"on_start"`
+		OnEnd      string      `yaml:"on_end"`
+		OnJoin     string      `yaml:"on_join"`
+		Timeout    string      `yaml:"timeout"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	c.Match = raw.Match
+	c.OnReminder = raw.OnReminder
+	c.OnStart = raw.OnStart
+	c.OnEnd = raw.OnEnd
+	c.OnJoin = raw.OnJoin
+	if raw.Timeout != "" {
+		d, err := ParseDuration(raw.Timeout)
+		if err != nil {
+			return fmt.Errorf("parse timeout: %w", err)
+		}
+		c.Timeout = d
+	}
+	return nil
+}
+
+// UnmarshalYAML implements custom unmarshaling for quick-add config.
+func (c *QuickAddConfig) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Source   string `yaml:"source"`
+		Calendar string `yaml:"calendar"`
+		Duration string `yaml:"duration"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	if raw.Duration != "" {
+		d, err := ParseDuration(raw.Duration)
+		if err != nil {
+			return fmt.Errorf("parse quick_add duration: %w", err)
+		}
+		c.Duration = d
+	}
+	c.Source = raw.Source
+	c.Calendar = raw.Calendar
+	return nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"maps"
 	"testing"
 	"time"
 
@@ -46,13 +47,13 @@ func TestParseDuration(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			got, err := parseDuration(tt.input)
+			got, err := ParseDuration(tt.input)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("parseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				t.Errorf("ParseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
 				return
 			}
 			if got != tt.expected {
-				t.Errorf("parseDuration(%q) = %v, want %v", tt.input, got, tt.expected)
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, got, tt.expected)
 			}
 		})
 	}
@@ -206,6 +207,42 @@ func TestSourceConfigValidate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid inline with keyring",
+			cfg: SourceConfig{
+				Name: "test",
+				SourceConnectionConfig: SourceConnectionConfig{
+					Type:     "caldav",
+					URL:      "https://example.com/dav/",
+					Username: "user",
+					Keyring:  true,
+				},
+			},
+		},
+		{
+			name: "keyring with password_secret",
+			cfg: SourceConfig{
+				Name: "test",
+				SourceConnectionConfig: SourceConnectionConfig{
+					Type:           "caldav",
+					URL:            "https://example.com/dav/",
+					Keyring:        true,
+					PasswordSecret: map[string]string{"service": "caldav"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "config_cmd with inline keyring",
+			cfg: SourceConfig{
+				Name:      "test",
+				ConfigCmd: "echo test",
+				SourceConnectionConfig: SourceConnectionConfig{
+					Keyring: true,
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -298,6 +335,31 @@ func TestSourceConfigResolveConfigCmd(t *testing.T) {
 	}
 }
 
+func TestSourceConfigResolveKeyring(t *testing.T) {
+	cfg := SourceConfig{
+		Name: "Work",
+		SourceConnectionConfig: SourceConnectionConfig{
+			Type:     "caldav",
+			URL:      "https://example.com/dav/",
+			Username: "user",
+			Keyring:  true,
+		},
+	}
+
+	resolved, err := cfg.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := map[string]string{"application": "calbar", "source": "Work", "field": "password"}
+	if !maps.Equal(resolved.PasswordSecret, want) {
+		t.Errorf("PasswordSecret = %v, want %v", resolved.PasswordSecret, want)
+	}
+	if cfg.PasswordSecret != nil {
+		t.Errorf("Resolve() modified source config: PasswordSecret = %v", cfg.PasswordSecret)
+	}
+}
+
 func TestSourceConfigResolveConfigCmdJSON(t *testing.T) {
 	cfg := SourceConfig{
 		Name:      "test",
@@ -613,3 +675,210 @@ func TestNotificationConfigUnmarshalBefore(t *testing.T) {
 		t.Fatalf("unexpected notification offsets: %v", cfg.Before)
 	}
 }
+
+func TestNotificationConfigUnmarshalRules(t *testing.T) {
+	input := []byte(`
+before: []
+rules:
+  - match:
+      field: title
+      contains: all-hands
+      case_insensitive: true
+    before: [10m, 1m]
+  - match:
+      field: source
+      exact: Holidays
+    before: []
+  - match:
+      expr: 'not organizer $= "@company.com"'
+    urgency: critical
+`)
+
+	var cfg NotificationConfig
+	if err := yaml.Unmarshal(input, &cfg); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	if cfg.Before == nil || len(cfg.Before) != 0 {
+		t.Fatalf("Before = %#v, want an empty, non-nil list", cfg.Before)
+	}
+	if len(cfg.Rules) != 3 {
+		t.Fatalf("expected 3 rules, got %d", len(cfg.Rules))
+	}
+	if r := cfg.Rules[0]; r.Match.Contains != "all-hands" || len(r.Before) != 2 || r.Before[1] != time.Minute {
+		t.Errorf("rule 0 = %+v", r)
+	}
+	if r := cfg.Rules[1]; r.Before == nil || len(r.Before) != 0 {
+		t.Errorf("rule 1 Before = %#v, want an empty, non-nil list", r.Before)
+	}
+	if r := cfg.Rules[2]; r.Before != nil || r.Urgency != "critical" || r.Match.Expr == "" {
+		t.Errorf("rule 2 = %+v, want only urgency and an expression", r)
+	}
+}
+
+func TestQuickAddConfigUnmarshal(t *testing.T) {
+	input := []byte("quick_add:\n  source: Work\n  calendar: Meetings\n  duration: 30m\n")
+
+	var cfg Config
+	if err := yaml.Unmarshal(input, &cfg); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	cfg.applyDefaults()
+
+	if cfg.QuickAdd.Source != "Work" || cfg.QuickAdd.Calendar != "Meetings" {
+		t.Fatalf("unexpected quick_add target: %+v", cfg.QuickAdd)
+	}
+	if cfg.QuickAdd.Duration != 30*time.Minute {
+		t.Fatalf("QuickAdd.Duration = %v, want 30m", cfg.QuickAdd.Duration)
+	}
+
+	var defaults Config
+	defaults.applyDefaults()
+	if defaults.QuickAdd.Duration != time.Hour {
+		t.Fatalf("default QuickAdd.Duration = %v, want 1h", defaults.QuickAdd.Duration)
+	}
+}
+
+func TestAvailabilityConfigDefaults(t *testing.T) {
+	var defaults Config
+	defaults.applyDefaults()
+	a := defaults.Availability
+	if !*a.HideDeclined || !*a.DimTentative || !*a.IgnoreFree {
+		t.Fatalf("availability options should default to true: hide_declined=%v dim_tentative=%v ignore_free=%v",
+			*a.HideDeclined, *a.DimTentative, *a.IgnoreFree)
+	}
+
+	input := []byte("availability:\n  identities: [me@example.com]\n  hide_declined: false\n  ignore_free: false\n")
+	var cfg Config
+	if err := yaml.Unmarshal(input, &cfg); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	cfg.applyDefaults()
+	a = cfg.Availability
+	if len(a.Identities) != 1 || a.Identities[0] != "me@example.com" {
+		t.Fatalf("Identities = %v, want [me@example.com]", a.Identities)
+	}
+	if *a.HideDeclined || *a.IgnoreFree {
+		t.Fatalf("explicit false should be kept: hide_declined=%v ignore_free=%v", *a.HideDeclined, *a.IgnoreFree)
+	}
+	if !*a.DimTentative {
+		t.Fatalf("dim_tentative should default to true")
+	}
+}
+
+func TestUIConfigCancelledWindow(t *testing.T) {
+	var defaults Config
+	defaults.applyDefaults()
+	if *defaults.UI.CancelledWindow != 24*time.Hour {
+		t.Fatalf("default CancelledWindow = %v, want 24h", *defaults.UI.CancelledWindow)
+	}
+
+	for input, want := range map[string]time.Duration{
+		"ui:\n  cancelled_window: 2d\n": 48 * time.Hour,
+		"ui:\n  cancelled_window: 0\n":  0,
+	} {
+		var cfg Config
+		if err := yaml.Unmarshal([]byte(input), &cfg); err != nil {
+			t.Fatalf("Unmarshal(%q) error: %v", input, err)
+		}
+		cfg.applyDefaults()
+		if *cfg.UI.CancelledWindow != want {
+			t.Errorf("%q: CancelledWindow = %v, want %v", input, *cfg.UI.CancelledWindow, want)
+		}
+	}
+}
+
+func TestFilterConfigNestedUnmarshal(t *testing.T) {
+	input := []byte(`filters:
+  rules:
+    - any:
+        - all:
+            - field: title
+              contains: standup
+            - field: source
+              exact: Work
+        - not:
+            field: all_day
+    - expr: 'organizer == "boss@company.com"'
+      exclude: true
+`)
+
+	var cfg Config
+	if err := yaml.Unmarshal(input, &cfg); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	rules := cfg.Filters.Rules
+	if len(rules) != 2 || len(rules[0].Any) != 2 || len(rules[0].Any[0].All) != 2 {
+		t.Fatalf("unexpected nested rules: %+v", rules)
+	}
+	if not := rules[0].Any[1].Not; not == nil || not.Field != "all_day" {
+		t.Fatalf("Not = %+v, want all_day rule", not)
+	}
+	if rules[1].Expr != `organizer == "boss@company.com"` || !rules[1].Exclude {
+		t.Fatalf("unexpected expr rule: %+v", rules[1])
+	}
+}
+
+func TestTransformConfigUnmarshal(t *testing.T) {
+	input := []byte(`
+transforms:
+  - match:
+      field: source
+      exact: Work
+    summary:
+      regex: '^(FW|RE):\s*'
+      replace: ""
+    notify_before: [10m, 1d]
+    notify_override: true
+    tags: [work]
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+    transforms:
+      - match:
+          expr: 'location ~ "Microsoft Teams"'
+        location:
+          regex: 'Microsoft Teams Meeting;?\s*'
+          replace: ""
+        meeting_url: https://teams.microsoft.com/l/meetup-join/123
+`)
+	var cfg Config
+	if err := yaml.Unmarshal(input, &cfg); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	if len(cfg.Transforms) != 1 {
+		t.Fatalf("Transforms = %+v, want 1 transform", cfg.Transforms)
+	}
+	tr := cfg.Transforms[0]
+	if tr.Match == nil || tr.Match.Field != "source" || tr.Match.Exact != "Work" {
+		t.Errorf("Match = %+v, want source == Work", tr.Match)
+	}
+	if tr.Summary == nil || tr.Summary.Regex != `^(FW|RE):\s*` || tr.Summary.Replace != "" {
+		t.Errorf("Summary = %+v", tr.Summary)
+	}
+	if len(tr.NotifyBefore) != 2 || tr.NotifyBefore[0] != 10*time.Minute || tr.NotifyBefore[1] != 24*time.Hour {
+		t.Errorf("NotifyBefore = %v, want [10m 24h]", tr.NotifyBefore)
+	}
+	if !tr.NotifyOverride || len(tr.Tags) != 1 || tr.Tags[0] != "work" {
+		t.Errorf("NotifyOverride = %v, Tags = %v", tr.NotifyOverride, tr.Tags)
+	}
+
+	resolved, err := cfg.Sources[0].Resolve()
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if len(resolved.Transforms) != 1 || resolved.Transforms[0].MeetingURL != "https://teams.microsoft.com/l/meetup-join/123" {
+		t.Fatalf("resolved Transforms = %+v", resolved.Transforms)
+	}
+	if resolved.Transforms[0].Match.Expr == "" || resolved.Transforms[0].Location == nil {
+		t.Fatalf("source transform lost match or location: %+v", resolved.Transforms[0])
+	}
+
+	var bad Config
+	if err := yaml.Unmarshal([]byte("transforms:\n  - notify_before: [soon]\n"), &bad); err == nil {
+		t.Fatal("expected error for invalid notify_before")
+	}
+}
@@ -0,0 +1,610 @@
+// Package configcheck validates a config file and reports each problem with
+// its position in the file.
+package configcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/dnd"
+	"github.com/cpuguy83/calbar/internal/filter"
+	"github.com/cpuguy83/calbar/internal/hooks"
+	"github.com/cpuguy83/calbar/internal/notify"
+	"github.com/cpuguy83/calbar/internal/sync"
+	"github.com/cpuguy83/calbar/internal/transform"
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is a problem found in a config file.
+type Problem struct {
+	Line    int // 1-based line, or 0 if unknown
+	Column  int // 1-based column, or 0 if unknown
+	Message string
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.Line == 0:
+		return p.Message
+	case p.Column == 0:
+		return fmt.Sprintf("%d: %s", p.Line, p.Message)
+	default:
+		return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+	}
+}
+
+// Options enables the checks that run commands or use the network.
+type Options struct {
+	RunCommands bool // Run config_cmd and the *_cmd fields of each source
+	Probe       bool // Fetch each source to check it can be reached; implies RunCommands
+}
+
+// Check validates the contents of a config file and returns the problems
+// found, ordered by position.
+func Check(ctx context.Context, data []byte, opts Options) []Problem {
+	c := &checker{}
+	c.check(ctx, data, opts)
+	slices.SortStableFunc(c.problems, func(a, b Problem) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return c.problems
+}
+
+type checker struct {
+	problems []Problem
+}
+
+// addf reports a problem at node n, which may be nil.
+func (c *checker) addf(n *yaml.Node, format string, args ...any) {
+	p := Problem{Message: fmt.Sprintf(format, args...)}
+	if n != nil {
+		p.Line, p.Column = n.Line, n.Column
+	}
+	c.problems = append(c.problems, p)
+}
+
+func (c *checker) check(ctx context.Context, data []byte, opts Options) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		c.addYAMLError(&root, err)
+		return
+	}
+	if len(root.Content) == 0 {
+		c.addf(nil, "config is empty")
+		return
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		c.addf(doc, "config must be a mapping of settings")
+		return
+	}
+
+	c.checkKeys(doc, reflect.TypeFor[config.Config](), "")
+	if !c.decodeSections(doc) {
+		// Values that don't decode hide the semantic problems
+		return
+	}
+
+	cfg, err := config.Parse(data)
+	if err != nil {
+		c.addf(doc, "%v", err)
+		return
+	}
+
+	c.checkSync(doc, cfg)
+	c.checkSources(doc, cfg)
+	c.checkFilters(lookup(doc, "filters"), cfg.Filters, "filters")
+	c.checkTransforms(lookup(doc, "transforms"), cfg.Transforms, "transforms")
+	c.checkNotifications(doc, cfg)
+	c.checkUI(doc, cfg)
+	c.checkQuickAdd(doc, cfg)
+	c.checkServer(doc, cfg)
+	c.checkHooks(doc, cfg)
+	c.checkDND(doc, cfg)
+
+	if opts.RunCommands || opts.Probe {
+		c.runCommands(doc, cfg)
+	}
+	if opts.Probe {
+		c.probe(ctx, doc, cfg)
+	}
+}
+
+var yamlLineRe = regexp.MustCompile(`line (\d+): (.*)`)
+
+// addYAMLError reports an error from the YAML decoder, which carries line
+// numbers in its messages but no columns.
+func (c *checker) addYAMLError(n *yaml.Node, err error) {
+	var te *yaml.TypeError
+	if errors.As(err, &te) {
+		for _, msg := range te.Errors {
+			c.addYAMLMessage(n, msg)
+		}
+		return
+	}
+	c.addYAMLMessage(n, err.Error())
+}
+
+func (c *checker) addYAMLMessage(n *yaml.Node, msg string) {
+	m := yamlLineRe.FindStringSubmatch(msg)
+	if m == nil {
+		c.addf(n, "%s", strings.TrimPrefix(msg, "yaml: "))
+		return
+	}
+	line, _ := strconv.Atoi(m[1])
+	p := Problem{Line: line, Message: m[2]}
+	if at := nodeAtLine(n, line); at != nil {
+		p.Column = at.Column
+	}
+	c.problems = append(c.problems, p)
+}
+
+// checkKeys reports mapping keys under n that are not fields of t.
+func (c *checker) checkKeys(n *yaml.Node, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			return // Reported when decoding
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			ft, ok := fields[key.Value]
+			if !ok {
+				where := path
+				if where == "" {
+					where = "config"
+				}
+				c.addf(key, "unknown field %q in %s", key.Value, where)
+				continue
+			}
+			c.checkKeys(value, ft, joinPath(path, key.Value))
+		}
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range n.Content {
+			c.checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			c.checkKeys(n.Content[i+1], t.Elem(), joinPath(path, n.Content[i].Value))
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// yamlFields returns the YAML keys of struct type t and their types,
+// including the fields of inlined structs.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if slices.Contains(strings.Split(opts, ","), "inline") {
+			for k, v := range yamlFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// decodeSections decodes each top-level section on its own, so that a bad
+// value in one section does not hide problems in the others. It reports
+// whether every section decoded.
+func (c *checker) decodeSections(doc *yaml.Node) bool {
+	fields := yamlFields(reflect.TypeFor[config.Config]())
+	ok := true
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		ft, known := fields[doc.Content[i].Value]
+		if !known {
+			continue
+		}
+		value := doc.Content[i+1]
+		if err := value.Decode(reflect.New(ft).Interface()); err != nil {
+			ok = false
+			var te *yaml.TypeError
+			if errors.As(err, &te) {
+				c.addYAMLError(value, err)
+				continue
+			}
+			// Errors from the config's own unmarshalers have no position,
+			// but usually quote the offending value
+			c.addf(locate(value, err.Error()), "%v", err)
+		}
+	}
+	return ok
+}
+
+func (c *checker) checkSync(doc *yaml.Node, cfg *config.Config) {
+	n := lookup(doc, "sync")
+	if cfg.Sync.Interval < 0 {
+		c.addf(or(lookup(n, "interval"), n), "sync.interval must be positive")
+	}
+	if cfg.Sync.TimeRange < 0 {
+		c.addf(or(lookup(n, "time_range"), n), "sync.time_range must be positive")
+	}
+}
+
+func (c *checker) checkSources(doc *yaml.Node, cfg *config.Config) {
+	sources := lookup(doc, "sources")
+	if len(cfg.Sources) == 0 {
+		c.addf(or(sources, doc), "no sources configured")
+		return
+	}
+
+	seen := make(map[string]*yaml.Node)
+	for i, src := range cfg.Sources {
+		item := or(element(sources, i), sources)
+		name := or(lookup(item, "name"), item)
+		if prev, ok := seen[src.Name]; ok && src.Name != "" {
+			c.addf(name, "duplicate source name %q (first used on line %d)", src.Name, prev.Line)
+		} else {
+			seen[src.Name] = name
+		}
+
+		if err := src.Validate(); err != nil {
+			c.addf(locate(item, err.Error()), "%v", err)
+		} else if src.ConfigCmd == "" {
+			c.checkConnection(item, src.Name, src.SourceConnectionConfig)
+		}
+
+		what := fmt.Sprintf("source %q", src.Name)
+		c.checkFilters(lookup(item, "filters"), src.Filters, what+" filters")
+		c.checkTransforms(lookup(item, "transforms"), src.Transforms, what+" transforms")
+	}
+}
+
+// checkConnection checks the connection settings of a source, which are
+// inline at n or were output by its config_cmd.
+func (c *checker) checkConnection(n *yaml.Node, name string, conn config.SourceConnectionConfig) {
+	switch conn.Type {
+	case "ics", "caldav":
+		if conn.URL == "" && conn.URLCmd == "" {
+			c.addf(n, "source %q: url or url_cmd is required for type %s", name, conn.Type)
+		}
+	case "icloud", "ms365":
+	default:
+		c.addf(or(lookup(n, "type"), n), "source %q: unknown type %q (use ics, caldav, icloud, or ms365)", name, conn.Type)
+	}
+}
+
+// checkFilters checks each filter rule on its own so that every bad rule is
+// reported.
+func (c *checker) checkFilters(n *yaml.Node, fc config.FilterConfig, what string) {
+	if fc.Mode != "" && fc.Mode != "or" && fc.Mode != "and" {
+		c.addf(or(lookup(n, "mode"), n), "%s: unknown mode %q (use or, and)", what, fc.Mode)
+	}
+	rules := lookup(n, "rules")
+	for i, r := range fc.Rules {
+		_, err := filter.New(config.FilterConfig{Rules: []config.FilterRule{r}})
+		if err != nil {
+			c.addRuleError(or(element(rules, i), rules), fmt.Sprintf("%s: rule %d", what, i), "rule 0: ", err)
+		}
+	}
+}
+
+func (c *checker) checkTransforms(n *yaml.Node, transforms []config.TransformConfig, what string) {
+	for i, t := range transforms {
+		if _, err := transform.New([]config.TransformConfig{t}); err != nil {
+			c.addRuleError(or(element(n, i), n), fmt.Sprintf("%s: transform %d", what, i), "transform 0: ", err)
+		}
+	}
+}
+
+func (c *checker) checkNotifications(doc *yaml.Node, cfg *config.Config) {
+	notifications := lookup(doc, "notifications")
+	if cfg.Notifications.CountdownTimeout < 0 {
+		c.addf(or(lookup(notifications, "countdown_timeout"), notifications), "notifications.countdown_timeout must be positive")
+	}
+	if cfg.Notifications.Conflicts < 0 {
+		c.addf(or(lookup(notifications, "conflicts"), notifications), "notifications.conflicts must be positive")
+	}
+	rules := lookup(notifications, "rules")
+	for i, r := range cfg.Notifications.Rules {
+		_, err := notify.NewPolicy(config.NotificationConfig{Rules: []config.NotificationRule{r}})
+		if err != nil {
+			c.addRuleError(or(element(rules, i), rules), fmt.Sprintf("notifications: rule %d", i), "rule 0: ", err)
+		}
+	}
+}
+
+// addRuleError reports an error compiling a single rule at n. The error was
+// produced for a list holding only that rule, so its index prefix is
+// replaced by what. Expression syntax errors are reported at their column.
+func (c *checker) addRuleError(n *yaml.Node, what, prefix string, err error) {
+	msg := strings.TrimPrefix(err.Error(), prefix)
+
+	var se *filter.SyntaxError
+	if errors.As(err, &se) {
+		if expr := failingExpr(n); expr != nil {
+			c.addf(exprPosition(expr, se.Pos), "%s: %s", what, msg)
+			return
+		}
+	}
+	c.addf(locate(n, msg), "%s: %s", what, msg)
+}
+
+// failingExpr returns the first expr value under n that does not compile.
+func failingExpr(n *yaml.Node) *yaml.Node {
+	var found *yaml.Node
+	walk(n, func(m *yaml.Node) bool {
+		if m.Kind != yaml.MappingNode {
+			return true
+		}
+		value := lookup(m, "expr")
+		if value == nil {
+			return true
+		}
+		if _, err := filter.Compile(config.FilterRule{Expr: value.Value}); err != nil {
+			found = value
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// exprPosition returns a node positioned at column col of the expression in
+// scalar n. Block scalars start on a later line, so they keep n's position.
+func exprPosition(n *yaml.Node, col int) *yaml.Node {
+	offset := col - 1
+	switch n.Style {
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		offset++
+	case yaml.LiteralStyle, yaml.FoldedStyle:
+		return n
+	}
+	return &yaml.Node{Line: n.Line, Column: n.Column + offset}
+}
+
+func (c *checker) checkUI(doc *yaml.Node, cfg *config.Config) {
+	ui := lookup(doc, "ui")
+	switch cfg.UI.Backend {
+	case "", "auto", "gtk", "menu":
+	default:
+		c.addf(or(lookup(ui, "backend"), ui), "ui.backend: unknown backend %q (use auto, gtk, or menu)", cfg.UI.Backend)
+	}
+	switch cfg.UI.Theme {
+	case "", "system", "light", "dark":
+	default:
+		c.addf(or(lookup(ui, "theme"), ui), "ui.theme: unknown theme %q (use system, light, or dark)", cfg.UI.Theme)
+	}
+	if cfg.UI.CSSFile != "" {
+		if _, err := os.Stat(config.ResolvePath(cfg.UI.CSSFile)); err != nil {
+			c.addf(or(lookup(ui, "css_file"), ui), "ui.css_file: %v", err)
+		}
+	}
+	if cfg.UI.MaxEvents < 0 {
+		c.addf(or(lookup(ui, "max_events"), ui), "ui.max_events must not be negative")
+	}
+	if cfg.UI.TimeRange < 0 {
+		c.addf(or(lookup(ui, "time_range"), ui), "ui.time_range must be positive")
+	}
+}
+
+func (c *checker) checkQuickAdd(doc *yaml.Node, cfg *config.Config) {
+	name := cfg.QuickAdd.Source
+	if name == "" {
+		return
+	}
+	n := lookup(lookup(doc, "quick_add"), "source")
+	i := slices.IndexFunc(cfg.Sources, func(s config.SourceConfig) bool { return s.Name == name })
+	if i < 0 {
+		c.addf(n, "quick_add.source: no source named %q", name)
+		return
+	}
+	if cfg.Sources[i].Type == "ics" {
+		c.addf(n, "quick_add.source: %q is an ICS feed, which is read-only (use a caldav, icloud, or ms365 source)", name)
+	}
+}
+
+func (c *checker) checkServer(doc *yaml.Node, cfg *config.Config) {
+	server := lookup(doc, "server")
+	if listen := cfg.Server.Listen; listen != "" && !strings.HasPrefix(listen, "unix:") {
+		if _, _, err := net.SplitHostPort(listen); err != nil {
+			c.addf(or(lookup(server, "listen"), server), "server.listen: %v (use host:port or unix:/path)", err)
+		}
+	}
+	profiles := lookup(server, "profiles")
+	for name, fc := range cfg.Server.Profiles {
+		n := or(lookup(profiles, name), profiles)
+		if name == "" || strings.Contains(name, "/") {
+			c.addf(n, "server.profiles: invalid profile name %q", name)
+			continue
+		}
+		c.checkFilters(n, fc, "server profile "+strconv.Quote(name))
+	}
+}
+
+func (c *checker) checkHooks(doc *yaml.Node, cfg *config.Config) {
+	list := lookup(doc, "hooks")
+	for i, h := range cfg.Hooks {
+		if _, err := hooks.New([]config.HookConfig{h}, nil); err != nil {
+			item := or(element(list, i), list)
+			c.addRuleError(item, fmt.Sprintf("hooks: hook %d", i), "hook 0: ", err)
+		}
+	}
+}
+
+func (c *checker) checkDND(doc *yaml.Node, cfg *config.Config) {
+	n := lookup(doc, "dnd")
+	if b := cfg.DND.Backend; b != "auto" && !slices.Contains(dnd.Supported(), b) {
+		c.addf(or(lookup(n, "backend"), n), "dnd.backend: unknown backend %q (use auto, %s)", b, strings.Join(dnd.Supported(), ", "))
+	}
+	if cfg.DND.Match != nil {
+		if _, err := filter.Compile(*cfg.DND.Match); err != nil {
+			c.addRuleError(or(lookup(n, "match"), n), "dnd: match", "", err)
+		}
+	}
+}
+
+// runCommands runs each source's config_cmd and *_cmd fields and reports
+// the ones that fail.
+func (c *checker) runCommands(doc *yaml.Node, cfg *config.Config) {
+	sources := lookup(doc, "sources")
+	for i, src := range cfg.Sources {
+		if src.Validate() != nil {
+			continue // Already reported
+		}
+		item := or(element(sources, i), sources)
+
+		resolved, err := src.Resolve()
+		if err != nil {
+			c.addf(or(lookup(item, "config_cmd"), item), "%v", err)
+			continue
+		}
+		if src.ConfigCmd != "" {
+			c.checkConnection(lookup(item, "config_cmd"), src.Name, resolved.SourceConnectionConfig)
+		}
+
+		if _, err := resolved.GetURL(); err != nil {
+			c.addf(or(lookup(item, "url_cmd"), item), "source %q: %v", src.Name, err)
+		}
+		if _, err := resolved.GetUsername(); err != nil {
+			c.addf(or(lookup(item, "username_cmd"), item), "source %q: %v", src.Name, err)
+		}
+		if _, err := resolved.GetPassword(); err != nil {
+			at := or(lookup(item, "password_cmd"), or(lookup(item, "password_secret"), or(lookup(item, "keyring"), item)))
+			c.addf(at, "source %q: %v", src.Name, err)
+		}
+	}
+	if _, err := cfg.Server.GetToken(); err != nil {
+		server := lookup(doc, "server")
+		c.addf(or(lookup(server, "token_cmd"), server), "server: %v", err)
+	}
+}
+
+// probe syncs each source on its own and reports the ones that fail.
+func (c *checker) probe(ctx context.Context, doc *yaml.Node, cfg *config.Config) {
+	sources := lookup(doc, "sources")
+	for i, src := range cfg.Sources {
+		if src.Validate() != nil {
+			continue
+		}
+		item := or(element(sources, i), sources)
+
+		single := *cfg
+		single.Sources = []config.SourceConfig{src}
+		single.Filters = config.FilterConfig{}
+		single.Transforms = nil
+		syncer, err := sync.NewSyncer(&single)
+		if err != nil || syncer.SourceCount() == 0 {
+			continue // Already reported
+		}
+
+		_, failures, _ := syncer.Sync(ctx)
+		for _, f := range failures {
+			c.addf(item, "source %q: probe failed: %v", src.Name, f.Err)
+		}
+	}
+}
+
+// lookup returns the value of key in mapping n, or nil.
+func lookup(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// element returns item i of sequence n, or nil.
+func element(n *yaml.Node, i int) *yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode || i >= len(n.Content) {
+		return nil
+	}
+	return n.Content[i]
+}
+
+func or(n, fallback *yaml.Node) *yaml.Node {
+	if n != nil {
+		return n
+	}
+	return fallback
+}
+
+// walk calls fn for n and the nodes under it, depth first, until fn
+// returns false.
+func walk(n *yaml.Node, fn func(*yaml.Node) bool) bool {
+	if n == nil {
+		return true
+	}
+	if !fn(n) {
+		return false
+	}
+	for _, child := range n.Content {
+		if !walk(child, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// locate returns the scalar under n whose value the message quotes, or n.
+// Messages name the offending value after any context, such as the source
+// name, so the last quoted value wins.
+func locate(n *yaml.Node, msg string) *yaml.Node {
+	found := n
+	walk(n, func(m *yaml.Node) bool {
+		if m.Kind == yaml.ScalarNode && m.Value != "" && strings.Contains(msg, strconv.Quote(m.Value)) {
+			found = m
+		}
+		return true
+	})
+	return found
+}
+
+// nodeAtLine returns the last node under n that starts on line, which for
+// a "key: value" line is the value.
+func nodeAtLine(n *yaml.Node, line int) *yaml.Node {
+	var found *yaml.Node
+	walk(n, func(m *yaml.Node) bool {
+		if m.Line == line && m.Kind != yaml.DocumentNode {
+			found = m
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,279 @@
+package configcheck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []string // "line:col: substring" of each problem, in order
+	}{
+		{
+			name: "valid",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+filters:
+  rules:
+    - expr: title ~ "standup"
+`,
+		},
+		{
+			name: "syntax error",
+			yaml: "ui:\n  theme: dark\n  backend: gtk: menu\n",
+			want: []string{"3: mapping values are not allowed"},
+		},
+		{
+			name: "unknown keys",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+    colour: "#3584e4"
+ui:
+  backnd: gtk
+`,
+			want: []string{
+				`6:5: unknown field "colour" in sources[0]`,
+				`8:3: unknown field "backnd" in ui`,
+			},
+		},
+		{
+			name: "bad duration",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+sync:
+  interval: 5 minutes
+`,
+			want: []string{`7:13: parse interval`},
+		},
+		{
+			name: "type error",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+ui:
+  max_events: lots
+`,
+			want: []string{`7:15: cannot unmarshal !!str`},
+		},
+		{
+			name: "sources",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+  - name: Work
+    type: exchange
+  - name: Home
+    type: caldav
+    url: https://dav.example.com
+    color: blue
+`,
+			want: []string{
+				`3:5: source "Work": url or url_cmd is required`,
+				`5:11: duplicate source name "Work" (first used on line 3)`,
+				`6:11: unknown type "exchange"`,
+				`10:12: color "blue" must be a hex color`,
+			},
+		},
+		{
+			name: "no sources",
+			yaml: "ui:\n  theme: dark\n",
+			want: []string{"1:1: no sources configured"},
+		},
+		{
+			name: "filters",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+    filters:
+      rules:
+        - field: title
+          regex: "("
+filters:
+  mode: xor
+  rules:
+    - field: title
+      contains: a
+    - expr: 'title ~ "a" &&'
+    - any:
+        - expr: title = "x"
+`,
+			want: []string{
+				`9:18: source "Work" filters: rule 0: invalid regex "("`,
+				`11:9: filters: unknown mode "xor"`,
+				`15:28: filters: rule 1: expr: column 15:`,
+				`17:23: filters: rule 2: any[0]: expr: column 7:`,
+			},
+		},
+		{
+			name: "transforms and notification rules",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+transforms:
+  - match:
+      field: title
+      contains: x
+notifications:
+  rules:
+    - match:
+        field: title
+        contains: x
+      urgency: urgent
+`,
+			want: []string{
+				`7:5: transforms: transform 0: transform needs at least one of`,
+				`15:16: notifications: rule 0: unknown urgency "urgent"`,
+			},
+		},
+		{
+			name: "ui and quick add",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+ui:
+  backend: qt
+  theme: solarized
+  css_file: /nonexistent/calbar.css
+quick_add:
+  source: Work
+`,
+			want: []string{
+				`7:12: ui.backend: unknown backend "qt"`,
+				`8:10: ui.theme: unknown theme "solarized"`,
+				`9:13: ui.css_file:`,
+				`11:11: quick_add.source: "Work" is an ICS feed`,
+			},
+		},
+		{
+			name: "hooks",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+hooks:
+  - match:
+      expr: source = "Work"
+    on_start: on-air on
+  - timeout: 10s
+`,
+			want: []string{
+				`8:20: hooks: hook 0: match: expr: column 8: unexpected '='`,
+				`10:5: hooks: hook 1: needs on_reminder, on_start, on_end or on_join`,
+			},
+		},
+		{
+			name: "dnd",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+dnd:
+  enabled: true
+  backend: xfce
+  match:
+    expr: has_meeting &&
+`,
+			want: []string{
+				`8:12: dnd.backend: unknown backend "xfce" (use auto, mako, swaync, dunst, gnome)`,
+				`10:25: dnd: match: expr: column 15: expected field name`,
+			},
+		},
+		{
+			name: "server",
+			yaml: `
+sources:
+  - name: Work
+    type: ics
+    url: https://example.com/work.ics
+server:
+  listen: localhost
+  profiles:
+    work:
+      rules:
+        - expr: source = "Work"
+`,
+			want: []string{
+				`7:11: server.listen: address localhost: missing port in address`,
+				`11:24: server profile "work": rule 0: expr: column 8: unexpected '='`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := Check(context.Background(), []byte(tt.yaml), Options{})
+			if len(problems) != len(tt.want) {
+				t.Fatalf("got %d problems, want %d: %v", len(problems), len(tt.want), problems)
+			}
+			for i, p := range problems {
+				pos, msg, _ := strings.Cut(tt.want[i], ": ")
+				if got := p.String(); !strings.HasPrefix(got, pos+": ") || !strings.Contains(got, msg) {
+					t.Errorf("problem %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheck_RunCommands(t *testing.T) {
+	yaml := `
+sources:
+  - name: Work
+    type: caldav
+    url: https://dav.example.com
+    username: me
+    password_cmd: exit 1
+  - name: Home
+    config_cmd: 'echo "type: ics"'
+`
+	problems := Check(context.Background(), []byte(yaml), Options{RunCommands: true})
+	want := []string{
+		`7:19: source "Work": execute password_cmd`,
+		`9:17: source "Home": url or url_cmd is required`,
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems, want %d: %v", len(problems), len(want), problems)
+	}
+	for i, p := range problems {
+		pos, msg, _ := strings.Cut(want[i], ": ")
+		if got := p.String(); !strings.HasPrefix(got, pos+": ") || !strings.Contains(got, msg) {
+			t.Errorf("problem %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestCheck_CSSFileExists(t *testing.T) {
+	css := filepath.Join(t.TempDir(), "style.css")
+	if err := os.WriteFile(css, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	yaml := "sources:\n  - name: Work\n    type: ics\n    url: https://example.com/work.ics\nui:\n  css_file: " + css + "\n"
+	if problems := Check(context.Background(), []byte(yaml), Options{}); len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+}
@@ -0,0 +1,229 @@
+// Package dnd turns do-not-disturb on and off in notification daemons.
+package dnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Supported backends in order of preference when the notification server
+// is not recognized.
+var supportedBackends = []string{
+	"mako",
+	"swaync",
+	"dunst",
+	"gnome",
+}
+
+// controlPrograms are the programs each backend runs.
+var controlPrograms = map[string]string{
+	"mako":   "makoctl",
+	"swaync": "swaync-client",
+	"dunst":  "dunstctl",
+	"gnome":  "gsettings",
+}
+
+// serverBackends maps notification server names, as reported by
+// GetServerInformation, to backends.
+var serverBackends = map[string]string{
+	"mako":                   "mako",
+	"SwayNotificationCenter": "swaync",
+	"dunst":                  "dunst",
+	"gnome-shell":            "gnome",
+}
+
+// dconf takes GSettings writes over D-Bus. It has no D-Bus method for reads,
+// which go straight to the user database, so those still use gsettings.
+const (
+	dconfService = "ca.desrt.dconf"
+	dconfPath    = "/ca/desrt/dconf/Writer/user"
+	dconfWriter  = "ca.desrt.dconf.Writer"
+
+	showBannersKey = "/org/gnome/desktop/notifications/show-banners"
+)
+
+// Backend turns do-not-disturb on and off in a notification daemon.
+type Backend struct {
+	name string
+
+	// enabled reports whether do-not-disturb is on.
+	enabled func(ctx context.Context) (bool, error)
+	// set turns do-not-disturb on or off.
+	set func(ctx context.Context, on bool) error
+}
+
+// run runs a control program and returns its trimmed output. Tests replace
+// it.
+var run = func(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// writeDconfBool sets a boolean dconf key through the dconf service. Tests
+// replace it.
+var writeDconfBool = func(ctx context.Context, key string, value bool) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	var tag string
+	if err := conn.Object(dconfService, dconfPath).CallWithContext(ctx, dconfWriter+".Change", 0, dconfChangeset(key, value)).Store(&tag); err != nil {
+		return fmt.Errorf("set %s: %w", key, err)
+	}
+	return nil
+}
+
+// dconfChangeset serializes a change to one boolean key the way the dconf
+// service reads it: a GVariant of type a{smv}. The framing offsets are one
+// byte, so the key must be shorter than 240 bytes.
+func dconfChangeset(key string, value bool) []byte {
+	b := append([]byte(key), 0)
+	keyEnd := len(b)
+	// The maybe value is 8-byte aligned
+	for len(b)%8 != 0 {
+		b = append(b, 0)
+	}
+	var v byte
+	if value {
+		v = 1
+	}
+	// The variant is its value, a separator and its type; the trailing zero
+	// marks the maybe as set
+	b = append(b, v, 0, 'b', 0)
+	// The dict entry ends with the offset of the end of the key, the array
+	// with the offset of the end of its entry
+	b = append(b, byte(keyEnd))
+	return append(b, byte(len(b)))
+}
+
+// New returns the named backend. makoMode is the mako mode that means
+// do-not-disturb.
+func New(name, makoMode string) (*Backend, error) {
+	b := &Backend{name: name}
+	switch name {
+	case "mako":
+		b.enabled = func(ctx context.Context) (bool, error) {
+			out, err := run(ctx, "makoctl", "mode")
+			if err != nil {
+				return false, err
+			}
+			return slices.Contains(strings.Fields(out), makoMode), nil
+		}
+		b.set = func(ctx context.Context, on bool) error {
+			flag := "-r"
+			if on {
+				flag = "-a"
+			}
+			_, err := run(ctx, "makoctl", "mode", flag, makoMode)
+			return err
+		}
+	case "swaync":
+		b.enabled = func(ctx context.Context) (bool, error) {
+			return runBool(ctx, "swaync-client", "--get-dnd", "--skip-wait")
+		}
+		b.set = func(ctx context.Context, on bool) error {
+			flag := "--dnd-off"
+			if on {
+				flag = "--dnd-on"
+			}
+			_, err := run(ctx, "swaync-client", flag, "--skip-wait")
+			return err
+		}
+	case "dunst":
+		b.enabled = func(ctx context.Context) (bool, error) {
+			return runBool(ctx, "dunstctl", "is-paused")
+		}
+		b.set = func(ctx context.Context, on bool) error {
+			_, err := run(ctx, "dunstctl", "set-paused", fmt.Sprint(on))
+			return err
+		}
+	case "gnome":
+		// Banners are off while do-not-disturb is on
+		b.enabled = func(ctx context.Context) (bool, error) {
+			banners, err := runBool(ctx, "gsettings", "get", "org.gnome.desktop.notifications", "show-banners")
+			return !banners, err
+		}
+		b.set = func(ctx context.Context, on bool) error {
+			return writeDconfBool(ctx, showBannersKey, !on)
+		}
+	default:
+		return nil, fmt.Errorf("unknown do-not-disturb backend %q (use %s)", name, strings.Join(supportedBackends, ", "))
+	}
+	return b, nil
+}
+
+// runBool runs a control program that prints true or false.
+func runBool(ctx context.Context, name string, args ...string) (bool, error) {
+	out, err := run(ctx, name, args...)
+	if err != nil {
+		return false, err
+	}
+	switch out {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s: unexpected output %q", name, out)
+}
+
+// Name returns the backend name.
+func (b *Backend) Name() string {
+	return b.name
+}
+
+// Enable turns do-not-disturb on. It returns a function that restores the
+// previous state: if do-not-disturb was already on, restoring leaves it on.
+func (b *Backend) Enable(ctx context.Context) (restore func(context.Context) error, err error) {
+	on, err := b.enabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if on {
+		return func(context.Context) error { return nil }, nil
+	}
+	if err := b.set(ctx, true); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error { return b.set(ctx, false) }, nil
+}
+
+// Detect returns the backend for the running notification server, given
+// its name as reported by GetServerInformation. For servers it does not
+// recognize, it returns the first backend whose control program is
+// installed, leaving out GNOME since gsettings is installed on many other
+// desktops.
+func Detect(serverName string) (string, error) {
+	if name, ok := serverBackends[serverName]; ok {
+		return name, nil
+	}
+	for _, name := range supportedBackends {
+		if name == "gnome" {
+			continue
+		}
+		if path, err := exec.LookPath(controlPrograms[name]); err == nil && path != "" {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no supported notification daemon found (tried: %s)", strings.Join(supportedBackends, ", "))
+}
+
+// Supported returns the list of supported backends.
+func Supported() []string {
+	return supportedBackends
+}
@@ -0,0 +1,123 @@
+package dnd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeDaemon records the commands run and answers state queries.
+type fakeDaemon struct {
+	output string
+	calls  []string
+}
+
+func (f *fakeDaemon) install(t *testing.T) {
+	t.Helper()
+	orig := run
+	run = func(ctx context.Context, name string, args ...string) (string, error) {
+		call := strings.Join(append([]string{name}, args...), " ")
+		f.calls = append(f.calls, call)
+		return f.output, nil
+	}
+	origWrite := writeDconfBool
+	writeDconfBool = func(ctx context.Context, key string, value bool) error {
+		f.calls = append(f.calls, fmt.Sprintf("dconf write %s %t", key, value))
+		return nil
+	}
+	t.Cleanup(func() {
+		run = orig
+		writeDconfBool = origWrite
+	})
+}
+
+func TestEnable(t *testing.T) {
+	tests := []struct {
+		backend string
+		output  string // State query output
+		want    []string
+	}{
+		{"mako", "default", []string{"makoctl mode", "makoctl mode -a do-not-disturb", "makoctl mode -r do-not-disturb"}},
+		{"mako", "default\ndo-not-disturb", []string{"makoctl mode"}},
+		{"swaync", "false", []string{"swaync-client --get-dnd --skip-wait", "swaync-client --dnd-on --skip-wait", "swaync-client --dnd-off --skip-wait"}},
+		{"swaync", "true", []string{"swaync-client --get-dnd --skip-wait"}},
+		{"dunst", "false", []string{"dunstctl is-paused", "dunstctl set-paused true", "dunstctl set-paused false"}},
+		{"gnome", "true", []string{
+			"gsettings get org.gnome.desktop.notifications show-banners",
+			"dconf write /org/gnome/desktop/notifications/show-banners false",
+			"dconf write /org/gnome/desktop/notifications/show-banners true",
+		}},
+		{"gnome", "false", []string{"gsettings get org.gnome.desktop.notifications show-banners"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend+" "+tt.output, func(t *testing.T) {
+			f := &fakeDaemon{output: tt.output}
+			f.install(t)
+
+			b, err := New(tt.backend, "do-not-disturb")
+			if err != nil {
+				t.Fatal(err)
+			}
+			restore, err := b.Enable(context.Background())
+			if err != nil {
+				t.Fatalf("Enable: %v", err)
+			}
+			if err := restore(context.Background()); err != nil {
+				t.Fatalf("restore: %v", err)
+			}
+			if got := strings.Join(f.calls, "; "); got != strings.Join(tt.want, "; ") {
+				t.Errorf("commands = %s\nwant %s", got, strings.Join(tt.want, "; "))
+			}
+		})
+	}
+}
+
+func TestEnableUnexpectedOutput(t *testing.T) {
+	f := &fakeDaemon{output: "Couldn't connect"}
+	f.install(t)
+
+	b, err := New("dunst", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Enable(context.Background()); err == nil || !strings.Contains(err.Error(), `unexpected output "Couldn't connect"`) {
+		t.Errorf("Enable() error = %v", err)
+	}
+	if len(f.calls) != 1 {
+		t.Errorf("do-not-disturb should not be changed when its state is unknown: %v", f.calls)
+	}
+}
+
+func TestDconfChangeset(t *testing.T) {
+	// As serialized by GLib for {'<key>': @mv <false>} and <true>
+	key := hex.EncodeToString([]byte(showBannersKey))
+	tests := []struct {
+		value bool
+		want  string
+	}{
+		{false, key + "00" + "0000" + "00006200" + "2e35"},
+		{true, key + "00" + "0000" + "01006200" + "2e35"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(dconfChangeset(showBannersKey, tt.value)); got != tt.want {
+			t.Errorf("dconfChangeset(%t) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNewUnknown(t *testing.T) {
+	if _, err := New("xfce", ""); err == nil {
+		t.Error("New() should fail for unknown backends")
+	}
+}
+
+func TestDetectServer(t *testing.T) {
+	for server, want := range serverBackends {
+		got, err := Detect(server)
+		if err != nil || got != want {
+			t.Errorf("Detect(%q) = %q, %v, want %q", server, got, err, want)
+		}
+	}
+}
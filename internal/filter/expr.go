@@ -0,0 +1,376 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cpuguy83/calbar/internal/config"
+)
+
+// SyntaxError is an error in a filter expression.
+type SyntaxError struct {
+	Pos int // 1-based column of the offending token
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// is reports whether t is the given operator or keyword.
+func (t token) is(text string) bool {
+	return (t.kind == tokOp || t.kind == tokWord) && t.text == text
+}
+
+// operators lists the expression operators, longest first so "<=" wins over "<".
+var operators = []string{"&&", "||", "==", "!=", "=~", "<=", ">=", "^=", "$=", "!", "~", "<", ">"}
+
+// operatorChars are the characters operators are made of. They end a bare word.
+const operatorChars = "&|=!~<>^$"
+
+// lex splits a filter expression into tokens.
+func lex(src string) ([]token, error) {
+	runes := []rune(src)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(' || r == ')' || r == '[' || r == ']' || r == ',':
+			kind := map[rune]tokenKind{'(': tokLParen, ')': tokRParen, '[': tokLBracket, ']': tokRBracket, ',': tokComma}[r]
+			tokens = append(tokens, token{kind: kind, text: string(r), pos: pos})
+			i++
+
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, &SyntaxError{Pos: pos, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokString, text: b.String(), pos: pos})
+			i = j + 1
+
+		case strings.ContainsRune(operatorChars, r):
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(string(runes[i:min(i+2, len(runes))]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected %q", r)}
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: pos})
+			i += len(op)
+
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune(`()[],"'`+operatorChars, runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: string(runes[i:j]), pos: pos})
+			i = j
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(runes) + 1}), nil
+}
+
+// exprParser is a recursive descent parser for filter expressions:
+//
+//	expr       = and { ("||" | "or") and }
+//	and        = unary { ("&&" | "and") unary }
+//	unary      = ("!" | "not") unary | primary
+//	primary    = "(" expr ")" | comparison | field
+//	comparison = field op value | field "in" "[" value { "," value } "]"
+type exprParser struct {
+	tokens []token
+	i      int
+}
+
+// parseExpr compiles a filter expression such as
+// `title ~ "standup" && source == "Work"` into a node.
+func parseExpr(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, &SyntaxError{Pos: 1, Msg: "empty expression"}
+	}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok)}
+	}
+	return n, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokEOF {
+		p.i++
+	}
+	return tok
+}
+
+func (p *exprParser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []node{left}
+	for p.peek().is("||") || p.peek().is("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return anyNode(nodes), nil
+}
+
+func (p *exprParser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []node{left}
+	for p.peek().is("&&") || p.peek().is("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return allNode(nodes), nil
+}
+
+func (p *exprParser) parseUnary() (node, error) {
+	if p.peek().is("!") || p.peek().is("not") {
+		p.next()
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &SyntaxError{Pos: closing.pos, Msg: fmt.Sprintf("expected \")\" to close \"(\" at column %d, got %s", tok.pos, closing)}
+		}
+		return n, nil
+	case tokWord:
+		return p.parseComparison(tok)
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected field name, got %s", tok)}
+	}
+}
+
+// parseComparison parses the rest of a comparison on field.
+func (p *exprParser) parseComparison(field token) (node, error) {
+	kind, ok := fieldKinds[field.text]
+	if !ok {
+		return nil, &SyntaxError{Pos: field.pos, Msg: fmt.Sprintf("unknown field %q", field.text)}
+	}
+
+	op := p.peek()
+	isComparison := op.kind == tokOp && op.text != "&&" && op.text != "||" && op.text != "!"
+	if !isComparison && !op.is("in") {
+		if kind == kindBool {
+			// A bare boolean field matches when it is true
+			return p.compile(field, config.FilterRule{Field: field.text})
+		}
+		return nil, &SyntaxError{Pos: op.pos, Msg: fmt.Sprintf("expected operator after %s, got %s", field.text, op)}
+	}
+	p.next()
+
+	if op.is("in") {
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return p.compile(field, config.FilterRule{Field: field.text, In: values})
+	}
+
+	value, err := p.parseValue(op)
+	if err != nil {
+		return nil, err
+	}
+
+	r := config.FilterRule{Field: field.text}
+	switch op.text {
+	case "==":
+		r.Exact = value
+		return p.compile(field, r)
+	case "!=":
+		r.Exact = value
+		compiled, err := p.compile(field, r)
+		if err != nil {
+			return nil, err
+		}
+		return notNode{compiled}, nil
+	case "~":
+		r.Contains = value
+		r.CaseInsensitive = true
+		return p.compile(field, r)
+	case "=~":
+		r.Regex = value
+		return p.compile(field, r)
+	case "^=":
+		r.Prefix = value
+		return p.compile(field, r)
+	case "$=":
+		r.Suffix = value
+		return p.compile(field, r)
+	case "<":
+		r.Lt = value
+		return p.compile(field, r)
+	case "<=":
+		// There is no rule equivalent of <= or >=; compile as < or > and
+		// relax the comparison.
+		r.Lt = value
+		compiled, err := p.compileRule(field, r)
+		if err != nil {
+			return nil, err
+		}
+		compiled.matchType = MatchAtMost
+		return compiled, nil
+	case ">":
+		r.Gt = value
+		return p.compile(field, r)
+	case ">=":
+		r.Gt = value
+		compiled, err := p.compileRule(field, r)
+		if err != nil {
+			return nil, err
+		}
+		compiled.matchType = MatchAtLeast
+		return compiled, nil
+	default:
+		return nil, &SyntaxError{Pos: op.pos, Msg: fmt.Sprintf("unexpected %s", op)}
+	}
+}
+
+// compile compiles a single comparison, reporting errors at the field.
+func (p *exprParser) compile(field token, r config.FilterRule) (node, error) {
+	compiled, err := p.compileRule(field, r)
+	if err != nil {
+		return nil, err
+	}
+	return compiled, nil
+}
+
+// compileRule is like compile but returns the rule for further adjustment.
+func (p *exprParser) compileRule(field token, r config.FilterRule) (*rule, error) {
+	compiled, err := compileRule(r)
+	if err != nil {
+		return nil, &SyntaxError{Pos: field.pos, Msg: err.Error()}
+	}
+	return &compiled, nil
+}
+
+// parseValue parses a comparison value after op.
+func (p *exprParser) parseValue(op token) (string, error) {
+	tok := p.next()
+	if tok.kind != tokWord && tok.kind != tokString {
+		return "", &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected value after %s, got %s", op.text, tok)}
+	}
+	return tok.text, nil
+}
+
+// parseList parses a bracketed, comma-separated list of values.
+func (p *exprParser) parseList() ([]string, error) {
+	open := p.next()
+	if open.kind != tokLBracket {
+		return nil, &SyntaxError{Pos: open.pos, Msg: fmt.Sprintf("expected \"[\" after in, got %s", open)}
+	}
+
+	var values []string
+	for {
+		tok := p.next()
+		if tok.kind != tokWord && tok.kind != tokString {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected value in list, got %s", tok)}
+		}
+		values = append(values, tok.text)
+
+		switch sep := p.next(); sep.kind {
+		case tokComma:
+		case tokRBracket:
+			return values, nil
+		default:
+			return nil, &SyntaxError{Pos: sep.pos, Msg: fmt.Sprintf("expected \",\" or \"]\" to close \"[\" at column %d, got %s", open.pos, sep)}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+)
+
+func TestParseExpr(t *testing.T) {
+	start := time.Date(2026, 10, 14, 9, 30, 0, 0, time.Local)
+	standup := calendar.Event{Summary: "Daily Standup", Source: "Work", Start: start, End: start.Add(15 * time.Minute)}
+	personal := calendar.Event{Summary: "Standup practice", Source: "Home", Start: start, End: start.Add(time.Hour)}
+	oneOnOne := calendar.Event{Summary: "1:1", Organizer: "boss@company.com", Source: "Home", Start: start, End: start.Add(30 * time.Minute)}
+
+	tests := []struct {
+		expr string
+		want map[string]bool // event summary -> match
+	}{
+		{
+			`(title ~ "standup" && source == "Work") || organizer == boss@company.com`,
+			map[string]bool{"Daily Standup": true, "Standup practice": false, "1:1": true},
+		},
+		{
+			`title ~ standup and not source == Work`,
+			map[string]bool{"Daily Standup": false, "Standup practice": true, "1:1": false},
+		},
+		{
+			`source in ["Home", 'Office'] && duration >= 30m`,
+			map[string]bool{"Daily Standup": false, "Standup practice": true, "1:1": true},
+		},
+		{
+			`duration <= 30m && start_time < 10:00 && weekday == wed`,
+			map[string]bool{"Daily Standup": true, "Standup practice": false, "1:1": true},
+		},
+		{
+			`duration < 1d && duration > 0.25h`,
+			map[string]bool{"Daily Standup": false, "Standup practice": true, "1:1": true},
+		},
+		{
+			`!all_day && title != "1:1" && title =~ "^Daily"`,
+			map[string]bool{"Daily Standup": true, "Standup practice": false, "1:1": false},
+		},
+		{
+			`title ^= Stand or title $= "1"`,
+			map[string]bool{"Daily Standup": false, "Standup practice": true, "1:1": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			n, err := parseExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("parseExpr error: %v", err)
+			}
+			for _, e := range []calendar.Event{standup, personal, oneOnOne} {
+				if got := n.matches(e); got != tt.want[e.Summary] {
+					t.Errorf("%q: matches() = %v, want %v", e.Summary, got, tt.want[e.Summary])
+				}
+			}
+		})
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+	}{
+		{``, 1},
+		{`title ~ "standup`, 9},
+		{`colour == red`, 1},
+		{`title ~ standup &&`, 19},
+		{`(title ~ standup`, 17},
+		{`title standup`, 7},
+		{`duration > soon`, 1},
+		{`source in [Work Home]`, 17},
+		{`title ~ a || | b`, 14},
+		{`title ~ a) `, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := parseExpr(tt.expr)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected SyntaxError, got %v", err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("error %q at column %d, want %d", syntaxErr, syntaxErr.Pos, tt.pos)
+			}
+		})
+	}
+}
@@ -4,10 +4,14 @@ package filter
 import (
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cpuguy83/calbar/internal/calendar"
 	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/links"
 )
 
 // MatchType specifies how a filter rule matches.
@@ -19,21 +23,129 @@ const (
 	MatchPrefix                    // Starts with
 	MatchSuffix                    // Ends with
 	MatchRegex                     // Regular expression
+	MatchIn                        // Equal to any of a list of values
+	MatchLess                      // Less than
+	MatchGreater                   // Greater than
+	MatchBetween                   // Within an inclusive range
+	MatchAtMost                    // Less than or equal
+	MatchAtLeast                   // Greater than or equal
+	MatchIs                        // Boolean field equals a value
 )
 
+// fieldKind is the type of value a filter field holds.
+type fieldKind int
+
+const (
+	kindText      fieldKind = iota // Free text, matched as strings
+	kindTimeOfDay                  // Local start time, as minutes since midnight
+	kindWeekday                    // Local start weekday, Monday = 1 to Sunday = 7
+	kindDuration                   // Event duration
+	kindBool                       // True or false
+)
+
+// fieldKinds lists the fields rules can match on.
+var fieldKinds = map[string]fieldKind{
+	"title":           kindText,
+	"summary":         kindText,
+	"organizer":       kindText,
+	"source":          kindText,
+	"calendar":        kindText,
+	"description":     kindText,
+	"location":        kindText,
+	"attendee":        kindText,
+	"status":          kindText,
+	"meeting_service": kindText,
+	"tag":             kindText,
+	"start_time":      kindTimeOfDay,
+	"weekday":         kindWeekday,
+	"duration":        kindDuration,
+	"all_day":         kindBool,
+	"has_meeting":     kindBool,
+}
+
+// weekdays maps weekday names and abbreviations to ISO weekday numbers.
+var weekdays = map[string]int64{
+	"mon": 1, "monday": 1,
+	"tue": 2, "tues": 2, "tuesday": 2,
+	"wed": 3, "wednesday": 3,
+	"thu": 4, "thur": 4, "thurs": 4, "thursday": 4,
+	"fri": 5, "friday": 5,
+	"sat": 6, "saturday": 6,
+	"sun": 7, "sunday": 7,
+}
+
 // Filter applies include/exclude rules to events.
 type Filter struct {
-	mode         string // "or" or "and"
-	includeRules []rule // Rules that include events
-	excludeRules []rule // Rules that exclude events
+	mode         string         // "or" or "and"
+	includeRules []compiledRule // Rules that include events
+	excludeRules []compiledRule // Rules that exclude events
+}
+
+// compiledRule is a top-level rule with its position and description, so
+// decisions can be explained.
+type compiledRule struct {
+	node
+	index   int    // Position in the config rules
+	desc    string // Human-readable form of the config rule
+	exclude bool
+}
+
+// Decision describes how a filter decided an event.
+type Decision struct {
+	Included bool
+	Rule     int    // Index of the deciding rule in the config rules, or -1 if no single rule decided
+	RuleDesc string // Human-readable form of the deciding rule, if any
+	Reason   string // Explanation, e.g. "excluded by rule 2: title contains \"OOO\""
+}
+
+// node is a compiled filter expression: a single rule or a group of nodes.
+type node interface {
+	matches(event calendar.Event) bool
+}
+
+// allNode matches if every child matches.
+type allNode []node
+
+func (n allNode) matches(event calendar.Event) bool {
+	for _, child := range n {
+		if !child.matches(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// anyNode matches if any child matches.
+type anyNode []node
+
+func (n anyNode) matches(event calendar.Event) bool {
+	for _, child := range n {
+		if child.matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// notNode matches if its child does not.
+type notNode struct {
+	node
+}
+
+func (n notNode) matches(event calendar.Event) bool {
+	return !n.node.matches(event)
 }
 
 type rule struct {
 	field           string
+	kind            fieldKind
 	matchType       MatchType
 	pattern         string         // For non-regex matches
+	patterns        []string       // For in matches on text fields
 	regex           *regexp.Regexp // For regex matches
 	caseInsensitive bool
+	operands        []int64 // Parsed values for typed fields
+	want            bool    // Expected value for boolean fields
 }
 
 // New creates a new filter from configuration.
@@ -47,33 +159,215 @@ func New(cfg config.FilterConfig) (*Filter, error) {
 	}
 
 	for i, r := range cfg.Rules {
-		compiled, err := compileRule(r)
+		compiled, err := compileNode(r)
 		if err != nil {
 			return nil, fmt.Errorf("rule %d: %w", i, err)
 		}
+		cr := compiledRule{node: compiled, index: i, desc: describeRule(r), exclude: r.Exclude}
 		if r.Exclude {
-			f.excludeRules = append(f.excludeRules, compiled)
+			f.excludeRules = append(f.excludeRules, cr)
 		} else {
-			f.includeRules = append(f.includeRules, compiled)
+			f.includeRules = append(f.includeRules, cr)
 		}
 	}
 
 	return f, nil
 }
 
+// Matcher matches events against a single rule, group or expression, for
+// uses other than filtering such as transforms.
+type Matcher struct {
+	node node
+}
+
+// Compile compiles a rule into a Matcher. Exclude has no meaning outside of a
+// filter and is an error.
+func Compile(r config.FilterRule) (*Matcher, error) {
+	if r.Exclude {
+		return nil, fmt.Errorf("exclude is only allowed in filters (use not)")
+	}
+	n, err := compileNode(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{node: n}, nil
+}
+
+// Matches reports whether the event matches.
+func (m *Matcher) Matches(event calendar.Event) bool {
+	return m.node.matches(event)
+}
+
+// describeRule returns a short, human-readable form of a config rule, e.g.
+// `title contains "standup"` or `any(source exact "Work", all_day)`.
+func describeRule(r config.FilterRule) string {
+	describeAll := func(rules []config.FilterRule) string {
+		parts := make([]string, 0, len(rules))
+		for _, child := range rules {
+			parts = append(parts, describeRule(child))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	switch {
+	case r.Expr != "":
+		return "expr " + strconv.Quote(r.Expr)
+	case len(r.All) > 0:
+		return "all(" + describeAll(r.All) + ")"
+	case len(r.Any) > 0:
+		return "any(" + describeAll(r.Any) + ")"
+	case r.Not != nil:
+		return "not(" + describeRule(*r.Not) + ")"
+	}
+
+	var op string
+	switch {
+	case r.Contains != "":
+		op = "contains " + strconv.Quote(r.Contains)
+	case r.Exact != "":
+		op = "exact " + strconv.Quote(r.Exact)
+	case r.Prefix != "":
+		op = "prefix " + strconv.Quote(r.Prefix)
+	case r.Suffix != "":
+		op = "suffix " + strconv.Quote(r.Suffix)
+	case r.Regex != "":
+		op = "regex " + strconv.Quote(r.Regex)
+	case len(r.In) > 0:
+		op = "in [" + strings.Join(r.In, ", ") + "]"
+	case r.Lt != "":
+		op = "lt " + r.Lt
+	case r.Gt != "":
+		op = "gt " + r.Gt
+	case len(r.Between) > 0:
+		op = "between [" + strings.Join(r.Between, ", ") + "]"
+	case r.Match != "":
+		op = "match " + strconv.Quote(r.Match)
+	}
+
+	desc := r.Field
+	if op != "" {
+		desc += " " + op
+	}
+	if r.CaseInsensitive {
+		desc += " (case-insensitive)"
+	}
+	return desc
+}
+
+// compileNode compiles a config FilterRule, which may be a group or an
+// expression, into a node.
+func compileNode(r config.FilterRule) (node, error) {
+	var kinds []string
+	if r.Field != "" {
+		kinds = append(kinds, "field")
+	}
+	if len(r.All) > 0 {
+		kinds = append(kinds, "all")
+	}
+	if len(r.Any) > 0 {
+		kinds = append(kinds, "any")
+	}
+	if r.Not != nil {
+		kinds = append(kinds, "not")
+	}
+	if r.Expr != "" {
+		kinds = append(kinds, "expr")
+	}
+	switch len(kinds) {
+	case 0:
+		return nil, fmt.Errorf("rule needs one of field, all, any, not, or expr")
+	case 1:
+	default:
+		return nil, fmt.Errorf("rule has more than one of %s", strings.Join(kinds, ", "))
+	}
+
+	switch kinds[0] {
+	case "all":
+		return compileGroup("all", r.All, func(nodes []node) node { return allNode(nodes) })
+	case "any":
+		return compileGroup("any", r.Any, func(nodes []node) node { return anyNode(nodes) })
+	case "not":
+		child, err := compileNestedNode(*r.Not)
+		if err != nil {
+			return nil, fmt.Errorf("not: %w", err)
+		}
+		return notNode{child}, nil
+	case "expr":
+		n, err := parseExpr(r.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("expr: %w", err)
+		}
+		return n, nil
+	default:
+		compiled, err := compileRule(r)
+		if err != nil {
+			return nil, err
+		}
+		return &compiled, nil
+	}
+}
+
+// compileGroup compiles the children of an all or any group.
+func compileGroup(name string, rules []config.FilterRule, group func([]node) node) (node, error) {
+	nodes := make([]node, 0, len(rules))
+	for i, r := range rules {
+		child, err := compileNestedNode(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+		}
+		nodes = append(nodes, child)
+	}
+	return group(nodes), nil
+}
+
+// compileNestedNode compiles a rule inside a group, where exclude has no
+// meaning.
+func compileNestedNode(r config.FilterRule) (node, error) {
+	if r.Exclude {
+		return nil, fmt.Errorf("exclude is only allowed on top-level rules (use not)")
+	}
+	return compileNode(r)
+}
+
 // compileRule converts a config FilterRule to an internal rule.
 func compileRule(r config.FilterRule) (rule, error) {
+	kind, ok := fieldKinds[r.Field]
+	if !ok {
+		return rule{}, fmt.Errorf("unknown field %q", r.Field)
+	}
+
 	compiled := rule{
 		field:           r.Field,
+		kind:            kind,
 		caseInsensitive: r.CaseInsensitive,
 	}
 
+	switch kind {
+	case kindText:
+		return compileTextRule(r, compiled)
+	case kindBool:
+		return compileBoolRule(r, compiled)
+	default:
+		return compileOrderedRule(r, compiled)
+	}
+}
+
+// compileTextRule compiles a rule on a free-text field.
+func compileTextRule(r config.FilterRule, compiled rule) (rule, error) {
+	if r.Lt != "" || r.Gt != "" || len(r.Between) > 0 {
+		return compiled, fmt.Errorf("%s is a text field (use contains, exact, prefix, suffix, regex, or in)", r.Field)
+	}
+	// Statuses are matched regardless of case
+	if r.Field == "status" {
+		compiled.caseInsensitive = true
+	}
+
 	// Determine match type and pattern from the new typed fields
 	switch {
 	case r.Regex != "":
 		compiled.matchType = MatchRegex
 		pattern := r.Regex
-		if r.CaseInsensitive {
+		if compiled.caseInsensitive {
 			pattern = "(?i)" + pattern
 		}
 		re, err := regexp.Compile(pattern)
@@ -85,31 +379,40 @@ func compileRule(r config.FilterRule) (rule, error) {
 	case r.Exact != "":
 		compiled.matchType = MatchExact
 		compiled.pattern = r.Exact
-		if r.CaseInsensitive {
+		if compiled.caseInsensitive {
 			compiled.pattern = strings.ToLower(compiled.pattern)
 		}
 
 	case r.Prefix != "":
 		compiled.matchType = MatchPrefix
 		compiled.pattern = r.Prefix
-		if r.CaseInsensitive {
+		if compiled.caseInsensitive {
 			compiled.pattern = strings.ToLower(compiled.pattern)
 		}
 
 	case r.Suffix != "":
 		compiled.matchType = MatchSuffix
 		compiled.pattern = r.Suffix
-		if r.CaseInsensitive {
+		if compiled.caseInsensitive {
 			compiled.pattern = strings.ToLower(compiled.pattern)
 		}
 
 	case r.Contains != "":
 		compiled.matchType = MatchContains
 		compiled.pattern = r.Contains
-		if r.CaseInsensitive {
+		if compiled.caseInsensitive {
 			compiled.pattern = strings.ToLower(compiled.pattern)
 		}
 
+	case len(r.In) > 0:
+		compiled.matchType = MatchIn
+		for _, v := range r.In {
+			if compiled.caseInsensitive {
+				v = strings.ToLower(v)
+			}
+			compiled.patterns = append(compiled.patterns, v)
+		}
+
 	case r.Match != "":
 		// Backward compatibility: handle legacy "match" field
 		if strings.HasPrefix(r.Match, "regex:") {
@@ -132,12 +435,138 @@ func compileRule(r config.FilterRule) (rule, error) {
 		}
 
 	default:
-		return compiled, fmt.Errorf("no match pattern specified (use contains, exact, prefix, suffix, or regex)")
+		return compiled, fmt.Errorf("no match pattern specified (use contains, exact, prefix, suffix, regex, or in)")
+	}
+
+	return compiled, nil
+}
+
+// compileBoolRule compiles a rule on a boolean field. Without an operator the
+// rule matches when the field is true.
+func compileBoolRule(r config.FilterRule, compiled rule) (rule, error) {
+	compiled.matchType = MatchIs
+	compiled.want = true
+
+	switch ops := ruleOperators(r); {
+	case len(ops) == 0:
+	case len(ops) > 1:
+		return compiled, fmt.Errorf("multiple match operators (%s)", strings.Join(ops, ", "))
+	case ops[0] != "exact":
+		return compiled, fmt.Errorf("%s is a boolean field (use exact: true or exact: false)", r.Field)
+	default:
+		want, err := strconv.ParseBool(r.Exact)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid %s value %q: want true or false", r.Field, r.Exact)
+		}
+		compiled.want = want
+	}
+	return compiled, nil
+}
+
+// compileOrderedRule compiles a rule on a time of day, weekday or duration
+// field.
+func compileOrderedRule(r config.FilterRule, compiled rule) (rule, error) {
+	ops := ruleOperators(r)
+	switch {
+	case len(ops) == 0:
+		return compiled, fmt.Errorf("no match operator specified for %s (use exact, in, lt, gt, or between)", r.Field)
+	case len(ops) > 1:
+		return compiled, fmt.Errorf("multiple match operators (%s)", strings.Join(ops, ", "))
+	}
+
+	var values []string
+	switch ops[0] {
+	case "exact":
+		compiled.matchType = MatchExact
+		values = []string{r.Exact}
+	case "in":
+		compiled.matchType = MatchIn
+		values = r.In
+	case "lt":
+		compiled.matchType = MatchLess
+		values = []string{r.Lt}
+	case "gt":
+		compiled.matchType = MatchGreater
+		values = []string{r.Gt}
+	case "between":
+		compiled.matchType = MatchBetween
+		if len(r.Between) != 2 {
+			return compiled, fmt.Errorf("between needs two values, got %d", len(r.Between))
+		}
+		values = r.Between
+	default:
+		return compiled, fmt.Errorf("%s does not support %s (use exact, in, lt, gt, or between)", r.Field, ops[0])
+	}
+
+	for _, v := range values {
+		operand, err := parseOperand(compiled.kind, v)
+		if err != nil {
+			return compiled, fmt.Errorf("%s: %w", r.Field, err)
+		}
+		compiled.operands = append(compiled.operands, operand)
+	}
+
+	// Times of day and weekdays wrap around, so a reversed range such as
+	// 22:00 to 06:00 is allowed; durations do not.
+	if compiled.matchType == MatchBetween && compiled.kind == kindDuration && compiled.operands[0] > compiled.operands[1] {
+		return compiled, fmt.Errorf("%s: between range %s to %s is reversed", r.Field, r.Between[0], r.Between[1])
 	}
 
 	return compiled, nil
 }
 
+// ruleOperators returns the names of the match operators set on a rule.
+func ruleOperators(r config.FilterRule) []string {
+	var ops []string
+	for _, op := range []struct {
+		name string
+		set  bool
+	}{
+		{"contains", r.Contains != ""},
+		{"exact", r.Exact != ""},
+		{"prefix", r.Prefix != ""},
+		{"suffix", r.Suffix != ""},
+		{"regex", r.Regex != ""},
+		{"match", r.Match != ""},
+		{"in", len(r.In) > 0},
+		{"lt", r.Lt != ""},
+		{"gt", r.Gt != ""},
+		{"between", len(r.Between) > 0},
+	} {
+		if op.set {
+			ops = append(ops, op.name)
+		}
+	}
+	return ops
+}
+
+// parseOperand parses a rule value for a typed field.
+func parseOperand(kind fieldKind, s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	switch kind {
+	case kindTimeOfDay:
+		t, err := time.Parse("15:04", s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time of day %q (use HH:MM)", s)
+		}
+		return int64(t.Hour()*60 + t.Minute()), nil
+	case kindWeekday:
+		day, ok := weekdays[strings.ToLower(s)]
+		if !ok {
+			return 0, fmt.Errorf("invalid weekday %q", s)
+		}
+		return day, nil
+	case kindDuration:
+		d, err := config.ParseDuration(s)
+		if err != nil || s == "" {
+			return 0, fmt.Errorf("invalid duration %q (use e.g. 30m, 4h or 1d)", s)
+		}
+		return int64(d), nil
+	default:
+		return 0, fmt.Errorf("unsupported field type")
+	}
+}
+
 // Apply filters events, returning only those that pass the filter rules.
 // - Exclude rules are applied first: any event matching an exclude rule is removed
 // - Include rules are applied second: if any include rules exist, only matching events are kept
@@ -150,56 +579,107 @@ func (f *Filter) Apply(events []calendar.Event) []calendar.Event {
 
 	var filtered []calendar.Event
 	for _, event := range events {
-		// Check exclude rules first - if any match, skip this event
-		if f.matchesExclude(event) {
-			continue
-		}
-
-		// If no include rules, keep the event (only excludes matter)
-		// If include rules exist, event must match them
-		if len(f.includeRules) == 0 || f.matchesInclude(event) {
+		if included, _ := f.decide(event); included {
 			filtered = append(filtered, event)
 		}
 	}
 	return filtered
 }
 
-// matchesExclude checks if an event matches any exclude rule.
-func (f *Filter) matchesExclude(event calendar.Event) bool {
-	// Any exclude rule matching means exclude
-	for _, r := range f.excludeRules {
-		if r.matches(event) {
-			return true
-		}
+// Explain reports whether Apply keeps an event and which rule decided it.
+func (f *Filter) Explain(event calendar.Event) Decision {
+	included, r := f.decide(event)
+	d := Decision{Included: included, Rule: -1}
+	if r != nil {
+		d.Rule = r.index
+		d.RuleDesc = r.desc
 	}
-	return false
+
+	switch {
+	case len(f.includeRules) == 0 && len(f.excludeRules) == 0:
+		d.Reason = "included: no rules"
+	case r != nil && r.exclude:
+		d.Reason = fmt.Sprintf("excluded by rule %d: %s", r.index, r.desc)
+	case len(f.includeRules) == 0:
+		d.Reason = "included: no exclude rule matched"
+	case r != nil && included:
+		d.Reason = fmt.Sprintf("included by rule %d: %s", r.index, r.desc)
+	case r != nil:
+		d.Reason = fmt.Sprintf("not included: rule %d did not match (mode: and): %s", r.index, r.desc)
+	case included:
+		d.Reason = "included: matched every include rule"
+	default:
+		d.Reason = "not included: matched no include rule"
+	}
+	return d
 }
 
-// matchesInclude checks if an event matches the include filter rules.
-func (f *Filter) matchesInclude(event calendar.Event) bool {
+// decide reports whether an event passes the filter, and the rule that
+// decided it: the first matching exclude rule, the first matching include
+// rule in "or" mode, or the first failing include rule in "and" mode.
+func (f *Filter) decide(event calendar.Event) (bool, *compiledRule) {
+	// Check exclude rules first - if any match, skip this event
+	if r := f.matchesExclude(event); r != nil {
+		return false, r
+	}
+
+	// If no include rules, keep the event (only excludes matter)
+	if len(f.includeRules) == 0 {
+		return true, nil
+	}
+
 	if f.mode == "and" {
 		// All rules must match
-		for _, r := range f.includeRules {
-			if !r.matches(event) {
-				return false
+		for i := range f.includeRules {
+			if !f.includeRules[i].matches(event) {
+				return false, &f.includeRules[i]
 			}
 		}
-		return true
+		return true, nil
 	}
 
 	// OR mode: any rule must match
-	for _, r := range f.includeRules {
-		if r.matches(event) {
-			return true
+	for i := range f.includeRules {
+		if f.includeRules[i].matches(event) {
+			return true, &f.includeRules[i]
 		}
 	}
-	return false
+	return false, nil
+}
+
+// matchesExclude returns the first exclude rule the event matches, if any.
+func (f *Filter) matchesExclude(event calendar.Event) *compiledRule {
+	for i := range f.excludeRules {
+		if f.excludeRules[i].matches(event) {
+			return &f.excludeRules[i]
+		}
+	}
+	return nil
 }
 
 // matches checks if an event matches a single rule.
 func (r *rule) matches(event calendar.Event) bool {
-	value := r.getFieldValue(event)
+	switch r.kind {
+	case kindBool:
+		return r.getBoolValue(event) == r.want
+	case kindText:
+		for _, value := range r.getFieldValues(event) {
+			if r.matchesText(value) {
+				return true
+			}
+		}
+		return false
+	default:
+		value, ok := r.getOrderedValue(event)
+		if !ok {
+			return false
+		}
+		return r.matchesOrdered(value)
+	}
+}
 
+// matchesText checks a single text value against the rule.
+func (r *rule) matchesText(value string) bool {
 	// Apply case insensitivity for non-regex matches
 	if r.caseInsensitive && r.matchType != MatchRegex {
 		value = strings.ToLower(value)
@@ -214,6 +694,8 @@ func (r *rule) matches(event calendar.Event) bool {
 		return strings.HasPrefix(value, r.pattern)
 	case MatchSuffix:
 		return strings.HasSuffix(value, r.pattern)
+	case MatchIn:
+		return slices.Contains(r.patterns, value)
 	case MatchContains:
 		fallthrough
 	default:
@@ -221,20 +703,121 @@ func (r *rule) matches(event calendar.Event) bool {
 	}
 }
 
-// getFieldValue extracts the field value from an event.
-func (r *rule) getFieldValue(event calendar.Event) string {
+// matchesOrdered checks a time of day, weekday or duration against the rule.
+func (r *rule) matchesOrdered(value int64) bool {
+	switch r.matchType {
+	case MatchExact:
+		return value == r.operands[0]
+	case MatchIn:
+		return slices.Contains(r.operands, value)
+	case MatchLess:
+		return value < r.operands[0]
+	case MatchGreater:
+		return value > r.operands[0]
+	case MatchAtMost:
+		return value <= r.operands[0]
+	case MatchAtLeast:
+		return value >= r.operands[0]
+	case MatchBetween:
+		low, high := r.operands[0], r.operands[1]
+		if low <= high {
+			return value >= low && value <= high
+		}
+		// Wrapped range, e.g. 22:00 to 06:00 or Fri to Mon
+		return value >= low || value <= high
+	default:
+		return false
+	}
+}
+
+// getFieldValues extracts the text values of a field from an event. Fields
+// with several values, such as attendee, match if any value does.
+func (r *rule) getFieldValues(event calendar.Event) []string {
 	switch r.field {
 	case "title", "summary":
-		return event.Summary
+		return []string{event.Summary}
 	case "organizer":
-		return event.Organizer
+		return []string{event.Organizer}
 	case "source", "calendar":
-		return event.Source
+		return []string{event.Source}
 	case "description":
-		return event.Description
+		return []string{event.Description}
 	case "location":
-		return event.Location
+		return []string{event.Location}
+	case "attendee":
+		values := make([]string, 0, 2*len(event.Attendees))
+		for _, a := range event.Attendees {
+			if a.Name != "" {
+				values = append(values, a.Name)
+			}
+			values = append(values, a.Email)
+		}
+		return values
+	case "status":
+		return []string{string(event.Status)}
+	case "meeting_service":
+		_, service := meetingInfo(event)
+		return []string{service}
+	case "tag":
+		return event.Tags
+	default:
+		return nil
+	}
+}
+
+// getOrderedValue extracts a time of day, weekday or duration from an event.
+// All-day events have no time of day.
+func (r *rule) getOrderedValue(event calendar.Event) (int64, bool) {
+	start := event.Start.Local()
+	switch r.kind {
+	case kindTimeOfDay:
+		if event.AllDay {
+			return 0, false
+		}
+		return int64(start.Hour()*60 + start.Minute()), true
+	case kindWeekday:
+		day := int64(start.Weekday())
+		if day == 0 {
+			day = 7 // Sunday
+		}
+		return day, true
+	case kindDuration:
+		return int64(event.Duration()), true
 	default:
-		return ""
+		return 0, false
+	}
+}
+
+// getBoolValue extracts a boolean field from an event.
+func (r *rule) getBoolValue(event calendar.Event) bool {
+	switch r.field {
+	case "all_day":
+		return event.AllDay
+	case "has_meeting":
+		url, _ := meetingInfo(event)
+		return url != ""
+	default:
+		return false
+	}
+}
+
+// meetingInfo returns the event's online meeting URL and service. Links found
+// in the location or description only count if they belong to a known
+// meeting service.
+func meetingInfo(event calendar.Event) (url, service string) {
+	url = event.Meeting.URL
+	if url == "" {
+		url = links.DetectFromEvent(event.Location, event.Description, event.URL)
+		if url != "" && links.Service(url) == "Meeting" {
+			url = ""
+		}
+	}
+	if url == "" {
+		return "", event.Meeting.Service
+	}
+	service = event.Meeting.Service
+	if service == "" {
+		service = links.Service(url)
 	}
+	return url, service
 }
@@ -0,0 +1,245 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+)
+
+func TestRuleMatches(t *testing.T) {
+	// Wednesday
+	start := time.Date(2026, 10, 14, 8, 30, 0, 0, time.Local)
+	event := calendar.Event{
+		Summary:  "Design review",
+		Start:    start,
+		End:      start.Add(5 * time.Hour),
+		Status:   calendar.StatusCancelled,
+		Location: "https://acme.zoom.us/j/123456789",
+		Tags:     []string{"work", "external"},
+		Attendees: []calendar.Attendee{
+			{Name: "Jane Doe", Email: "jane@example.com"},
+		},
+	}
+	allDay := calendar.Event{
+		Summary: "Offsite",
+		Start:   time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local),
+		End:     time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local),
+		AllDay:  true,
+	}
+
+	tests := []struct {
+		name  string
+		rule  config.FilterRule
+		event calendar.Event
+		want  bool
+	}{
+		{"start before", config.FilterRule{Field: "start_time", Lt: "09:00"}, event, true},
+		{"start after", config.FilterRule{Field: "start_time", Gt: "18:00"}, event, false},
+		{"start between", config.FilterRule{Field: "start_time", Between: []string{"09:00", "18:00"}}, event, false},
+		{"start wrapped range", config.FilterRule{Field: "start_time", Between: []string{"22:00", "08:30"}}, event, true},
+		{"all-day has no start time", config.FilterRule{Field: "start_time", Lt: "09:00"}, allDay, false},
+		{"weekday in", config.FilterRule{Field: "weekday", In: []string{"Mon", "wednesday"}}, event, true},
+		{"weekday between", config.FilterRule{Field: "weekday", Between: []string{"sat", "sun"}}, allDay, true},
+		{"weekend wrap", config.FilterRule{Field: "weekday", Between: []string{"fri", "mon"}}, event, false},
+		{"duration over", config.FilterRule{Field: "duration", Gt: "4h"}, event, true},
+		{"duration under", config.FilterRule{Field: "duration", Lt: "4h"}, event, false},
+		{"duration under a day", config.FilterRule{Field: "duration", Lt: "1d"}, event, true},
+		{"duration in weeks", config.FilterRule{Field: "duration", Between: []string{"1d", "1w"}}, allDay, true},
+		{"all day", config.FilterRule{Field: "all_day"}, allDay, true},
+		{"not all day", config.FilterRule{Field: "all_day", Exact: "false"}, event, true},
+		{"has meeting", config.FilterRule{Field: "has_meeting"}, event, true},
+		{"no meeting", config.FilterRule{Field: "has_meeting", Exact: "false"}, allDay, true},
+		{"meeting service", config.FilterRule{Field: "meeting_service", Exact: "Zoom"}, event, true},
+		{"status", config.FilterRule{Field: "status", Exact: "cancelled"}, event, true},
+		{"status in", config.FilterRule{Field: "status", In: []string{"confirmed", "tentative"}}, event, false},
+		{"attendee name", config.FilterRule{Field: "attendee", Contains: "jane", CaseInsensitive: true}, event, true},
+		{"attendee email", config.FilterRule{Field: "attendee", Exact: "jane@example.com"}, event, true},
+		{"tag", config.FilterRule{Field: "tag", Exact: "external"}, event, true},
+		{"no tags", config.FilterRule{Field: "tag", Contains: "work"}, allDay, false},
+		{"title in", config.FilterRule{Field: "title", In: []string{"Offsite", "Standup"}}, allDay, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := compileRule(tt.rule)
+			if err != nil {
+				t.Fatalf("compileRule error: %v", err)
+			}
+			if got := r.matches(tt.event); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileRuleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule config.FilterRule
+	}{
+		{"unknown field", config.FilterRule{Field: "colour", Contains: "red"}},
+		{"text with lt", config.FilterRule{Field: "title", Lt: "b"}},
+		{"no operator", config.FilterRule{Field: "duration"}},
+		{"two operators", config.FilterRule{Field: "duration", Lt: "1h", Gt: "2h"}},
+		{"contains on duration", config.FilterRule{Field: "duration", Contains: "1h"}},
+		{"bad time", config.FilterRule{Field: "start_time", Lt: "9am"}},
+		{"bad weekday", config.FilterRule{Field: "weekday", Exact: "someday"}},
+		{"bad duration", config.FilterRule{Field: "duration", Gt: "long"}},
+		{"between one value", config.FilterRule{Field: "start_time", Between: []string{"09:00"}}},
+		{"reversed duration", config.FilterRule{Field: "duration", Between: []string{"4h", "1h"}}},
+		{"bad bool", config.FilterRule{Field: "all_day", Exact: "maybe"}},
+		{"bool with lt", config.FilterRule{Field: "has_meeting", Lt: "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compileRule(tt.rule); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestApply_ExcludeTypedRules(t *testing.T) {
+	start := time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)
+	day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	events := []calendar.Event{
+		{UID: "standup", Start: start, End: start.Add(15 * time.Minute), Meeting: calendar.MeetingDetails{URL: "https://meet.google.com/abc-defg-hij"}},
+		{UID: "focus", Start: start, End: start.Add(2 * time.Hour)},
+		{UID: "holiday", Start: day, End: day.AddDate(0, 0, 1), AllDay: true},
+	}
+
+	f, err := New(config.FilterConfig{Rules: []config.FilterRule{
+		{Field: "all_day", Exclude: true},
+		{Field: "has_meeting", Exact: "false", Exclude: true},
+	}})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	got := f.Apply(events)
+	if len(got) != 1 || got[0].UID != "standup" {
+		t.Fatalf("Apply() = %+v, want only standup", got)
+	}
+}
+
+func TestNew_NestedGroups(t *testing.T) {
+	events := []calendar.Event{
+		{UID: "work-standup", Summary: "Standup", Source: "Work"},
+		{UID: "home-standup", Summary: "Standup", Source: "Home"},
+		{UID: "boss", Summary: "1:1", Organizer: "boss@company.com", Source: "Work"},
+		{UID: "boss-lunch", Summary: "Lunch", Organizer: "boss@company.com", Source: "Work"},
+		{UID: "boss-home", Summary: "Dinner", Organizer: "boss@company.com", Source: "Home"},
+		{UID: "other", Summary: "Review", Source: "Work"},
+	}
+
+	// (title contains standup AND source is Work) OR (organizer is boss AND NOT title is Lunch),
+	// excluding anything from Home
+	f, err := New(config.FilterConfig{Rules: []config.FilterRule{
+		{Any: []config.FilterRule{
+			{All: []config.FilterRule{
+				{Field: "title", Contains: "standup", CaseInsensitive: true},
+				{Field: "source", Exact: "Work"},
+			}},
+			{All: []config.FilterRule{
+				{Field: "organizer", Exact: "boss@company.com"},
+				{Not: &config.FilterRule{Field: "title", Exact: "Lunch"}},
+			}},
+		}},
+		{Expr: `source == Home`, Exclude: true},
+	}})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	var got []string
+	for _, e := range f.Apply(events) {
+		got = append(got, e.UID)
+	}
+	if len(got) != 2 || got[0] != "work-standup" || got[1] != "boss" {
+		t.Fatalf("Apply() = %v, want [work-standup boss]", got)
+	}
+}
+
+func TestNew_RuleErrors(t *testing.T) {
+	for name, rules := range map[string][]config.FilterRule{
+		"empty rule":       {{}},
+		"field and group":  {{Field: "title", Contains: "a", Any: []config.FilterRule{{Field: "title", Contains: "b"}}}},
+		"nested exclude":   {{All: []config.FilterRule{{Field: "title", Contains: "a", Exclude: true}}}},
+		"bad nested rule":  {{Not: &config.FilterRule{Field: "colour", Contains: "a"}}},
+		"bad expression":   {{Expr: `title ~`}},
+		"legacy bad regex": {{Field: "title", Regex: "("}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(config.FilterConfig{Rules: rules}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	standup := calendar.Event{Summary: "Daily Standup", Source: "Work"}
+	ooo := calendar.Event{Summary: "OOO", Source: "Work"}
+	lunch := calendar.Event{Summary: "Lunch", Source: "Home"}
+
+	rules := []config.FilterRule{
+		{Field: "title", Contains: "OOO", Exclude: true},
+		{Field: "title", Contains: "standup", CaseInsensitive: true},
+		{Any: []config.FilterRule{{Field: "source", Exact: "Work"}, {Expr: `all_day`}}},
+	}
+
+	tests := []struct {
+		name  string
+		mode  string
+		rules []config.FilterRule
+		event calendar.Event
+		want  Decision
+	}{
+		{"no rules", "or", nil, lunch, Decision{Included: true, Rule: -1, Reason: "included: no rules"}},
+		{"excluded", "or", rules, ooo, Decision{Rule: 0, RuleDesc: `title contains "OOO"`, Reason: `excluded by rule 0: title contains "OOO"`}},
+		{"first include match", "or", rules, standup, Decision{
+			Included: true,
+			Rule:     1,
+			RuleDesc: `title contains "standup" (case-insensitive)`,
+			Reason:   `included by rule 1: title contains "standup" (case-insensitive)`,
+		}},
+		{"no include match", "or", rules, lunch, Decision{Rule: -1, Reason: "not included: matched no include rule"}},
+		{"and mode failure", "and", rules, calendar.Event{Summary: "Review", Source: "Work"}, Decision{
+			Rule:     1,
+			RuleDesc: `title contains "standup" (case-insensitive)`,
+			Reason:   `not included: rule 1 did not match (mode: and): title contains "standup" (case-insensitive)`,
+		}},
+		{"and mode success", "and", rules, standup, Decision{Included: true, Rule: -1, Reason: "included: matched every include rule"}},
+		{"only excludes", "or", rules[:1], lunch, Decision{Included: true, Rule: -1, Reason: "included: no exclude rule matched"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(config.FilterConfig{Mode: tt.mode, Rules: tt.rules})
+			if err != nil {
+				t.Fatalf("New error: %v", err)
+			}
+			if got := f.Explain(tt.event); got != tt.want {
+				t.Errorf("Explain() = %+v, want %+v", got, tt.want)
+			}
+			if included := len(f.Apply([]calendar.Event{tt.event})) == 1; included != tt.want.Included {
+				t.Errorf("Apply() kept event = %v, Explain says %v", included, tt.want.Included)
+			}
+		})
+	}
+}
+
+func TestDescribeRule(t *testing.T) {
+	r := config.FilterRule{Any: []config.FilterRule{
+		{Field: "start_time", Between: []string{"09:00", "18:00"}},
+		{Not: &config.FilterRule{Field: "all_day"}},
+		{Expr: `source in [Work, Home]`},
+	}}
+	want := `any(start_time between [09:00, 18:00], not(all_day), expr "source in [Work, Home]")`
+	if got := describeRule(r); got != want {
+		t.Errorf("describeRule() = %q, want %q", got, want)
+	}
+}
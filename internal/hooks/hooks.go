@@ -0,0 +1,173 @@
+// Package hooks runs user commands when events reach a point in their
+// lifecycle: a reminder, the start, the end, or joining the meeting.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/filter"
+	"github.com/cpuguy83/calbar/internal/links"
+)
+
+// Kind is a point in an event's lifecycle that hooks run on.
+type Kind string
+
+const (
+	Reminder Kind = "reminder"
+	Start    Kind = "start"
+	End      Kind = "end"
+	Join     Kind = "join"
+)
+
+// maxOutput is how much of a failed command's output is logged.
+const maxOutput = 512
+
+// Runner runs the configured hooks.
+type Runner struct {
+	hooks      []hook
+	encodeJSON func(calendar.Event) ([]byte, error)
+}
+
+type hook struct {
+	match    *filter.Matcher // nil matches every event
+	commands map[Kind]string
+	timeout  time.Duration
+}
+
+// New creates a Runner from the hook configs. encodeJSON encodes the event
+// passed to commands on stdin.
+func New(cfgs []config.HookConfig, encodeJSON func(calendar.Event) ([]byte, error)) (*Runner, error) {
+	r := &Runner{encodeJSON: encodeJSON}
+	for i, c := range cfgs {
+		h := hook{
+			commands: make(map[Kind]string),
+			timeout:  c.Timeout,
+		}
+		if c.Match != nil {
+			m, err := filter.Compile(*c.Match)
+			if err != nil {
+				return nil, fmt.Errorf("hook %d: match: %w", i, err)
+			}
+			h.match = m
+		}
+		for kind, cmd := range map[Kind]string{Reminder: c.OnReminder, Start: c.OnStart, End: c.OnEnd, Join: c.OnJoin} {
+			if cmd != "" {
+				h.commands[kind] = cmd
+			}
+		}
+		if len(h.commands) == 0 {
+			return nil, fmt.Errorf("hook %d: needs on_reminder, on_start, on_end or on_join", i)
+		}
+		if h.timeout <= 0 {
+			return nil, fmt.Errorf("hook %d: timeout must be positive", i)
+		}
+		r.hooks = append(r.hooks, h)
+	}
+	return r, nil
+}
+
+// Has reports whether any hook has a command for kind.
+func (r *Runner) Has(kind Kind) bool {
+	for _, h := range r.hooks {
+		if _, ok := h.commands[kind]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Run starts the commands of the hooks that match the event for kind in the
+// background. Failures are logged.
+func (r *Runner) Run(ctx context.Context, kind Kind, event calendar.Event) {
+	for _, h := range r.hooks {
+		cmd, ok := h.commands[kind]
+		if !ok || (h.match != nil && !h.match.Matches(event)) {
+			continue
+		}
+		go func() {
+			if err := r.run(ctx, cmd, h.timeout, kind, event); err != nil {
+				slog.Warn("hook failed", "hook", kind, "summary", event.Summary, "command", cmd, "error", err)
+			}
+		}()
+	}
+}
+
+// run runs one command with the event in its environment and as JSON on
+// stdin, and waits for it to finish.
+func (r *Runner) run(ctx context.Context, command string, timeout time.Duration, kind Kind, event calendar.Event) error {
+	payload, err := r.encodeJSON(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	slog.Debug("running hook", "hook", kind, "summary", event.Summary, "command", command)
+	start := time.Now()
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), Env(kind, event)...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.WaitDelay = time.Second // Don't wait on background processes holding the output open
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if output := strings.TrimSpace(out.String()); output != "" {
+			if len(output) > maxOutput {
+				output = output[:maxOutput] + "…"
+			}
+			return fmt.Errorf("%w: %s", err, output)
+		}
+		return err
+	}
+
+	slog.Debug("hook finished", "hook", kind, "summary", event.Summary, "duration", time.Since(start))
+	return nil
+}
+
+// Env returns the environment variables describing an event for a hook.
+func Env(kind Kind, e calendar.Event) []string {
+	link := e.Meeting.URL
+	if link == "" {
+		link = links.DetectFromEvent(e.Location, e.Description, e.URL)
+	}
+	service := e.Meeting.Service
+	if service == "" && link != "" {
+		service = links.Service(link)
+	}
+
+	return []string{
+		"CALBAR_HOOK=" + string(kind),
+		"CALBAR_UID=" + e.UID,
+		"CALBAR_SUMMARY=" + e.Summary,
+		"CALBAR_START=" + e.Start.Format(time.RFC3339),
+		"CALBAR_END=" + e.End.Format(time.RFC3339),
+		"CALBAR_ALL_DAY=" + strconv.FormatBool(e.AllDay),
+		"CALBAR_LOCATION=" + e.Location,
+		"CALBAR_SOURCE=" + e.Source,
+		"CALBAR_ORGANIZER=" + e.Organizer,
+		"CALBAR_MEETING_URL=" + link,
+		"CALBAR_MEETING_SERVICE=" + service,
+		"CALBAR_URL=" + e.URL,
+		"CALBAR_STATUS=" + string(e.Status),
+		"CALBAR_RESPONSE=" + string(e.Response),
+		"CALBAR_TAGS=" + strings.Join(e.Tags, ","),
+	}
+}
@@ -0,0 +1,112 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+)
+
+func newTestRunner(t *testing.T, cfgs []config.HookConfig) *Runner {
+	t.Helper()
+	for i := range cfgs {
+		if cfgs[i].Timeout == 0 {
+			cfgs[i].Timeout = 5 * time.Second
+		}
+	}
+	r, err := New(cfgs, func(e calendar.Event) ([]byte, error) { return json.Marshal(e) })
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return r
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	r := newTestRunner(t, []config.HookConfig{{
+		OnStart: `printf '%s|%s|%s|' "$CALBAR_HOOK" "$CALBAR_SUMMARY" "$CALBAR_MEETING_SERVICE" > "` + out + `" && cat >> "` + out + `"`,
+	}})
+
+	event := calendar.Event{
+		UID:     "standup",
+		Summary: "Standup",
+		Start:   time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		End:     time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC),
+		Meeting: calendar.MeetingDetails{URL: "https://meet.google.com/abc-defg-hij"},
+	}
+	if err := r.run(context.Background(), r.hooks[0].commands[Start], time.Second, Start, event); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, stdin, _ := strings.Cut(string(data), "|Meet|")
+	if env != "start|Standup" {
+		t.Errorf("environment = %q, want %q", env, "start|Standup")
+	}
+	var got calendar.Event
+	if err := json.Unmarshal([]byte(stdin), &got); err != nil {
+		t.Fatalf("stdin is not the event JSON: %v (%q)", err, stdin)
+	}
+	if got.UID != "standup" {
+		t.Errorf("stdin UID = %q, want standup", got.UID)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	r := newTestRunner(t, []config.HookConfig{{OnEnd: "true"}})
+	tests := []struct {
+		name    string
+		command string
+		timeout time.Duration
+		want    string
+	}{
+		{"exit status", "echo 'no light found' >&2; exit 3", time.Second, "exit status 3: no light found"},
+		{"timeout", "sleep 5", 100 * time.Millisecond, "timed out after 100ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.run(context.Background(), tt.command, tt.timeout, End, calendar.Event{})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("run() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.HookConfig
+		err  string
+	}{
+		{"no command", config.HookConfig{Timeout: time.Second}, "hook 0: needs on_reminder, on_start, on_end or on_join"},
+		{"bad match", config.HookConfig{OnStart: "true", Timeout: time.Second, Match: &config.FilterRule{Expr: "title =="}}, "hook 0: match:"},
+		{"bad timeout", config.HookConfig{OnStart: "true", Timeout: -time.Second}, "hook 0: timeout must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New([]config.HookConfig{tt.cfg}, nil)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("New() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	r := newTestRunner(t, []config.HookConfig{
+		{OnStart: "true", Match: &config.FilterRule{Expr: `source == "Work"`}},
+		{OnEnd: "true"},
+	})
+	if !r.Has(Start) || !r.Has(End) || r.Has(Join) || r.Has(Reminder) {
+		t.Error("Has() does not match the configured commands")
+	}
+}
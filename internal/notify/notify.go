@@ -4,6 +4,8 @@ package notify
 import (
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,11 +17,30 @@ const (
 	notifyPath      = "/org/freedesktop/Notifications"
 )
 
+// Hints sent with every notification. The desktop entry lets servers group
+// and configure CalBar's notifications by application.
+const (
+	desktopEntry = "calbar"
+	category     = "x-calbar.reminder"
+)
+
+// serverMaxActions is how many action buttons notification servers show, for
+// servers known to show only the first few.
+var serverMaxActions = map[string]int{
+	"gnome-shell": 3,
+}
+
+// defaultCapabilities are assumed when the server does not report its
+// capabilities.
+var defaultCapabilities = []string{"actions", "body"}
+
 // Notifier sends desktop notifications via D-Bus.
 type Notifier struct {
 	conn    *dbus.Conn
 	obj     dbus.BusObject
 	appName string
+	caps    []string // Capabilities reported by the server
+	server  string   // Server name, if known
 
 	mu       sync.Mutex
 	notified map[string]time.Time // Track notified event UIDs to avoid duplicates
@@ -32,12 +53,39 @@ func New(appName string) (*Notifier, error) {
 		return nil, fmt.Errorf("connect to session bus: %w", err)
 	}
 
-	return &Notifier{
+	n := &Notifier{
 		conn:     conn,
 		obj:      conn.Object(notifyInterface, notifyPath),
 		appName:  appName,
 		notified: make(map[string]time.Time),
-	}, nil
+	}
+
+	if err := n.obj.Call(notifyInterface+".GetCapabilities", 0).Store(&n.caps); err != nil {
+		slog.Debug("failed to get notification server capabilities", "error", err)
+		n.caps = defaultCapabilities
+	}
+	slog.Debug("notification server capabilities", "capabilities", n.caps)
+
+	if n.server, err = n.ServerName(); err != nil {
+		slog.Debug("failed to get notification server", "error", err)
+	}
+
+	return n, nil
+}
+
+// MaxActions returns how many action buttons the server shows, or 0 if it
+// shows them all.
+func (n *Notifier) MaxActions() int {
+	if n == nil {
+		return 0
+	}
+	return serverMaxActions[n.server]
+}
+
+// HasCapability reports whether the notification server has a capability
+// from the notification spec, such as "actions" or "body-markup".
+func (n *Notifier) HasCapability(name string) bool {
+	return slices.Contains(n.caps, name)
 }
 
 // Close closes the D-Bus connection.
@@ -54,6 +102,17 @@ type Notification struct {
 	Actions []Action
 	Urgency Urgency
 
+	// Event details shown below Body as the server allows: with markup if
+	// it supports it, and the meeting link as a clickable link, or as text
+	// when the server can't show a Join action.
+	Location  string
+	Organizer string
+	Link      string
+
+	// ReplacesID updates the notification with this ID in place instead of
+	// showing a new one.
+	ReplacesID uint32
+
 	// For tracking
 	EventUID string
 }
@@ -75,8 +134,9 @@ const (
 
 // Send sends a notification and returns the notification ID.
 func (n *Notifier) Send(notif Notification) (uint32, error) {
-	// Check if we already notified for this event recently
-	if notif.EventUID != "" {
+	// Check if we already notified for this event recently. Updates of a
+	// notification are always sent.
+	if notif.EventUID != "" && notif.ReplacesID == 0 {
 		n.mu.Lock()
 		if lastNotified, ok := n.notified[notif.EventUID]; ok {
 			// Don't re-notify within 1 minute
@@ -89,16 +149,7 @@ func (n *Notifier) Send(notif Notification) (uint32, error) {
 		n.mu.Unlock()
 	}
 
-	// Build actions array: [key1, label1, key2, label2, ...]
-	var actions []string
-	for _, a := range notif.Actions {
-		actions = append(actions, a.Key, a.Label)
-	}
-
-	// Build hints map
-	hints := map[string]dbus.Variant{
-		"urgency": dbus.MakeVariant(byte(notif.Urgency)),
-	}
+	summary, body, actions := n.render(notif)
 
 	// Calculate timeout in milliseconds
 	timeout := int32(-1) // Use default
@@ -114,17 +165,24 @@ func (n *Notifier) Send(notif Notification) (uint32, error) {
 		icon = "x-office-calendar"
 	}
 
+	hints := map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(byte(notif.Urgency)),
+		"desktop-entry": dbus.MakeVariant(desktopEntry),
+		"category":      dbus.MakeVariant(category),
+		"image-path":    dbus.MakeVariant(icon),
+	}
+
 	call := n.obj.Call(
 		notifyInterface+".Notify",
 		0,
-		n.appName,     // app_name
-		uint32(0),     // replaces_id (0 = new notification)
-		icon,          // app_icon
-		notif.Summary, // summary
-		notif.Body,    // body
-		actions,       // actions
-		hints,         // hints
-		timeout,       // expire_timeout
+		n.appName,        // app_name
+		notif.ReplacesID, // replaces_id (0 = new notification)
+		icon,             // app_icon
+		summary,          // summary
+		body,             // body
+		actions,          // actions
+		hints,            // hints
+		timeout,          // expire_timeout
 	)
 
 	if call.Err != nil {
@@ -136,16 +194,126 @@ func (n *Notifier) Send(notif Notification) (uint32, error) {
 		return 0, fmt.Errorf("get notification id: %w", err)
 	}
 
-	slog.Debug("sent notification", "id", id, "summary", notif.Summary)
+	slog.Debug("sent notification", "id", id, "replaces", notif.ReplacesID, "summary", notif.Summary)
 	return id, nil
 }
 
+// render returns the summary, body and actions to send for notif, adapted
+// to the server's capabilities.
+func (n *Notifier) render(notif Notification) (summary, body string, actions []string) {
+	hasActions := n.HasCapability("actions")
+	markup := n.HasCapability("body-markup")
+	hyperlinks := markup && n.HasCapability("body-hyperlinks")
+
+	if !n.HasCapability("body") {
+		// Keep the first line, which says when the event starts
+		summary = notif.Summary
+		if first, _, _ := strings.Cut(notif.Body, "\n"); first != "" {
+			summary += " – " + first
+		}
+		if !hasActions && notif.Link != "" {
+			summary += " – " + notif.Link
+		}
+		return summary, "", nil
+	}
+
+	text := func(s string) string {
+		if markup {
+			return markupEscaper.Replace(s)
+		}
+		return s
+	}
+	label := func(name, value string) string {
+		if markup {
+			return "<b>" + name + ":</b> " + text(value)
+		}
+		return name + ": " + value
+	}
+
+	var lines []string
+	if notif.Body != "" {
+		lines = append(lines, text(notif.Body))
+	}
+	if notif.Location != "" && notif.Location != notif.Link {
+		lines = append(lines, label("Location", notif.Location))
+	}
+	if notif.Organizer != "" {
+		lines = append(lines, label("Organizer", notif.Organizer))
+	}
+	switch {
+	case notif.Link == "":
+	case hyperlinks:
+		lines = append(lines, `<a href="`+text(notif.Link)+`">Join meeting</a>`)
+	case !hasActions:
+		lines = append(lines, text(notif.Link))
+	}
+
+	// Build actions array: [key1, label1, key2, label2, ...]
+	if hasActions {
+		for _, a := range notif.Actions {
+			actions = append(actions, a.Key, a.Label)
+		}
+	}
+
+	return notif.Summary, strings.Join(lines, "\n"), actions
+}
+
+// markupEscaper escapes text for the body markup of the notification spec.
+var markupEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+)
+
+// ServerName returns the name of the running notification server, such as
+// "mako", "dunst" or "gnome-shell".
+func (n *Notifier) ServerName() (string, error) {
+	var name, vendor, version, specVersion string
+	if err := n.obj.Call(notifyInterface+".GetServerInformation", 0).Store(&name, &vendor, &version, &specVersion); err != nil {
+		return "", fmt.Errorf("get server information: %w", err)
+	}
+	return name, nil
+}
+
+// CloseNotification closes a notification.
+func (n *Notifier) CloseNotification(id uint32) error {
+	if err := n.obj.Call(notifyInterface+".CloseNotification", 0, id).Err; err != nil {
+		return fmt.Errorf("close notification: %w", err)
+	}
+	return nil
+}
+
 // WatchActions listens for notification action invocations.
 // The callback receives the notification ID and action key.
 func (n *Notifier) WatchActions(callback func(id uint32, actionKey string)) error {
+	return n.watch("ActionInvoked", func(body []any) {
+		id, ok1 := body[0].(uint32)
+		key, ok2 := body[1].(string)
+		if ok1 && ok2 {
+			callback(id, key)
+		}
+	})
+}
+
+// WatchClosed listens for notifications being closed, whether they expired,
+// were dismissed by the user or closed with CloseNotification.
+// The callback receives the notification ID.
+func (n *Notifier) WatchClosed(callback func(id uint32)) error {
+	return n.watch("NotificationClosed", func(body []any) {
+		if id, ok := body[0].(uint32); ok {
+			callback(id)
+		}
+	})
+}
+
+// watch calls handle with the body of each signal with the given name that
+// has at least two values, as ActionInvoked and NotificationClosed do.
+func (n *Notifier) watch(member string, handle func(body []any)) error {
 	if err := n.conn.AddMatchSignal(
 		dbus.WithMatchInterface(notifyInterface),
-		dbus.WithMatchMember("ActionInvoked"),
+		dbus.WithMatchMember(member),
 	); err != nil {
 		return fmt.Errorf("add match signal: %w", err)
 	}
@@ -155,25 +323,21 @@ func (n *Notifier) WatchActions(callback func(id uint32, actionKey string)) erro
 
 	go func() {
 		for sig := range ch {
-			if sig.Name != notifyInterface+".ActionInvoked" {
+			if sig.Name != notifyInterface+"."+member {
 				continue
 			}
 			if len(sig.Body) < 2 {
 				continue
 			}
-
-			id, ok1 := sig.Body[0].(uint32)
-			key, ok2 := sig.Body[1].(string)
-			if ok1 && ok2 {
-				callback(id, key)
-			}
+			handle(sig.Body)
 		}
 	}()
 
 	return nil
 }
 
-// CleanupOldNotifications removes tracking entries older than maxAge.
+// CleanupOldNotifications forgets events notified about more than maxAge
+// ago, so the map of recently notified events does not grow without bound.
 func (n *Notifier) CleanupOldNotifications(maxAge time.Duration) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
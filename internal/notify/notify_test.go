@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	notif := Notification{
+		Summary:   "Design review",
+		Body:      "Starts in 5 minutes",
+		Location:  "Room <4> & annex",
+		Organizer: "ana@example.com",
+		Link:      "https://meet.google.com/abc-defg-hij?a=1&b=2",
+		Actions:   []Action{{Key: "join", Label: "Join Meeting"}},
+	}
+
+	tests := []struct {
+		name    string
+		caps    []string
+		summary string
+		body    string
+		actions []string
+	}{
+		{
+			name:    "plain text with actions",
+			caps:    []string{"actions", "body"},
+			summary: "Design review",
+			body:    "Starts in 5 minutes\nLocation: Room <4> & annex\nOrganizer: ana@example.com",
+			actions: []string{"join", "Join Meeting"},
+		},
+		{
+			name:    "plain text without actions",
+			caps:    []string{"body"},
+			summary: "Design review",
+			body:    "Starts in 5 minutes\nLocation: Room <4> & annex\nOrganizer: ana@example.com\nhttps://meet.google.com/abc-defg-hij?a=1&b=2",
+		},
+		{
+			name:    "markup without hyperlinks",
+			caps:    []string{"body", "body-markup"},
+			summary: "Design review",
+			body:    "Starts in 5 minutes\n<b>Location:</b> Room &lt;4&gt; &amp; annex\n<b>Organizer:</b> ana@example.com\nhttps://meet.google.com/abc-defg-hij?a=1&amp;b=2",
+		},
+		{
+			name:    "hyperlinks",
+			caps:    []string{"actions", "body", "body-markup", "body-hyperlinks"},
+			summary: "Design review",
+			body:    "Starts in 5 minutes\n<b>Location:</b> Room &lt;4&gt; &amp; annex\n<b>Organizer:</b> ana@example.com\n<a href=\"https://meet.google.com/abc-defg-hij?a=1&amp;b=2\">Join meeting</a>",
+			actions: []string{"join", "Join Meeting"},
+		},
+		{
+			name:    "no body",
+			caps:    []string{"actions"},
+			summary: "Design review – Starts in 5 minutes",
+		},
+		{
+			name:    "no body or actions",
+			caps:    nil,
+			summary: "Design review – Starts in 5 minutes – https://meet.google.com/abc-defg-hij?a=1&b=2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Notifier{caps: tt.caps}
+			summary, body, actions := n.render(notif)
+			if summary != tt.summary {
+				t.Errorf("summary = %q, want %q", summary, tt.summary)
+			}
+			if body != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+			if !slices.Equal(actions, tt.actions) {
+				t.Errorf("actions = %q, want %q", actions, tt.actions)
+			}
+		})
+	}
+
+	// A location that is the meeting link is not repeated
+	n := &Notifier{caps: []string{"actions", "body"}}
+	_, body, _ := n.render(Notification{Body: "Started", Location: notif.Link, Link: notif.Link})
+	if body != "Started" {
+		t.Errorf("body = %q, want %q", body, "Started")
+	}
+}
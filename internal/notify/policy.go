@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/filter"
+)
+
+// criticalWithin is how close to the start a reminder becomes critical when
+// no rule sets the urgency.
+const criticalWithin = 5 * time.Minute
+
+// Policy decides when to notify about an event and how urgently, from the
+// global reminder offsets and the per-event notification rules.
+type Policy struct {
+	before []time.Duration
+	rules  []policyRule
+}
+
+type policyRule struct {
+	match   *filter.Matcher
+	before  []time.Duration // nil leaves the offsets alone; empty turns notifications off
+	urgency *Urgency
+}
+
+// urgencies maps config urgency names to levels.
+var urgencies = map[string]Urgency{
+	"low":      UrgencyLow,
+	"normal":   UrgencyNormal,
+	"critical": UrgencyCritical,
+}
+
+// NewPolicy creates a Policy from configuration.
+func NewPolicy(cfg config.NotificationConfig) (*Policy, error) {
+	p := &Policy{before: cfg.Before}
+	for i, r := range cfg.Rules {
+		m, err := filter.Compile(r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: match: %w", i, err)
+		}
+		compiled := policyRule{match: m, before: r.Before}
+		if r.Urgency != "" {
+			u, ok := urgencies[r.Urgency]
+			if !ok {
+				return nil, fmt.Errorf("rule %d: unknown urgency %q (use low, normal, or critical)", i, r.Urgency)
+			}
+			compiled.urgency = &u
+		}
+		if compiled.before == nil && compiled.urgency == nil {
+			return nil, fmt.Errorf("rule %d: needs before or urgency", i)
+		}
+		p.rules = append(p.rules, compiled)
+	}
+	return p, nil
+}
+
+// Before returns the configured reminder offsets for an event, or nil if the
+// event's own reminders apply. An empty, non-nil result means no
+// notifications.
+func (p *Policy) Before(event calendar.Event) []time.Duration {
+	for _, r := range p.rules {
+		if r.before != nil && r.match.Matches(event) {
+			return r.before
+		}
+	}
+	return p.before
+}
+
+// Triggers returns the times to notify about an event. Reminders set by a
+// transform take precedence over the configured offsets.
+func (p *Policy) Triggers(event calendar.Event) []time.Time {
+	if event.NotifySet {
+		return event.NotifyAt
+	}
+	before := p.Before(event)
+	if before == nil {
+		return event.NotifyAt
+	}
+	triggers := make([]time.Time, 0, len(before))
+	for _, b := range before {
+		triggers = append(triggers, event.Start.Add(-b))
+	}
+	return triggers
+}
+
+// Urgency returns the urgency of a notification for an event that starts in
+// startsIn.
+func (p *Policy) Urgency(event calendar.Event, startsIn time.Duration) Urgency {
+	for _, r := range p.rules {
+		if r.urgency != nil && r.match.Matches(event) {
+			return *r.urgency
+		}
+	}
+	if startsIn <= criticalWithin {
+		return UrgencyCritical
+	}
+	return UrgencyNormal
+}
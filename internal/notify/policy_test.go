@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+)
+
+func TestPolicy(t *testing.T) {
+	start := time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)
+	own := []time.Time{start.Add(-15 * time.Minute)}
+
+	p, err := NewPolicy(config.NotificationConfig{
+		Rules: []config.NotificationRule{
+			{Match: config.FilterRule{Field: "title", Contains: "all-hands", CaseInsensitive: true}, Before: []time.Duration{10 * time.Minute, time.Minute}},
+			{Match: config.FilterRule{Field: "title", Exact: "1:1"}, Before: []time.Duration{2 * time.Minute}},
+			{Match: config.FilterRule{Field: "source", Exact: "Holidays"}, Before: []time.Duration{}},
+			{Match: config.FilterRule{Expr: `not organizer $= "@company.com"`}, Urgency: "critical"},
+			// Never reached for before: the all-hands rule comes first
+			{Match: config.FilterRule{Field: "source", Exact: "Work"}, Before: []time.Duration{time.Hour}, Urgency: "low"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		event    calendar.Event
+		triggers []time.Time
+		urgency  Urgency
+	}{
+		{
+			name:     "first matching rule sets the offsets",
+			event:    calendar.Event{Summary: "Q3 All-Hands", Organizer: "ceo@company.com", Source: "Work", Start: start, NotifyAt: own},
+			triggers: []time.Time{start.Add(-10 * time.Minute), start.Add(-time.Minute)},
+			urgency:  UrgencyLow,
+		},
+		{
+			name:     "later rule sets the urgency",
+			event:    calendar.Event{Summary: "1:1", Organizer: "partner@example.com", Start: start, NotifyAt: own},
+			triggers: []time.Time{start.Add(-2 * time.Minute)},
+			urgency:  UrgencyCritical,
+		},
+		{
+			name:    "empty offsets turn notifications off",
+			event:   calendar.Event{Summary: "Thanksgiving", Organizer: "holidays@company.com", Source: "Holidays", Start: start, NotifyAt: own},
+			urgency: UrgencyNormal,
+		},
+		{
+			name:     "no rule keeps the event reminders",
+			event:    calendar.Event{Summary: "Review", Organizer: "me@company.com", Start: start, NotifyAt: own},
+			triggers: own,
+			urgency:  UrgencyNormal,
+		},
+		{
+			name:     "transform reminders win",
+			event:    calendar.Event{Summary: "1:1", Organizer: "me@company.com", Start: start, NotifyAt: own, NotifySet: true},
+			triggers: own,
+			urgency:  UrgencyNormal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Triggers(tt.event)
+			if !slices.EqualFunc(got, tt.triggers, time.Time.Equal) {
+				t.Errorf("Triggers() = %v, want %v", got, tt.triggers)
+			}
+			if got := p.Urgency(tt.event, time.Hour); got != tt.urgency {
+				t.Errorf("Urgency() = %d, want %d", got, tt.urgency)
+			}
+		})
+	}
+}
+
+func TestPolicy_DefaultUrgency(t *testing.T) {
+	p, err := NewPolicy(config.NotificationConfig{})
+	if err != nil {
+		t.Fatalf("NewPolicy error: %v", err)
+	}
+	event := calendar.Event{Summary: "Standup"}
+	if got := p.Urgency(event, 15*time.Minute); got != UrgencyNormal {
+		t.Errorf("Urgency(15m) = %d, want normal", got)
+	}
+	if got := p.Urgency(event, 5*time.Minute); got != UrgencyCritical {
+		t.Errorf("Urgency(5m) = %d, want critical", got)
+	}
+}
+
+func TestNewPolicy_Errors(t *testing.T) {
+	for name, rule := range map[string]config.NotificationRule{
+		"bad match":     {Match: config.FilterRule{Field: "colour", Contains: "red"}, Urgency: "low"},
+		"empty match":   {Urgency: "low"},
+		"bad urgency":   {Match: config.FilterRule{Field: "title", Contains: "a"}, Urgency: "urgent"},
+		"no setting":    {Match: config.FilterRule{Field: "title", Contains: "a"}},
+		"exclude match": {Match: config.FilterRule{Field: "title", Contains: "a", Exclude: true}, Urgency: "low"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewPolicy(config.NotificationConfig{Rules: []config.NotificationRule{rule}}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
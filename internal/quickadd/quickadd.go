@@ -0,0 +1,455 @@
+// Package quickadd parses short natural-language event descriptions such as
+// "Focus 2pm-4pm tomorrow" or "Lunch with Sam fri 12:30 1h" into events.
+package quickadd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+)
+
+// Parse turns text into an event relative to now.
+//
+// Recognized parts, in any order:
+//   - dates: "today", "tomorrow", weekdays ("fri", "next monday"), "2026-10-20",
+//     "10/20", "oct 20", "20 oct"
+//   - times: "2pm", "14:00", "noon", "at 9:30"; ranges: "2pm-4pm", "2-4pm",
+//     "from 2 to 3:30"
+//   - durations: "1h", "30m", "1h30m", "90 minutes", "for 2 hours"
+//   - "all day"
+//
+// Everything else becomes the event title. Times without am/pm from 1 to 7
+// are taken as afternoon times. Without a date, the event is today, or
+// tomorrow if the start time has already passed. A date without a time makes
+// an all-day event. defaultDuration is used when no end or duration is given.
+func Parse(text string, now time.Time, defaultDuration time.Duration) (calendar.Event, error) {
+	p := &parser{now: now}
+
+	tokens := strings.Fields(text)
+	for i := 0; i < len(tokens); {
+		if n := p.match(tokens[i:]); n > 0 {
+			i += n
+			continue
+		}
+		p.words = append(p.words, tokens[i])
+		i++
+	}
+
+	return p.event(text, defaultDuration)
+}
+
+// clock is a time of day as written, before am/pm is resolved.
+type clock struct {
+	hour, min int
+	meridiem  string // "am", "pm" or ""
+	padded    bool   // written with a leading zero ("07:00"), so 24-hour
+}
+
+// minutes returns the clock as minutes after midnight.
+func (c clock) minutes() int {
+	h := c.hour
+	switch c.meridiem {
+	case "am":
+		h %= 12
+	case "pm":
+		h = h%12 + 12
+	default:
+		if h >= 1 && h <= 7 && !c.padded {
+			h += 12
+		}
+	}
+	return h*60 + c.min
+}
+
+type parser struct {
+	now time.Time
+
+	date     time.Time // Midnight of the event day; zero if not given
+	start    *clock
+	end      *clock
+	duration time.Duration
+	allDay   bool
+
+	words []string // Title words
+}
+
+// connectors introduce a date, time or duration and are dropped along with it.
+var connectors = map[string]bool{"at": true, "on": true, "from": true, "for": true}
+
+// match tries to consume a date, time, duration or connector phrase at the
+// start of tokens and returns the number of tokens consumed.
+func (p *parser) match(tokens []string) int {
+	first := normalize(tokens[0])
+
+	if n := p.matchAllDay(tokens); n > 0 {
+		return n
+	}
+	if connectors[first] && len(tokens) > 1 {
+		if n := p.matchValue(tokens[1:], first); n > 0 {
+			return n + 1
+		}
+		return 0
+	}
+	return p.matchValue(tokens, "")
+}
+
+// matchValue matches a date, time or duration, restricted to the kinds that
+// make sense after the given connector.
+func (p *parser) matchValue(tokens []string, connector string) int {
+	if p.date.IsZero() && (connector == "" || connector == "on") {
+		if n := p.matchDate(tokens); n > 0 {
+			return n
+		}
+	}
+	if p.start == nil && (connector == "" || connector == "at" || connector == "from") {
+		if n := p.matchTime(tokens, connector != ""); n > 0 {
+			return n
+		}
+	}
+	if p.duration == 0 && p.end == nil && (connector == "" || connector == "for") {
+		if n := p.matchDuration(tokens); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+func (p *parser) matchAllDay(tokens []string) int {
+	switch normalize(tokens[0]) {
+	case "all-day", "allday":
+		p.allDay = true
+		return 1
+	case "all":
+		if len(tokens) > 1 && normalize(tokens[1]) == "day" {
+			p.allDay = true
+			return 2
+		}
+	}
+	return 0
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+var (
+	isoDateRe   = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	slashDateRe = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{4}))?$`)
+	dayOfMonth  = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+)
+
+func (p *parser) matchDate(tokens []string) int {
+	today := midnight(p.now)
+	first := normalize(tokens[0])
+
+	switch first {
+	case "today", "tonight":
+		p.date = today
+		return 1
+	case "tomorrow", "tmrw", "tmr":
+		p.date = today.AddDate(0, 0, 1)
+		return 1
+	case "next":
+		if len(tokens) > 1 {
+			if wd, ok := weekdays[normalize(tokens[1])]; ok {
+				days := (int(wd) - int(today.Weekday()) + 7) % 7
+				if days == 0 {
+					days = 7
+				}
+				p.date = today.AddDate(0, 0, days)
+				return 2
+			}
+		}
+		return 0
+	}
+
+	if wd, ok := weekdays[first]; ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		p.date = today.AddDate(0, 0, days)
+		return 1
+	}
+
+	if m := isoDateRe.FindStringSubmatch(first); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		return p.setDate(year, time.Month(month), day, 1)
+	}
+
+	if m := slashDateRe.FindStringSubmatch(first); m != nil {
+		month, _ := strconv.Atoi(m[1])
+		day, _ := strconv.Atoi(m[2])
+		if m[3] != "" {
+			year, _ := strconv.Atoi(m[3])
+			return p.setDate(year, time.Month(month), day, 1)
+		}
+		return p.setUpcomingDate(time.Month(month), day, 1)
+	}
+
+	// "oct 20" or "20 oct"
+	if len(tokens) > 1 {
+		second := normalize(tokens[1])
+		if month, ok := months[first]; ok {
+			if m := dayOfMonth.FindStringSubmatch(second); m != nil {
+				day, _ := strconv.Atoi(m[1])
+				return p.setUpcomingDate(month, day, 2)
+			}
+		}
+		if month, ok := months[second]; ok {
+			if m := dayOfMonth.FindStringSubmatch(first); m != nil {
+				day, _ := strconv.Atoi(m[1])
+				return p.setUpcomingDate(month, day, 2)
+			}
+		}
+	}
+
+	return 0
+}
+
+// setDate sets an explicit date if it is valid and returns n, or 0.
+func (p *parser) setDate(year int, month time.Month, day, n int) int {
+	if month < time.January || month > time.December || day < 1 || day > 31 {
+		return 0
+	}
+	d := time.Date(year, month, day, 0, 0, 0, 0, p.now.Location())
+	if d.Day() != day {
+		return 0 // e.g. Feb 30
+	}
+	p.date = d
+	return n
+}
+
+// setUpcomingDate sets the next occurrence of month/day, today included.
+func (p *parser) setUpcomingDate(month time.Month, day, n int) int {
+	year := p.now.Year()
+	if time.Date(year, month, day, 0, 0, 0, 0, p.now.Location()).Before(midnight(p.now)) {
+		year++
+	}
+	return p.setDate(year, month, day, n)
+}
+
+var (
+	clockRe = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm|a|p)?$`)
+	rangeRe = regexp.MustCompile(`^([0-9:apm]+)[-–]([0-9:apm]+)$`)
+)
+
+// parseClock parses a time of day. Bare hours ("2") are only accepted when
+// bare is set, since they are otherwise indistinguishable from title words.
+func parseClock(s string, bare bool) (clock, bool) {
+	switch s {
+	case "noon":
+		return clock{hour: 12, meridiem: "pm"}, true
+	case "midnight":
+		return clock{hour: 12, meridiem: "am"}, true
+	}
+
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil {
+		return clock{}, false
+	}
+	if m[2] == "" && m[3] == "" && !bare {
+		return clock{}, false
+	}
+
+	c := clock{padded: len(m[1]) == 2 && m[1][0] == '0'}
+	c.hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		c.min, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am", "a":
+		c.meridiem = "am"
+	case "pm", "p":
+		c.meridiem = "pm"
+	}
+
+	if c.min > 59 || c.hour > 23 || (c.meridiem != "" && (c.hour < 1 || c.hour > 12)) {
+		return clock{}, false
+	}
+	return c, true
+}
+
+// rangeSeparators join the two times of a range written as separate words.
+var rangeSeparators = map[string]bool{"-": true, "–": true, "to": true, "until": true, "till": true}
+
+func (p *parser) matchTime(tokens []string, bare bool) int {
+	first := normalize(tokens[0])
+
+	// Single-token range: "2pm-4pm", "2-4pm", "14:00-16:00"
+	if m := rangeRe.FindStringSubmatch(first); m != nil {
+		start, ok1 := parseClock(m[1], true)
+		end, ok2 := parseClock(m[2], true)
+		if ok1 && ok2 && (bare || explicit(m[1]) || explicit(m[2])) {
+			p.setRange(start, end)
+			return 1
+		}
+	}
+
+	start, ok := parseClock(first, bare)
+	if !ok {
+		return 0
+	}
+
+	// Separate-word range: "2pm - 4pm", "from 2 to 3:30"
+	if len(tokens) > 2 && rangeSeparators[normalize(tokens[1])] {
+		if end, ok := parseClock(normalize(tokens[2]), true); ok {
+			p.setRange(start, end)
+			return 3
+		}
+	}
+
+	p.start = &start
+	return 1
+}
+
+// explicit reports whether a time string is unambiguously a time.
+func explicit(s string) bool {
+	return strings.Contains(s, ":") || strings.HasSuffix(s, "m") || strings.HasSuffix(s, "a") || strings.HasSuffix(s, "p")
+}
+
+// setRange sets start and end times. A start without am/pm takes the end's,
+// unless that would put it after the end ("11-1pm").
+func (p *parser) setRange(start, end clock) {
+	if start.meridiem == "" && end.meridiem != "" {
+		start.meridiem = end.meridiem
+		if start.minutes() > end.minutes() {
+			start.meridiem = "am"
+		}
+	}
+	p.start = &start
+	p.end = &end
+}
+
+var (
+	durationRe     = regexp.MustCompile(`^(\d+(?:\.\d+)?)(h|hr|hrs|hour|hours|m|min|mins|minute|minutes)$`)
+	hourMinRe      = regexp.MustCompile(`^(\d+)h(\d+)(?:m|min)?$`)
+	durationNumRe  = regexp.MustCompile(`^\d+(?:\.\d+)?$`)
+	durationUnitRe = regexp.MustCompile(`^(h|hr|hrs|hour|hours|m|min|mins|minute|minutes)$`)
+)
+
+func (p *parser) matchDuration(tokens []string) int {
+	first := normalize(tokens[0])
+
+	if m := hourMinRe.FindStringSubmatch(first); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		return p.setDuration(time.Duration(h)*time.Hour+time.Duration(min)*time.Minute, 1)
+	}
+	if m := durationRe.FindStringSubmatch(first); m != nil {
+		return p.setDuration(durationValue(m[1], m[2]), 1)
+	}
+	if len(tokens) > 1 && durationNumRe.MatchString(first) {
+		if unit := normalize(tokens[1]); durationUnitRe.MatchString(unit) {
+			return p.setDuration(durationValue(first, unit), 2)
+		}
+	}
+	return 0
+}
+
+func (p *parser) setDuration(d time.Duration, n int) int {
+	if d <= 0 {
+		return 0
+	}
+	p.duration = d
+	return n
+}
+
+func durationValue(num, unit string) time.Duration {
+	v, _ := strconv.ParseFloat(num, 64)
+	if strings.HasPrefix(unit, "h") {
+		return time.Duration(v * float64(time.Hour))
+	}
+	return time.Duration(v * float64(time.Minute))
+}
+
+// event builds the parsed event.
+func (p *parser) event(text string, defaultDuration time.Duration) (calendar.Event, error) {
+	summary := strings.Join(p.words, " ")
+	if summary == "" {
+		return calendar.Event{}, fmt.Errorf("no event title in %q", text)
+	}
+	if p.date.IsZero() && p.start == nil && !p.allDay {
+		return calendar.Event{}, fmt.Errorf("no date or time in %q", text)
+	}
+
+	event := calendar.Event{Summary: summary}
+
+	date := p.date
+	if date.IsZero() {
+		date = midnight(p.now)
+	}
+
+	if p.allDay || p.start == nil {
+		days := 1
+		if p.duration >= 24*time.Hour {
+			days = int(p.duration / (24 * time.Hour))
+		}
+		event.AllDay = true
+		event.Start = date
+		event.End = date.AddDate(0, 0, days)
+		return event, nil
+	}
+
+	event.Start = atClock(date, *p.start)
+	if p.date.IsZero() && event.Start.Before(p.now) {
+		// A time without a date that has already passed means tomorrow
+		event.Start = event.Start.AddDate(0, 0, 1)
+	}
+
+	switch {
+	case p.end != nil:
+		end := atClock(event.Start, *p.end)
+		if !end.After(event.Start) {
+			end = atClock(event.Start.AddDate(0, 0, 1), *p.end) // Ends after midnight
+		}
+		event.End = end
+	case p.duration > 0:
+		event.End = event.Start.Add(p.duration)
+	default:
+		event.End = event.Start.Add(defaultDuration)
+	}
+
+	return event, nil
+}
+
+// normalize lowercases a token and strips surrounding punctuation.
+func normalize(s string) string {
+	return strings.ToLower(strings.Trim(s, ",.;!?()\"'"))
+}
+
+// atClock returns the wall clock time c on the day of date. Adding the
+// minutes since midnight instead would be an hour off on days that change
+// DST.
+func atClock(date time.Time, c clock) time.Time {
+	m := c.minutes()
+	return time.Date(date.Year(), date.Month(), date.Day(), m/60, m%60, 0, 0, date.Location())
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
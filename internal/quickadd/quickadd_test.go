@@ -0,0 +1,197 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 10, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		text    string
+		summary string
+		start   time.Time
+		end     time.Time
+		allDay  bool
+	}{
+		{
+			text:    "Focus 2pm-4pm tomorrow",
+			summary: "Focus",
+			start:   at(15, 14, 0),
+			end:     at(15, 16, 0),
+		},
+		{
+			text:    "Lunch with Sam fri 12:30 1h",
+			summary: "Lunch with Sam",
+			start:   at(16, 12, 30),
+			end:     at(16, 13, 30),
+		},
+		{
+			text:    "Standup at 9:30 for 15m",
+			summary: "Standup",
+			start:   at(15, 9, 30), // Already passed today
+			end:     at(15, 9, 45),
+		},
+		{
+			text:    "Call 3pm",
+			summary: "Call",
+			start:   at(14, 15, 0),
+			end:     at(14, 16, 0),
+		},
+		{
+			text:    "Review from 2 to 3:30 on 10/20",
+			summary: "Review",
+			start:   at(20, 14, 0),
+			end:     at(20, 15, 30),
+		},
+		{
+			text:    "Dinner 7-9pm",
+			summary: "Dinner",
+			start:   at(14, 19, 0),
+			end:     at(14, 21, 0),
+		},
+		{
+			text:    "Brunch 11-1pm sat",
+			summary: "Brunch",
+			start:   at(17, 11, 0),
+			end:     at(17, 13, 0),
+		},
+		{
+			text:    "Deploy 11pm-1am",
+			summary: "Deploy",
+			start:   at(14, 23, 0),
+			end:     at(15, 1, 0),
+		},
+		{
+			text:    "Dentist next wed at 8am",
+			summary: "Dentist",
+			start:   at(21, 8, 0),
+			end:     at(21, 9, 0),
+		},
+		{
+			text:    "Workshop 2026-10-29 noon for 1.5 hours",
+			summary: "Workshop",
+			start:   at(29, 12, 0),
+			end:     at(29, 13, 30),
+		},
+		{
+			text:    "Read 2 books 16:00 1h30m",
+			summary: "Read 2 books",
+			start:   at(14, 16, 0),
+			end:     at(14, 17, 30),
+		},
+		{
+			text:    "Offsite oct 22 all day",
+			summary: "Offsite",
+			start:   at(22, 0, 0),
+			end:     at(23, 0, 0),
+			allDay:  true,
+		},
+		{
+			text:    "Conference 3 nov",
+			summary: "Conference",
+			start:   time.Date(2026, 11, 3, 0, 0, 0, 0, time.UTC),
+			end:     time.Date(2026, 11, 4, 0, 0, 0, 0, time.UTC),
+			allDay:  true,
+		},
+		{
+			text:    "Retro jan 5 10am",
+			summary: "Retro",
+			start:   time.Date(2027, 1, 5, 10, 0, 0, 0, time.UTC),
+			end:     time.Date(2027, 1, 5, 11, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			event, err := Parse(tt.text, now, time.Hour)
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			if event.Summary != tt.summary {
+				t.Errorf("Summary = %q, want %q", event.Summary, tt.summary)
+			}
+			if !event.Start.Equal(tt.start) {
+				t.Errorf("Start = %v, want %v", event.Start, tt.start)
+			}
+			if !event.End.Equal(tt.end) {
+				t.Errorf("End = %v, want %v", event.End, tt.end)
+			}
+			if event.AllDay != tt.allDay {
+				t.Errorf("AllDay = %v, want %v", event.AllDay, tt.allDay)
+			}
+		})
+	}
+}
+
+func TestParseDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		text       string
+		start, end time.Time
+	}{
+		{
+			name:  "spring forward",
+			now:   time.Date(2026, 3, 7, 22, 0, 0, 0, loc),
+			text:  "Focus 2pm-4pm tomorrow",
+			start: time.Date(2026, 3, 8, 14, 0, 0, 0, loc),
+			end:   time.Date(2026, 3, 8, 16, 0, 0, 0, loc),
+		},
+		{
+			name:  "fall back",
+			now:   time.Date(2026, 10, 31, 22, 0, 0, 0, loc),
+			text:  "Focus 2pm tomorrow",
+			start: time.Date(2026, 11, 1, 14, 0, 0, 0, loc),
+			end:   time.Date(2026, 11, 1, 15, 0, 0, 0, loc),
+		},
+		{
+			name:  "ends after midnight into the change",
+			now:   time.Date(2026, 3, 7, 12, 0, 0, 0, loc),
+			text:  "Party 11pm-3am",
+			start: time.Date(2026, 3, 7, 23, 0, 0, 0, loc),
+			end:   time.Date(2026, 3, 8, 3, 0, 0, 0, loc),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := Parse(tt.text, tt.now, time.Hour)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if !event.Start.Equal(tt.start) {
+				t.Errorf("Start = %v, want %v", event.Start, tt.start)
+			}
+			if !event.End.Equal(tt.end) {
+				t.Errorf("End = %v, want %v", event.End, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+
+	for _, text := range []string{
+		"",
+		"Lunch with Sam",
+		"3pm tomorrow",
+		"Focus 2h",
+	} {
+		t.Run(text, func(t *testing.T) {
+			if event, err := Parse(text, now, time.Hour); err == nil {
+				t.Fatalf("expected error, got %+v", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Package secret provides a minimal client for the freedesktop.org Secret Service
+// API (org.freedesktop.secrets), as implemented by GNOME Keyring, KeePassXC and
+// KWallet.
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	// D-Bus service details for the Secret Service API
+	serviceName       = "org.freedesktop.secrets"
+	servicePath       = "/org/freedesktop/secrets"
+	serviceInterface  = "org.freedesktop.Secret.Service"
+	collectionIface   = "org.freedesktop.Secret.Collection"
+	itemInterface     = "org.freedesktop.Secret.Item"
+	promptInterface   = "org.freedesktop.Secret.Prompt"
+	defaultCollection = "default"
+
+	// Item property names used when creating items
+	itemLabelProperty      = itemInterface + ".Label"
+	itemAttributesProperty = itemInterface + ".Attributes"
+
+	// noPrompt is the object path returned when no prompt is necessary.
+	noPrompt = dbus.ObjectPath("/")
+
+	// promptTimeout bounds how long we wait for the user to answer an unlock prompt.
+	promptTimeout = 2 * time.Minute
+)
+
+var (
+	ErrNotFound     = errors.New("secret not found")
+	ErrNotAvailable = errors.New("secret service not available")
+	ErrDismissed    = errors.New("secret service prompt dismissed")
+)
+
+// Application is the attribute value identifying items created by calbar.
+const Application = "calbar"
+
+// SourceAttributes returns the lookup attributes calbar uses for a credential
+// of the named calendar source. field is typically "password".
+func SourceAttributes(source, field string) map[string]string {
+	return map[string]string{
+		"application": Application,
+		"source":      source,
+		"field":       field,
+	}
+}
+
+// secretValue mirrors the Secret struct from the Secret Service API: (oayays).
+type secretValue struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// Client is a connection to the Secret Service.
+type Client struct {
+	conn    *dbus.Conn
+	service dbus.BusObject
+	session dbus.ObjectPath
+}
+
+// Connect opens a private session bus connection and a plain-text transfer session
+// with the Secret Service. The session bus is local to the user, so secrets are not
+// encrypted in transit.
+func Connect(ctx context.Context) (*Client, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("%w: connect to session bus: %v", ErrNotAvailable, err)
+	}
+
+	c, err := newClient(ctx, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func newClient(ctx context.Context, conn *dbus.Conn) (*Client, error) {
+	c := &Client{
+		conn:    conn,
+		service: conn.Object(serviceName, servicePath),
+	}
+
+	var output dbus.Variant
+	if err := c.service.CallWithContext(ctx, serviceInterface+".OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&output, &c.session); err != nil {
+		return nil, fmt.Errorf("%w: open session: %v", ErrNotAvailable, err)
+	}
+	return c, nil
+}
+
+// Close closes the transfer session and the bus connection.
+func (c *Client) Close() error {
+	if c.session != "" {
+		c.conn.Object(serviceName, c.session).Call("org.freedesktop.Secret.Session.Close", 0)
+	}
+	return c.conn.Close()
+}
+
+// Lookup returns the secret of the first item matching attrs, unlocking it if needed.
+// Returns ErrNotFound if no item matches.
+func (c *Client) Lookup(ctx context.Context, attrs map[string]string) ([]byte, error) {
+	var unlocked, locked []dbus.ObjectPath
+	if err := c.service.CallWithContext(ctx, serviceInterface+".SearchItems", 0, attrs).Store(&unlocked, &locked); err != nil {
+		return nil, fmt.Errorf("search items: %w", err)
+	}
+
+	if len(unlocked) == 0 && len(locked) > 0 {
+		var err error
+		unlocked, err = c.unlock(ctx, locked[:1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(unlocked) == 0 {
+		return nil, ErrNotFound
+	}
+
+	var secret secretValue
+	item := c.conn.Object(serviceName, unlocked[0])
+	if err := item.CallWithContext(ctx, itemInterface+".GetSecret", 0, c.session).Store(&secret); err != nil {
+		return nil, fmt.Errorf("get secret: %w", err)
+	}
+	return secret.Value, nil
+}
+
+// Store creates or replaces an item in the default collection.
+func (c *Client) Store(ctx context.Context, label string, attrs map[string]string, value []byte) error {
+	var collection dbus.ObjectPath
+	if err := c.service.CallWithContext(ctx, serviceInterface+".ReadAlias", 0, defaultCollection).Store(&collection); err != nil {
+		return fmt.Errorf("read default collection: %w", err)
+	}
+	if collection == noPrompt {
+		return fmt.Errorf("no default collection configured in the secret service")
+	}
+
+	if _, err := c.unlock(ctx, []dbus.ObjectPath{collection}); err != nil {
+		return err
+	}
+
+	props := map[string]dbus.Variant{
+		itemLabelProperty:      dbus.MakeVariant(label),
+		itemAttributesProperty: dbus.MakeVariant(attrs),
+	}
+	secret := secretValue{
+		Session:     c.session,
+		Value:       value,
+		ContentType: "text/plain",
+	}
+
+	var item, prompt dbus.ObjectPath
+	obj := c.conn.Object(serviceName, collection)
+	if err := obj.CallWithContext(ctx, collectionIface+".CreateItem", 0, props, secret, true).Store(&item, &prompt); err != nil {
+		return fmt.Errorf("create item: %w", err)
+	}
+	if prompt != noPrompt {
+		if _, err := c.prompt(ctx, prompt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unlock unlocks the given objects, prompting the user if the service requires it.
+func (c *Client) unlock(ctx context.Context, objects []dbus.ObjectPath) ([]dbus.ObjectPath, error) {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	if err := c.service.CallWithContext(ctx, serviceInterface+".Unlock", 0, objects).Store(&unlocked, &prompt); err != nil {
+		return nil, fmt.Errorf("unlock: %w", err)
+	}
+	if prompt == noPrompt {
+		return unlocked, nil
+	}
+
+	result, err := c.prompt(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	paths, ok := result.Value().([]dbus.ObjectPath)
+	if !ok {
+		return nil, fmt.Errorf("unlock: unexpected prompt result %s", result.Signature())
+	}
+	return paths, nil
+}
+
+// prompt shows a Secret Service prompt and waits for it to complete.
+func (c *Client) prompt(ctx context.Context, path dbus.ObjectPath) (dbus.Variant, error) {
+	ctx, cancel := context.WithTimeout(ctx, promptTimeout)
+	defer cancel()
+
+	matchOpts := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface(promptInterface),
+		dbus.WithMatchMember("Completed"),
+	}
+	if err := c.conn.AddMatchSignalContext(ctx, matchOpts...); err != nil {
+		return dbus.Variant{}, fmt.Errorf("watch prompt: %w", err)
+	}
+	defer c.conn.RemoveMatchSignal(matchOpts...)
+
+	signals := make(chan *dbus.Signal, 1)
+	c.conn.Signal(signals)
+	defer c.conn.RemoveSignal(signals)
+
+	if err := c.conn.Object(serviceName, path).CallWithContext(ctx, promptInterface+".Prompt", 0, "").Err; err != nil {
+		return dbus.Variant{}, fmt.Errorf("show prompt: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return dbus.Variant{}, fmt.Errorf("wait for prompt: %w", ctx.Err())
+		case sig := <-signals:
+			if sig.Path != path || sig.Name != promptInterface+".Completed" || len(sig.Body) != 2 {
+				continue
+			}
+			if dismissed, _ := sig.Body[0].(bool); dismissed {
+				return dbus.Variant{}, ErrDismissed
+			}
+			result, _ := sig.Body[1].(dbus.Variant)
+			return result, nil
+		}
+	}
+}
+
+// Get connects to the Secret Service and returns the secret matching attrs as a string.
+func Get(ctx context.Context, attrs map[string]string) (string, error) {
+	c, err := Connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	v, err := c.Lookup(ctx, attrs)
+	if err != nil {
+		return "", err
+	}
+	return string(v), nil
+}
+
+// Set connects to the Secret Service and stores value under attrs, replacing any
+// existing item with the same attributes.
+func Set(ctx context.Context, label string, attrs map[string]string, value string) error {
+	c, err := Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return c.Store(ctx, label, attrs, []byte(value))
+}
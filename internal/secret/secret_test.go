@@ -0,0 +1,297 @@
+package secret
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// startBus starts a private dbus-daemon and points the session bus address at it.
+func startBus(t *testing.T) string {
+	t.Helper()
+
+	exe, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not available")
+	}
+
+	cmd := exec.Command(exe, "--session", "--nofork", "--nopidfile", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("stdout pipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("read bus address: %v", err)
+	}
+	addr = strings.TrimSpace(addr)
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", addr)
+	return addr
+}
+
+// fakeItem is an item stored by fakeService.
+type fakeItem struct {
+	label  string
+	attrs  map[string]string
+	value  []byte
+	locked bool
+}
+
+// fakeService is a minimal in-memory stand-in for the Secret Service.
+type fakeService struct {
+	conn *dbus.Conn
+
+	mu      sync.Mutex
+	items   map[dbus.ObjectPath]*fakeItem
+	nextID  int
+	prompts int
+}
+
+const fakeCollection = dbus.ObjectPath("/org/freedesktop/secrets/collection/login")
+
+func newFakeService(t *testing.T, addr string) *fakeService {
+	t.Helper()
+
+	conn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatalf("connect to bus: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	f := &fakeService{conn: conn, items: make(map[dbus.ObjectPath]*fakeItem)}
+	if err := conn.Export(f, servicePath, serviceInterface); err != nil {
+		t.Fatalf("export service: %v", err)
+	}
+	if err := conn.Export(fakeCollectionObject{f}, fakeCollection, collectionIface); err != nil {
+		t.Fatalf("export collection: %v", err)
+	}
+	reply, err := conn.RequestName(serviceName, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("request name: %v (reply %d)", err, reply)
+	}
+	return f
+}
+
+func (f *fakeService) add(item *fakeItem) dbus.ObjectPath {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.nextID++
+	path := dbus.ObjectPath(fmt.Sprintf("%s/%d", fakeCollection, f.nextID))
+	f.items[path] = item
+	f.conn.Export(fakeItemObject{f, path}, path, itemInterface)
+	return path
+}
+
+func (f *fakeService) OpenSession(algorithm string, input dbus.Variant) (dbus.Variant, dbus.ObjectPath, *dbus.Error) {
+	if algorithm != "plain" {
+		return dbus.Variant{}, "", dbus.MakeFailedError(errors.New("unsupported algorithm"))
+	}
+	return dbus.MakeVariant(""), "/org/freedesktop/secrets/session/1", nil
+}
+
+func (f *fakeService) SearchItems(attrs map[string]string) ([]dbus.ObjectPath, []dbus.ObjectPath, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var unlocked, locked []dbus.ObjectPath
+	for path, item := range f.items {
+		match := true
+		for k, v := range attrs {
+			if item.attrs[k] != v {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if item.locked {
+			locked = append(locked, path)
+		} else {
+			unlocked = append(unlocked, path)
+		}
+	}
+	return unlocked, locked, nil
+}
+
+func (f *fakeService) Unlock(objects []dbus.ObjectPath) ([]dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var needPrompt bool
+	for _, path := range objects {
+		if item, ok := f.items[path]; ok && item.locked {
+			needPrompt = true
+		}
+	}
+	if !needPrompt {
+		return objects, noPrompt, nil
+	}
+
+	f.prompts++
+	prompt := dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/secrets/prompt/%d", f.prompts))
+	f.conn.Export(fakePrompt{f, prompt, objects}, prompt, promptInterface)
+	return nil, prompt, nil
+}
+
+func (f *fakeService) ReadAlias(name string) (dbus.ObjectPath, *dbus.Error) {
+	if name != defaultCollection {
+		return noPrompt, nil
+	}
+	return fakeCollection, nil
+}
+
+type fakePrompt struct {
+	f       *fakeService
+	path    dbus.ObjectPath
+	objects []dbus.ObjectPath
+}
+
+func (p fakePrompt) Prompt(windowID string) *dbus.Error {
+	p.f.mu.Lock()
+	for _, path := range p.objects {
+		if item, ok := p.f.items[path]; ok {
+			item.locked = false
+		}
+	}
+	p.f.mu.Unlock()
+
+	go p.f.conn.Emit(p.path, promptInterface+".Completed", false, dbus.MakeVariant(p.objects))
+	return nil
+}
+
+type fakeCollectionObject struct {
+	f *fakeService
+}
+
+func (c fakeCollectionObject) CreateItem(props map[string]dbus.Variant, secret secretValue, replace bool) (dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	label, _ := props[itemLabelProperty].Value().(string)
+	attrs, _ := props[itemAttributesProperty].Value().(map[string]string)
+
+	if replace {
+		c.f.mu.Lock()
+		for path, item := range c.f.items {
+			if maps.Equal(item.attrs, attrs) {
+				item.label = label
+				item.value = secret.Value
+				c.f.mu.Unlock()
+				return path, noPrompt, nil
+			}
+		}
+		c.f.mu.Unlock()
+	}
+
+	path := c.f.add(&fakeItem{label: label, attrs: attrs, value: secret.Value})
+	return path, noPrompt, nil
+}
+
+type fakeItemObject struct {
+	f    *fakeService
+	path dbus.ObjectPath
+}
+
+func (i fakeItemObject) GetSecret(session dbus.ObjectPath) (secretValue, *dbus.Error) {
+	i.f.mu.Lock()
+	defer i.f.mu.Unlock()
+
+	item, ok := i.f.items[i.path]
+	if !ok {
+		return secretValue{}, dbus.MakeFailedError(errors.New("no such item"))
+	}
+	if item.locked {
+		return secretValue{}, dbus.MakeFailedError(errors.New("item is locked"))
+	}
+	return secretValue{Session: session, Value: item.value, ContentType: "text/plain"}, nil
+}
+
+func TestSetAndGet(t *testing.T) {
+	addr := startBus(t)
+	newFakeService(t, addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	attrs := SourceAttributes("Work", "password")
+
+	if _, err := Get(ctx, attrs); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() before Set error = %v, want ErrNotFound", err)
+	}
+
+	if err := Set(ctx, "calbar: Work", attrs, "hunter2"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	got, err := Get(ctx, attrs)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != "hunter2" {
+		t.Fatalf("Get() = %q, want %q", got, "hunter2")
+	}
+
+	// Replacing keeps a single item with the new value.
+	if err := Set(ctx, "calbar: Work", attrs, "correct horse"); err != nil {
+		t.Fatalf("Set() replace error = %v", err)
+	}
+	got, err = Get(ctx, attrs)
+	if err != nil {
+		t.Fatalf("Get() after replace error = %v", err)
+	}
+	if got != "correct horse" {
+		t.Fatalf("Get() after replace = %q, want %q", got, "correct horse")
+	}
+
+	if _, err := Get(ctx, SourceAttributes("Personal", "password")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() for other source error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetLockedItem(t *testing.T) {
+	addr := startBus(t)
+	f := newFakeService(t, addr)
+
+	attrs := map[string]string{"service": "caldav", "user": "me"}
+	f.add(&fakeItem{label: "CalDAV", attrs: attrs, value: []byte("s3cret"), locked: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	got, err := Get(ctx, attrs)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Fatalf("Get() = %q, want %q", got, "s3cret")
+	}
+	if f.prompts != 1 {
+		t.Fatalf("prompts = %d, want 1", f.prompts)
+	}
+}
+
+func TestNotAvailable(t *testing.T) {
+	startBus(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := Get(ctx, SourceAttributes("Work", "password")); !errors.Is(err, ErrNotAvailable) {
+		t.Fatalf("Get() error = %v, want ErrNotAvailable", err)
+	}
+}
@@ -0,0 +1,271 @@
+// Package server serves the merged calendar over HTTP as ICS and JSON.
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/filter"
+)
+
+// Options configures what a Server serves.
+type Options struct {
+	// Events returns the events to serve. The server does not modify the
+	// returned slice, but it must not be modified by the caller afterwards.
+	Events func() []calendar.Event
+
+	// EncodeJSON encodes the events served as events.json.
+	EncodeJSON func([]calendar.Event) ([]byte, error)
+}
+
+// Server serves /calendar.ics and /events.json, and the same under
+// /<profile>/ for each configured filter profile.
+type Server struct {
+	listen   string
+	token    string
+	opts     Options
+	profiles map[string]*filter.Filter
+
+	srv    *http.Server
+	socket string // Unix socket path to remove on Close
+
+	mu    sync.Mutex
+	cache map[string]response // Last response by request path
+}
+
+// response is an encoded response body and the checksum of the events it
+// was encoded from.
+type response struct {
+	sum  string
+	body []byte
+}
+
+// New creates a server from its config, compiling the filter profiles and
+// resolving the token.
+func New(cfg config.ServerConfig, opts Options) (*Server, error) {
+	if cfg.Listen == "" {
+		return nil, fmt.Errorf("no listen address")
+	}
+	token, err := cfg.GetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		listen:   cfg.Listen,
+		token:    token,
+		opts:     opts,
+		profiles: make(map[string]*filter.Filter, len(cfg.Profiles)),
+		cache:    make(map[string]response),
+	}
+	for name, fc := range cfg.Profiles {
+		if name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid profile name %q", name)
+		}
+		f, err := filter.New(fc)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		s.profiles[name] = f
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /calendar.ics", s.handleICS)
+	mux.HandleFunc("GET /events.json", s.handleJSON)
+	mux.HandleFunc("GET /{profile}/calendar.ics", s.handleICS)
+	mux.HandleFunc("GET /{profile}/events.json", s.handleJSON)
+	s.srv = &http.Server{
+		Handler:           s.authorize(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s, nil
+}
+
+// Handler returns the server's HTTP handler.
+func (s *Server) Handler() http.Handler {
+	return s.srv.Handler
+}
+
+// Start listens on the configured address and serves in the background.
+// Addresses of the form "unix:/path" listen on a Unix socket that only the
+// current user can connect to.
+func (s *Server) Start() error {
+	var ln net.Listener
+	var err error
+	if path, ok := strings.CutPrefix(s.listen, "unix:"); ok {
+		// Remove a socket left behind by a previous run
+		if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			os.Remove(path)
+		}
+		// Create the socket with no group or other permissions, so nobody
+		// can connect before the chmod below
+		mask := syscall.Umask(0o077)
+		ln, err = net.Listen("unix", path)
+		syscall.Umask(mask)
+		if err != nil {
+			return fmt.Errorf("listen: %w", err)
+		}
+		if err := os.Chmod(path, 0o600); err != nil {
+			ln.Close()
+			return fmt.Errorf("set socket permissions: %w", err)
+		}
+		s.socket = path
+	} else {
+		ln, err = net.Listen("tcp", s.listen)
+		if err != nil {
+			return fmt.Errorf("listen: %w", err)
+		}
+		if s.token == "" && !isLoopback(s.listen) {
+			slog.Warn("HTTP server is reachable from other hosts without a token", "listen", s.listen)
+		}
+	}
+
+	slog.Info("HTTP server listening", "addr", ln.Addr().String())
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP server failed", "error", err)
+		}
+	}()
+	return nil
+}
+
+// Close stops the server and removes its Unix socket, if any.
+func (s *Server) Close() error {
+	err := s.srv.Close()
+	if s.socket != "" {
+		os.Remove(s.socket)
+	}
+	return err
+}
+
+// isLoopback reports whether a TCP listen address only accepts local
+// connections.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// authorize rejects requests without the configured token, given either as
+// a bearer token or as the "token" query parameter for calendar clients
+// that cannot set headers.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if v, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			token = v
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="calbar"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleICS(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, "text/calendar; charset=utf-8", func(events []calendar.Event) ([]byte, error) {
+		var buf bytes.Buffer
+		if err := calendar.EncodeICS(&buf, events, time.Now()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	})
+}
+
+func (s *Server) handleJSON(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, "application/json", s.opts.EncodeJSON)
+}
+
+// serve writes the events for the request's profile using encode. The
+// encoded body is reused until the events change, so the ETag and the
+// body (including its DTSTAMPs) stay the same between syncs.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, contentType string, encode func([]calendar.Event) ([]byte, error)) {
+	events := s.opts.Events()
+	if name := r.PathValue("profile"); name != "" {
+		f, ok := s.profiles[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		events = f.Apply(events)
+	}
+
+	sum, err := checksum(events)
+	if err != nil {
+		slog.Error("HTTP server: hash events", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	resp, ok := s.cache[r.URL.Path]
+	if !ok || resp.sum != sum {
+		body, err := encode(events)
+		if err != nil {
+			s.mu.Unlock()
+			slog.Error("HTTP server: encode events", "path", r.URL.Path, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		resp = response{sum: sum, body: body}
+		s.cache[r.URL.Path] = resp
+	}
+	s.mu.Unlock()
+
+	etag := `"` + resp.sum + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(resp.body)
+}
+
+// checksum returns a short hash identifying a set of events.
+func checksum(events []calendar.Event) (string, error) {
+	data, err := json.Marshal(events)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// matchETag reports whether an If-None-Match header matches etag.
+func matchETag(header, etag string) bool {
+	for v := range strings.SplitSeq(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+)
+
+func newTestServer(t *testing.T, cfg config.ServerConfig, events *[]calendar.Event) http.Handler {
+	t.Helper()
+	cfg.Listen = "127.0.0.1:0"
+	s, err := New(cfg, Options{
+		Events:     func() []calendar.Event { return *events },
+		EncodeJSON: func(events []calendar.Event) ([]byte, error) { return json.Marshal(events) },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s.Handler()
+}
+
+func get(h http.Handler, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	events := []calendar.Event{
+		{UID: "a", Summary: "Standup", Start: start, End: start.Add(15 * time.Minute), Source: "work"},
+		{UID: "b", Summary: "Dentist", Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour), Source: "personal"},
+	}
+	h := newTestServer(t, config.ServerConfig{
+		Profiles: map[string]config.FilterConfig{
+			"work": {Rules: []config.FilterRule{{Expr: `source == "work"`}}},
+		},
+	}, &events)
+
+	tests := []struct {
+		target      string
+		status      int
+		contentType string
+		contains    []string
+		excludes    []string
+	}{
+		{"/calendar.ics", http.StatusOK, "text/calendar; charset=utf-8", []string{"BEGIN:VCALENDAR", "SUMMARY:Standup", "SUMMARY:Dentist"}, nil},
+		{"/events.json", http.StatusOK, "application/json", []string{`"Standup"`, `"Dentist"`}, nil},
+		{"/work/calendar.ics", http.StatusOK, "text/calendar; charset=utf-8", []string{"SUMMARY:Standup"}, []string{"Dentist"}},
+		{"/work/events.json", http.StatusOK, "application/json", []string{`"Standup"`}, []string{"Dentist"}},
+		{"/home/events.json", http.StatusNotFound, "", nil, nil},
+		{"/calendar.json", http.StatusNotFound, "", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := get(h, tt.target, nil)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.contentType != "" && rec.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), tt.contentType)
+			}
+			body := rec.Body.String()
+			for _, s := range tt.contains {
+				if !strings.Contains(body, s) {
+					t.Errorf("body does not contain %q:\n%s", s, body)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(body, s) {
+					t.Errorf("body contains %q:\n%s", s, body)
+				}
+			}
+		})
+	}
+}
+
+func TestServerETag(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	events := []calendar.Event{{UID: "a", Summary: "Standup", Start: start, End: start.Add(15 * time.Minute)}}
+	h := newTestServer(t, config.ServerConfig{}, &events)
+
+	first := get(h, "/calendar.ics", nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+
+	time.Sleep(time.Second) // DTSTAMP has second precision
+	second := get(h, "/calendar.ics", nil)
+	if second.Header().Get("ETag") != etag || second.Body.String() != first.Body.String() {
+		t.Errorf("response changed without the events changing")
+	}
+
+	if rec := get(h, "/calendar.ics", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+
+	events = append(events, calendar.Event{UID: "b", Summary: "Lunch", Start: start.Add(3 * time.Hour), End: start.Add(4 * time.Hour)})
+	rec := get(h, "/calendar.ics", map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("after change: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Errorf("ETag did not change with the events")
+	}
+	if !strings.Contains(rec.Body.String(), "SUMMARY:Lunch") {
+		t.Errorf("body does not contain the new event")
+	}
+}
+
+func TestServerToken(t *testing.T) {
+	var events []calendar.Event
+	h := newTestServer(t, config.ServerConfig{Token: "s3cret"}, &events)
+
+	tests := []struct {
+		name   string
+		target string
+		header map[string]string
+		status int
+	}{
+		{"no token", "/events.json", nil, http.StatusUnauthorized},
+		{"wrong token", "/events.json?token=nope", nil, http.StatusUnauthorized},
+		{"query token", "/events.json?token=s3cret", nil, http.StatusOK},
+		{"bearer token", "/events.json", map[string]string{"Authorization": "Bearer s3cret"}, http.StatusOK},
+		{"wrong bearer token", "/events.json?token=s3cret", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := get(h, tt.target, tt.header); rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestServerUnixSocket(t *testing.T) {
+	var events []calendar.Event
+	path := filepath.Join(t.TempDir(), "calbar.sock")
+	s, err := New(config.ServerConfig{Listen: "unix:" + path}, Options{
+		Events:     func() []calendar.Event { return events },
+		EncodeJSON: func(events []calendar.Event) ([]byte, error) { return json.Marshal(events) },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer s.Close()
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("socket permissions = %o, want 600", perm)
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://calbar/events.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	s.Close()
+	if _, err := os.Stat(path); err == nil {
+		t.Error("Close() should remove the socket")
+	}
+}
+
+func TestNewInvalidProfile(t *testing.T) {
+	_, err := New(config.ServerConfig{
+		Listen:   "127.0.0.1:0",
+		Profiles: map[string]config.FilterConfig{"bad": {Rules: []config.FilterRule{{Expr: "source =="}}}},
+	}, Options{})
+	if err == nil || !strings.Contains(err.Error(), `profile "bad"`) {
+		t.Errorf("New() error = %v, want profile error", err)
+	}
+}
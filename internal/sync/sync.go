@@ -5,25 +5,37 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cpuguy83/calbar/internal/calendar"
 	"github.com/cpuguy83/calbar/internal/config"
 	"github.com/cpuguy83/calbar/internal/filter"
+	"github.com/cpuguy83/calbar/internal/transform"
 )
 
-// sourceWithFilter pairs a calendar source with its optional filter.
+// sourceWithFilter pairs a calendar source with its optional filter,
+// transforms and color.
 type sourceWithFilter struct {
-	source calendar.Source
-	filter *filter.Filter
+	source     calendar.Source
+	filter     *filter.Filter
+	transforms *transform.Transformer
+	color      string // Configured accent color; overrides colors reported by the source
 }
 
 // Syncer handles calendar synchronization from multiple sources.
 type Syncer struct {
-	sources   []sourceWithFilter
-	interval  time.Duration
-	timeRange time.Duration
+	sources    []sourceWithFilter
+	filter     *filter.Filter         // Global filter, applied after per-source filters
+	transforms *transform.Transformer // Global transforms, applied before the global filter
+	interval   time.Duration
+	timeRange  time.Duration
+
+	// identities are the user's addresses, used to find their own response
+	// in sources that do not report it.
+	identities []string
 }
 
 // SourceFailure describes a source that failed during sync.
@@ -50,10 +62,23 @@ func NewSyncer(cfg *config.Config) (*Syncer, error) {
 		return nil, err
 	}
 
+	f, err := filter.New(cfg.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("filters: %w", err)
+	}
+
+	t, err := transform.New(cfg.Transforms)
+	if err != nil {
+		return nil, fmt.Errorf("transforms: %w", err)
+	}
+
 	return &Syncer{
-		sources:   sources,
-		interval:  cfg.Sync.Interval,
-		timeRange: cfg.Sync.TimeRange,
+		sources:    sources,
+		filter:     f,
+		transforms: t,
+		interval:   cfg.Sync.Interval,
+		timeRange:  cfg.Sync.TimeRange,
+		identities: cfg.Availability.Identities,
 	}, nil
 }
 
@@ -67,7 +92,8 @@ func (s *Syncer) SourceCount() int {
 	return len(s.sources)
 }
 
-// Sync fetches all sources, applies per-source filters, and returns merged events.
+// Sync fetches all sources, applies per-source transforms and filters, and
+// returns merged events.
 // Also returns any sources that failed to sync.
 func (s *Syncer) Sync(ctx context.Context) ([]calendar.Event, []SourceFailure, error) {
 	slog.Info("starting sync", "sources", len(s.sources))
@@ -75,7 +101,7 @@ func (s *Syncer) Sync(ctx context.Context) ([]calendar.Event, []SourceFailure, e
 	// Calculate end time from configured time range
 	endTime := time.Now().Add(s.timeRange)
 
-	// Fetch from all sources in parallel, applying per-source filters
+	// Fetch from all sources in parallel, applying per-source transforms and filters
 	type result struct {
 		events   []calendar.Event
 		name     string
@@ -90,9 +116,7 @@ func (s *Syncer) Sync(ctx context.Context) ([]calendar.Event, []SourceFailure, e
 	for _, swf := range s.sources {
 		wg.Go(func() {
 			name := swf.source.Name()
-			slog.Debug("fetching source", "name", name)
-
-			events, err := swf.source.Fetch(ctx, endTime)
+			events, err := s.fetch(ctx, swf, endTime)
 			if err != nil {
 				results <- result{name: name, err: err}
 				return
@@ -138,8 +162,8 @@ func (s *Syncer) Sync(ctx context.Context) ([]calendar.Event, []SourceFailure, e
 		allEvents = append(allEvents, r.events...)
 	}
 
-	// Merge and sort
-	merged := calendar.Merge(allEvents)
+	// Merge and sort, then apply global transforms and filters
+	merged := s.filter.Apply(s.transforms.Apply(calendar.Merge(allEvents)))
 
 	slog.Info("sync complete", "events", len(merged), "failed_sources", len(failures))
 
@@ -152,6 +176,141 @@ func (s *Syncer) Sync(ctx context.Context) ([]calendar.Event, []SourceFailure, e
 	return merged, failures, nil
 }
 
+// fetch fetches a source and prepares its events for filtering: it applies
+// the configured color, resolves the user's response and runs the per-source
+// transforms.
+func (s *Syncer) fetch(ctx context.Context, swf sourceWithFilter, endTime time.Time) ([]calendar.Event, error) {
+	slog.Debug("fetching source", "name", swf.source.Name())
+
+	events, err := swf.source.Fetch(ctx, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		if swf.color != "" {
+			events[i].Color = swf.color
+		}
+		events[i].ResolveResponse(s.identities)
+	}
+
+	// Transform before filtering so filters see the cleaned-up events
+	return swf.transforms.Apply(events), nil
+}
+
+// Explanation describes how the filters decided a fetched event.
+type Explanation struct {
+	Event  calendar.Event   // The event after transforms
+	Source filter.Decision  // Decision of the per-source filter
+	Global *filter.Decision // Decision of the global filter; nil if the per-source filter dropped the event
+}
+
+// Explain fetches all sources like Sync, but keeps every event and reports
+// how the per-source and global filters decided it. Events are sorted by
+// start time.
+func (s *Syncer) Explain(ctx context.Context) ([]Explanation, []SourceFailure, error) {
+	endTime := time.Now().Add(s.timeRange)
+
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		explanations []Explanation
+		failures     []SourceFailure
+	)
+	for _, swf := range s.sources {
+		wg.Go(func() {
+			events, err := s.fetch(ctx, swf, endTime)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, SourceFailure{Name: swf.source.Name(), Err: err})
+				return
+			}
+			for _, e := range events {
+				explanations = append(explanations, Explanation{Event: e, Source: swf.filter.Explain(e)})
+			}
+		})
+	}
+	wg.Wait()
+
+	for i := range explanations {
+		if !explanations[i].Source.Included {
+			continue
+		}
+		transformed := s.transforms.Apply([]calendar.Event{explanations[i].Event})
+		explanations[i].Event = transformed[0]
+		d := s.filter.Explain(transformed[0])
+		explanations[i].Global = &d
+	}
+
+	sort.SliceStable(explanations, func(i, j int) bool {
+		return explanations[i].Event.Start.Before(explanations[j].Event.Start)
+	})
+
+	if len(explanations) == 0 && len(failures) > 0 {
+		return nil, failures, failures[0].Err
+	}
+	return explanations, failures, nil
+}
+
+// Respond sets the user's participation status on an invitation through the
+// source the event came from.
+func (s *Syncer) Respond(ctx context.Context, event calendar.Event, status calendar.PartStat) error {
+	src := s.sourceFor(event)
+	if src == nil {
+		return fmt.Errorf("no source for event %q (%s)", event.Summary, event.Source)
+	}
+	responder, ok := src.(calendar.Responder)
+	if !ok || !event.CanRespond() {
+		return fmt.Errorf("source %q does not support responding to invitations", src.Name())
+	}
+	return responder.Respond(ctx, event, status)
+}
+
+// CreateEvent creates an event in the named source and calendar, and returns
+// the event as stored, with the source's configured color and the transforms
+// applied.
+func (s *Syncer) CreateEvent(ctx context.Context, sourceName, calendarName string, event calendar.Event) (calendar.Event, error) {
+	for _, swf := range s.sources {
+		if swf.source.Name() != sourceName {
+			continue
+		}
+		creator, ok := swf.source.(calendar.Creator)
+		if !ok {
+			return calendar.Event{}, fmt.Errorf("source %q does not support creating events", sourceName)
+		}
+		created, err := creator.Create(ctx, event, calendarName)
+		if err != nil {
+			return calendar.Event{}, err
+		}
+		if swf.color != "" {
+			created.Color = swf.color
+		}
+		transformed := []calendar.Event{created}
+		swf.transforms.Apply(transformed)
+		s.transforms.Apply(transformed)
+		return transformed[0], nil
+	}
+	return calendar.Event{}, fmt.Errorf("source %q not found", sourceName)
+}
+
+// sourceFor returns the source an event was fetched from. CalDAV events are
+// labeled "<source>/<calendar>", so a name prefix also matches.
+func (s *Syncer) sourceFor(event calendar.Event) calendar.Source {
+	for _, swf := range s.sources {
+		if event.Source == swf.source.Name() {
+			return swf.source
+		}
+	}
+	for _, swf := range s.sources {
+		if strings.HasPrefix(event.Source, swf.source.Name()+"/") {
+			return swf.source
+		}
+	}
+	return nil
+}
+
 // Run starts the sync loop, calling onSync after each sync completes.
 // The callback receives the synced events, failed sources, and any error.
 // Run blocks until the context is cancelled.
@@ -223,12 +382,19 @@ func createSources(cfgs []config.SourceConfig) ([]sourceWithFilter, error) {
 		// Create per-source filter (if no rules, filter passes everything through)
 		f, err := filter.New(resolved.Filters)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("source %q filters: %w", resolved.Name, err)
+		}
+
+		t, err := transform.New(resolved.Transforms)
+		if err != nil {
+			return nil, fmt.Errorf("source %q transforms: %w", resolved.Name, err)
 		}
 
 		sources = append(sources, sourceWithFilter{
-			source: src,
-			filter: f,
+			source:     src,
+			filter:     f,
+			transforms: t,
+			color:      calendar.NormalizeColor(resolved.Color),
 		})
 	}
 
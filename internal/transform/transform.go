@@ -0,0 +1,182 @@
+// Package transform rewrites calendar events: it cleans up titles and
+// locations, sets reminders, colors and tags, and overrides meeting links.
+package transform
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+	"github.com/cpuguy83/calbar/internal/filter"
+)
+
+// Transformer applies a list of transforms to events.
+type Transformer struct {
+	transforms []transform
+}
+
+type transform struct {
+	match          *filter.Matcher // nil matches every event
+	summary        *replacement
+	location       *replacement
+	notifyBefore   []time.Duration
+	notifyOverride bool
+	color          string
+	tags           []string
+	meetingURL     string
+}
+
+type replacement struct {
+	regex   *regexp.Regexp
+	replace string
+}
+
+// New creates a Transformer from configuration.
+func New(cfgs []config.TransformConfig) (*Transformer, error) {
+	t := &Transformer{}
+	for i, cfg := range cfgs {
+		compiled, err := compile(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("transform %d: %w", i, err)
+		}
+		t.transforms = append(t.transforms, compiled)
+	}
+	return t, nil
+}
+
+// compile validates a config TransformConfig and converts it to a transform.
+func compile(cfg config.TransformConfig) (transform, error) {
+	var tr transform
+
+	if cfg.Match != nil {
+		m, err := filter.Compile(*cfg.Match)
+		if err != nil {
+			return tr, fmt.Errorf("match: %w", err)
+		}
+		tr.match = m
+	}
+
+	var err error
+	if tr.summary, err = compileReplacement(cfg.Summary); err != nil {
+		return tr, fmt.Errorf("summary: %w", err)
+	}
+	if tr.location, err = compileReplacement(cfg.Location); err != nil {
+		return tr, fmt.Errorf("location: %w", err)
+	}
+
+	for _, before := range cfg.NotifyBefore {
+		if before < 0 {
+			return tr, fmt.Errorf("notify_before: negative offset %s", before)
+		}
+	}
+	tr.notifyBefore = cfg.NotifyBefore
+	tr.notifyOverride = cfg.NotifyOverride
+
+	if cfg.Color != "" {
+		tr.color = calendar.NormalizeColor(cfg.Color)
+		if tr.color == "" {
+			return tr, fmt.Errorf("invalid color %q", cfg.Color)
+		}
+	}
+
+	for _, tag := range cfg.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return tr, fmt.Errorf("empty tag")
+		}
+		tr.tags = append(tr.tags, tag)
+	}
+
+	if cfg.MeetingURL != "" {
+		u, err := url.Parse(cfg.MeetingURL)
+		if err != nil || !u.IsAbs() {
+			return tr, fmt.Errorf("invalid meeting_url %q", cfg.MeetingURL)
+		}
+		tr.meetingURL = cfg.MeetingURL
+	}
+
+	if tr.summary == nil && tr.location == nil && len(tr.notifyBefore) == 0 && !tr.notifyOverride &&
+		tr.color == "" && len(tr.tags) == 0 && tr.meetingURL == "" {
+		return tr, fmt.Errorf("transform needs at least one of summary, location, notify_before, color, tags, or meeting_url")
+	}
+
+	return tr, nil
+}
+
+// compileReplacement compiles an optional regex replacement.
+func compileReplacement(r *config.Replacement) (*replacement, error) {
+	if r == nil {
+		return nil, nil
+	}
+	if r.Regex == "" {
+		return nil, fmt.Errorf("regex is required")
+	}
+	re, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", r.Regex, err)
+	}
+	return &replacement{regex: re, replace: r.Replace}, nil
+}
+
+// Apply transforms events in place and returns them. Transforms run in order,
+// so each one sees the changes made by the ones before it.
+func (t *Transformer) Apply(events []calendar.Event) []calendar.Event {
+	if len(t.transforms) == 0 {
+		return events
+	}
+	for i := range events {
+		for _, tr := range t.transforms {
+			if tr.match == nil || tr.match.Matches(events[i]) {
+				tr.apply(&events[i])
+			}
+		}
+	}
+	return events
+}
+
+// apply makes the transform's changes to a matching event.
+func (tr *transform) apply(e *calendar.Event) {
+	if tr.summary != nil {
+		e.Summary = tr.summary.apply(e.Summary)
+	}
+	if tr.location != nil {
+		e.Location = tr.location.apply(e.Location)
+	}
+
+	// Without notify_override, only fill in reminders for events that have
+	// none of their own
+	if tr.notifyOverride || (len(tr.notifyBefore) > 0 && len(e.NotifyAt) == 0) {
+		notifyAt := make([]time.Time, 0, len(tr.notifyBefore))
+		for _, before := range tr.notifyBefore {
+			notifyAt = append(notifyAt, e.Start.Add(-before))
+		}
+		e.NotifyAt = notifyAt
+		e.NotifySet = true
+	}
+
+	if tr.color != "" {
+		e.Color = tr.color
+	}
+
+	for _, tag := range tr.tags {
+		if !slices.Contains(e.Tags, tag) {
+			// Clip so events sharing a backing array are not changed too
+			e.Tags = append(slices.Clip(e.Tags), tag)
+		}
+	}
+
+	if tr.meetingURL != "" {
+		// Details such as dial-in numbers belong to the replaced meeting
+		e.Meeting = calendar.MeetingDetails{URL: tr.meetingURL}
+	}
+}
+
+// apply replaces all regex matches in s and trims the leftover whitespace.
+func (r *replacement) apply(s string) string {
+	return strings.TrimSpace(r.regex.ReplaceAllString(s, r.replace))
+}
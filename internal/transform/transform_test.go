@@ -0,0 +1,119 @@
+package transform
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+	"github.com/cpuguy83/calbar/internal/config"
+)
+
+func TestApply(t *testing.T) {
+	start := time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)
+	ownReminder := start.Add(-30 * time.Minute)
+	events := []calendar.Event{
+		{
+			UID:      "fw",
+			Summary:  "FW: [EXTERNAL] Sync",
+			Location: "Microsoft Teams Meeting; Room 4",
+			Source:   "Work",
+			Start:    start,
+			NotifyAt: []time.Time{ownReminder},
+			Meeting:  calendar.MeetingDetails{URL: "https://teams.microsoft.com/l/meetup-join/old", DialIn: "+1 555 0100"},
+		},
+		{UID: "home", Summary: "RE: Dinner", Source: "Home", Start: start},
+	}
+
+	tr, err := New([]config.TransformConfig{
+		{
+			Summary: &config.Replacement{Regex: `^((FW|RE):\s*)+|\[EXTERNAL\]`, Replace: ""},
+		},
+		{
+			Match:      &config.FilterRule{Field: "source", Exact: "Work"},
+			Location:   &config.Replacement{Regex: `Microsoft Teams Meeting;?`, Replace: ""},
+			Color:      "#f00",
+			Tags:       []string{"work", "external"},
+			MeetingURL: "https://acme.zoom.us/j/123",
+		},
+		{
+			// Matches the rewritten title
+			Match:        &config.FilterRule{Expr: `title == Sync`},
+			NotifyBefore: []time.Duration{10 * time.Minute},
+			Tags:         []string{"work"},
+		},
+		{
+			Match:          &config.FilterRule{Field: "source", Exact: "Home"},
+			NotifyBefore:   []time.Duration{time.Hour, 5 * time.Minute},
+			NotifyOverride: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	got := tr.Apply(events)
+
+	work := got[0]
+	if work.Summary != "Sync" {
+		t.Errorf("Summary = %q, want %q", work.Summary, "Sync")
+	}
+	if work.Location != "Room 4" {
+		t.Errorf("Location = %q, want %q", work.Location, "Room 4")
+	}
+	if work.Color != "#ff0000" {
+		t.Errorf("Color = %q, want #ff0000", work.Color)
+	}
+	if !slices.Equal(work.Tags, []string{"work", "external"}) {
+		t.Errorf("Tags = %q, want [work external]", work.Tags)
+	}
+	if work.Meeting != (calendar.MeetingDetails{URL: "https://acme.zoom.us/j/123"}) {
+		t.Errorf("Meeting = %+v, want only the new URL", work.Meeting)
+	}
+	// notify_before without override keeps the event's own reminders
+	if len(work.NotifyAt) != 1 || !work.NotifyAt[0].Equal(ownReminder) || work.NotifySet {
+		t.Errorf("NotifyAt = %v (set %v), want own reminder kept", work.NotifyAt, work.NotifySet)
+	}
+
+	home := got[1]
+	if home.Summary != "Dinner" || home.Color != "" || len(home.Tags) != 0 {
+		t.Errorf("home event = %+v, want only the title rewritten", home)
+	}
+	want := []time.Time{start.Add(-time.Hour), start.Add(-5 * time.Minute)}
+	if !slices.EqualFunc(home.NotifyAt, want, time.Time.Equal) || !home.NotifySet {
+		t.Errorf("NotifyAt = %v (set %v), want %v", home.NotifyAt, home.NotifySet, want)
+	}
+}
+
+func TestApply_FillsMissingReminders(t *testing.T) {
+	start := time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)
+	tr, err := New([]config.TransformConfig{{NotifyBefore: []time.Duration{15 * time.Minute}}})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	got := tr.Apply([]calendar.Event{{UID: "a", Start: start}})
+	if len(got[0].NotifyAt) != 1 || !got[0].NotifyAt[0].Equal(start.Add(-15*time.Minute)) || !got[0].NotifySet {
+		t.Fatalf("NotifyAt = %v (set %v), want one reminder 15m before", got[0].NotifyAt, got[0].NotifySet)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for name, cfg := range map[string]config.TransformConfig{
+		"no action":         {Match: &config.FilterRule{Field: "title", Contains: "a"}},
+		"bad match":         {Match: &config.FilterRule{Field: "colour", Contains: "a"}, Tags: []string{"a"}},
+		"exclude in match":  {Match: &config.FilterRule{Field: "title", Contains: "a", Exclude: true}, Tags: []string{"a"}},
+		"bad regex":         {Summary: &config.Replacement{Regex: "("}},
+		"missing regex":     {Location: &config.Replacement{Replace: "x"}},
+		"bad color":         {Color: "red"},
+		"empty tag":         {Tags: []string{" "}},
+		"relative url":      {MeetingURL: "zoom.us/j/123"},
+		"negative reminder": {NotifyBefore: []time.Duration{-time.Minute}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New([]config.TransformConfig{cfg}); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
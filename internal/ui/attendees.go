@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/cpuguy83/calbar/internal/calendar"
+)
+
+// attendeeMarks are the status markers shown before attendee names.
+var attendeeMarks = map[calendar.PartStat]string{
+	calendar.PartStatAccepted:    "✓",
+	calendar.PartStatTentative:   "?",
+	calendar.PartStatDeclined:    "✗",
+	calendar.PartStatNeedsAction: "…",
+}
+
+// attendeeRoleLabels are shown after attendees whose role is not required.
+var attendeeRoleLabels = map[string]string{
+	calendar.RoleChair:          "chair",
+	calendar.RoleOptional:       "optional",
+	calendar.RoleNonParticipant: "resource",
+}
+
+// FormatAttendee returns a one-line description of an attendee with a status
+// marker, e.g. "✓ Jane Doe <jane@example.com> (optional)".
+func FormatAttendee(a calendar.Attendee) string {
+	mark := attendeeMarks[a.Status]
+	if mark == "" {
+		mark = "·"
+	}
+
+	text := a.DisplayName()
+	if a.Name != "" && a.Email != "" {
+		text = fmt.Sprintf("%s <%s>", a.Name, a.Email)
+	}
+	if role := attendeeRoleLabels[a.Role]; role != "" {
+		text += " (" + role + ")"
+	}
+	return mark + " " + text
+}
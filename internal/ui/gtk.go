@@ -16,7 +16,7 @@ type GTK struct {
 // NewGTK creates a new GTK UI backend.
 func NewGTK(cfg Config) *GTK {
 	return &GTK{
-		popup: NewPopup(cfg.TimeRange, cfg.EventEndGrace, cfg.HoverDismissDelay, cfg.NotificationBefore, cfg.CSSFile),
+		popup: NewPopup(cfg.TimeRange, cfg.EventEndGrace, cfg.HoverDismissDelay, cfg.NotificationBefore, cfg.CSSFile, cfg.DimTentative),
 	}
 }
 
@@ -45,6 +45,16 @@ func (g *GTK) Init() error {
 			g.onAction(Action{Type: ActionSync})
 		}
 	})
+	g.popup.OnRespond(func(uid string, status calendar.PartStat) {
+		if g.onAction != nil {
+			g.onAction(Action{Type: ActionRespond, UID: uid, Response: status})
+		}
+	})
+	g.popup.OnQuickAdd(func(text string) {
+		if g.onAction != nil {
+			g.onAction(Action{Type: ActionQuickAdd, Text: text})
+		}
+	})
 	return nil
 }
 
@@ -68,6 +78,11 @@ func (g *GTK) Search() {
 	g.popup.Search()
 }
 
+// ShowDetails displays the popup with an event's details.
+func (g *GTK) ShowDetails(uid string) {
+	g.popup.ShowDetails(uid)
+}
+
 // SetEvents updates the event list.
 func (g *GTK) SetEvents(events []calendar.Event) {
 	g.popup.SetEvents(events)
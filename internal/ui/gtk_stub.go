@@ -36,6 +36,9 @@ func (g *GTK) Toggle() {}
 // Search is a no-op stub.
 func (g *GTK) Search() {}
 
+// ShowDetails is a no-op stub.
+func (g *GTK) ShowDetails(uid string) {}
+
 // SetEvents is a no-op stub.
 func (g *GTK) SetEvents(events []calendar.Event) {}
 
@@ -2,6 +2,7 @@ package menu
 
 import (
 	"fmt"
+	"html"
 	"sort"
 	"strings"
 	"time"
@@ -60,6 +61,8 @@ func formatEventList(events, hiddenEvents []calendar.Event, timeRange, eventEndG
 		return allDayEvents[i].Summary < allDayEvents[j].Summary
 	})
 
+	conflicts := calendar.ConflictingEvents(calendar.FindConflicts(timedEvents, now, cutoff))
+
 	var lines []string
 	eventMap := make(map[int]*calendar.Event)
 	var lastDay string
@@ -73,7 +76,7 @@ func formatEventList(events, hiddenEvents []calendar.Event, timeRange, eventEndG
 			lastDay = day
 		}
 
-		line := formatEventLine(e, now)
+		line := formatEventLine(e, now, len(conflicts[e.UID]) > 0)
 		lineIdx := len(lines)
 		lines = append(lines, line)
 		eventMap[lineIdx] = e
@@ -84,11 +87,7 @@ func formatEventList(events, hiddenEvents []calendar.Event, timeRange, eventEndG
 		lines = append(lines, "━━━━ All Day ━━━━")
 		for i := range allDayEvents {
 			e := &allDayEvents[i]
-			prefix := "  "
-			if e.Stale {
-				prefix = "⚠ "
-			}
-			line := fmt.Sprintf("%s%s", prefix, e.Summary)
+			line := fmt.Sprintf("%s%s", eventLinePrefix(e, false), e.Summary)
 			if e.AllDay {
 				if dateRange := formatAllDayRange(e, now); dateRange != "" {
 					line += fmt.Sprintf(" [%s]", dateRange)
@@ -126,8 +125,50 @@ func formatEventList(events, hiddenEvents []calendar.Event, timeRange, eventEndG
 	return lines, eventMap
 }
 
-// formatEventLine formats a single timed event for the list.
-func formatEventLine(e *calendar.Event, now time.Time) string {
+// markupEventLines renders event list lines as Pango markup for launchers that
+// support it. Lines for events with a calendar color get a colored dot,
+// cancelled events are struck through, and tentatively accepted events are
+// dimmed if dimTentative is set.
+func markupEventLines(lines []string, eventMap map[int]*calendar.Event, dimTentative bool) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		e, ok := eventMap[i]
+		if !ok {
+			out[i] = html.EscapeString(line)
+			continue
+		}
+		text := html.EscapeString(line)
+		if e.Color != "" {
+			text = html.EscapeString(strings.TrimPrefix(line, "  "))
+		}
+		if e.IsCancelled() {
+			text = "<s>" + text + "</s>"
+		}
+		if dimTentative && e.Response == calendar.PartStatTentative {
+			text = `<span alpha="50%">` + text + `</span>`
+		}
+		if e.Color != "" {
+			text = fmt.Sprintf(`<span foreground="%s">●</span> %s`, e.Color, text)
+		}
+		out[i] = text
+	}
+	return out
+}
+
+// plainSelection maps a line selected from the markup display back to the
+// corresponding plain line. Unknown selections are returned unchanged.
+func plainSelection(selected string, display, lines []string) string {
+	for i, d := range display {
+		if d == selected || strings.TrimSpace(d) == selected {
+			return lines[i]
+		}
+	}
+	return selected
+}
+
+// formatEventLine formats a single timed event for the list. conflict marks
+// events that overlap another busy event.
+func formatEventLine(e *calendar.Event, now time.Time, conflict bool) string {
 	localStart := e.Start.Local()
 
 	var timeStr string
@@ -148,16 +189,26 @@ func formatEventLine(e *calendar.Event, now time.Time) string {
 	}
 
 	duration := formatDuration(e.End.Sub(e.Start))
-	prefix := "  "
-	if e.Stale {
-		prefix = "⚠ "
+	return fmt.Sprintf("%s%s  %s (%s)", eventLinePrefix(e, conflict), timeStr, e.Summary, duration)
+}
+
+// eventLinePrefix returns the two-column marker in front of an event line.
+func eventLinePrefix(e *calendar.Event, conflict bool) string {
+	switch {
+	case e.IsCancelled():
+		return "✗ "
+	case e.Stale:
+		return "⚠ "
+	case conflict:
+		return "‼ "
+	default:
+		return "  "
 	}
-	return fmt.Sprintf("%s%s  %s (%s)", prefix, timeStr, e.Summary, duration)
 }
 
 // formatEventDetails formats event details for the details menu.
 // Returns lines to display and a map of line -> action URL.
-func formatEventDetails(e *calendar.Event, notificationBefore []time.Duration) ([]string, map[string]string) {
+func formatEventDetails(e *calendar.Event, notificationBefore []time.Duration, showAttendees bool) ([]string, map[string]string) {
 	now := time.Now()
 	localStart := e.Start.Local()
 	localEnd := e.End.Local()
@@ -167,6 +218,9 @@ func formatEventDetails(e *calendar.Event, notificationBefore []time.Duration) (
 
 	// Header with event title
 	lines = append(lines, fmt.Sprintf("━━━━ %s ━━━━", truncate(e.Summary, 40)))
+	if e.IsCancelled() {
+		lines = append(lines, "  🚫 Cancelled")
+	}
 
 	// Time info
 	if e.AllDay {
@@ -213,11 +267,34 @@ func formatEventDetails(e *calendar.Event, notificationBefore []time.Duration) (
 		lines = append(lines, fmt.Sprintf("  ☎ Phone conference ID: %s", e.Meeting.PhoneConferenceID))
 	}
 
+	if len(e.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("  🏷 %s", strings.Join(e.Tags, ", ")))
+	}
+
 	// Organizer
 	if e.Organizer != "" {
 		lines = append(lines, fmt.Sprintf("  👤 %s", e.Organizer))
 	}
 
+	// Attendees, collapsed to a count until selected
+	if summary := e.AttendeeSummary(); summary != "" {
+		marker := attendeesCollapsed
+		if showAttendees {
+			marker = attendeesExpanded
+		}
+		lines = append(lines, fmt.Sprintf("  👥 %s %s", summary, marker))
+		if showAttendees {
+			for _, a := range e.Attendees {
+				lines = append(lines, "      "+ui.FormatAttendee(a))
+			}
+		}
+	}
+
+	// Invitation response
+	if e.CanRespond() {
+		lines = append(lines, fmt.Sprintf("  ✉ Your response: %s", e.Response.Label()))
+	}
+
 	// Source
 	if e.Source != "" {
 		lines = append(lines, fmt.Sprintf("  📁 %s", e.Source))
@@ -245,6 +322,13 @@ func formatEventDetails(e *calendar.Event, notificationBefore []time.Duration) (
 
 	// Actions
 	lines = append(lines, "")
+	if e.CanRespond() {
+		for _, action := range responseActions {
+			if action.status != e.Response {
+				lines = append(lines, action.line)
+			}
+		}
+	}
 	lines = append(lines, "🚫 Hide this event")
 	lines = append(lines, "← Back")
 
@@ -323,6 +407,39 @@ func isHideAction(line string) bool {
 	return line == "🚫 Hide this event" || strings.Contains(line, "Hide this event")
 }
 
+// responseActions are the invitation response lines in the details menu.
+var responseActions = []struct {
+	line   string
+	status calendar.PartStat
+}{
+	{"✓ Accept invitation", calendar.PartStatAccepted},
+	{"? Tentatively accept", calendar.PartStatTentative},
+	{"✗ Decline invitation", calendar.PartStatDeclined},
+}
+
+// responseAction returns the response for an invitation action line.
+func responseAction(line string) (calendar.PartStat, bool) {
+	for _, action := range responseActions {
+		if line == action.line {
+			return action.status, true
+		}
+	}
+	return "", false
+}
+
+// Markers on the attendee summary line showing whether the list is expanded.
+const (
+	attendeesCollapsed = "▸"
+	attendeesExpanded  = "▾"
+)
+
+// isAttendeesToggle returns true if the line is the attendee summary, which
+// expands or collapses the attendee list.
+func isAttendeesToggle(line string) bool {
+	return strings.HasPrefix(line, "👥 ") &&
+		(strings.HasSuffix(line, attendeesCollapsed) || strings.HasSuffix(line, attendeesExpanded))
+}
+
 // isHiddenIndicator returns true if the line is the hidden events indicator.
 func isHiddenIndicator(line string) bool {
 	return strings.HasPrefix(line, "👁 ") && strings.Contains(line, "hidden event")
@@ -77,7 +77,7 @@ func TestFormatEventDetails_ShowsEventReminderDetails(t *testing.T) {
 		Start:    start,
 		End:      start.Add(30 * time.Minute),
 		NotifyAt: []time.Time{start.Add(-15 * time.Minute)},
-	}, nil)
+	}, nil, false)
 
 	joined := strings.Join(lines, "\n")
 	if !strings.Contains(joined, "Using event reminders") {
@@ -95,7 +95,7 @@ func TestFormatEventDetails_ShowsOverrideReminderDetails(t *testing.T) {
 		Start:    start,
 		End:      start.Add(30 * time.Minute),
 		NotifyAt: []time.Time{start.Add(-15 * time.Minute)},
-	}, []time.Duration{0})
+	}, []time.Duration{0}, false)
 
 	joined := strings.Join(lines, "\n")
 	if !strings.Contains(joined, "Using config override") {
@@ -106,6 +106,45 @@ func TestFormatEventDetails_ShowsOverrideReminderDetails(t *testing.T) {
 	}
 }
 
+func TestFormatEventDetails_Cancelled(t *testing.T) {
+	start := time.Date(2026, 2, 17, 15, 0, 0, 0, time.Local)
+	lines, _ := formatEventDetails(&calendar.Event{
+		Summary:  "1:1",
+		Start:    start,
+		End:      start.Add(30 * time.Minute),
+		Status:   calendar.StatusCancelled,
+		NotifyAt: []time.Time{start.Add(-15 * time.Minute)},
+	}, nil, false)
+
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "🚫 Cancelled") {
+		t.Fatalf("expected cancelled line, got %q", joined)
+	}
+	if !strings.Contains(joined, "No notifications for cancelled events") || strings.Contains(joined, "before start") {
+		t.Fatalf("expected no reminder schedule for a cancelled event, got %q", joined)
+	}
+}
+
+func TestFormatEventDetails_TagsAndTransformReminders(t *testing.T) {
+	start := time.Date(2026, 2, 17, 15, 0, 0, 0, time.Local)
+	lines, _ := formatEventDetails(&calendar.Event{
+		Summary:   "Sync",
+		Start:     start,
+		End:       start.Add(30 * time.Minute),
+		Tags:      []string{"work", "external"},
+		NotifyAt:  []time.Time{start.Add(-time.Hour)},
+		NotifySet: true,
+	}, []time.Duration{5 * time.Minute}, false)
+
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "🏷 work, external") {
+		t.Fatalf("expected tags line, got %q", joined)
+	}
+	if !strings.Contains(joined, "Using transform reminders") || !strings.Contains(joined, "1 hour before start") {
+		t.Fatalf("expected transform reminders to win over the config override, got %q", joined)
+	}
+}
+
 func TestFormatEventDetails_ShowsMeetingDetails(t *testing.T) {
 	start := time.Date(2026, 5, 5, 12, 0, 0, 0, time.Local)
 	lines, urlMap := formatEventDetails(&calendar.Event{
@@ -121,7 +160,7 @@ func TestFormatEventDetails_ShowsMeetingDetails(t *testing.T) {
 			DialIn:            "+1 323-849-4874,,864359718# United States, Los Angeles",
 			PhoneConferenceID: "864 359 718#",
 		},
-	}, nil)
+	}, nil, false)
 
 	joined := strings.Join(lines, "\n")
 	for _, want := range []string{
@@ -139,3 +178,130 @@ func TestFormatEventDetails_ShowsMeetingDetails(t *testing.T) {
 		t.Fatalf("unexpected join URL: %q", got)
 	}
 }
+
+func TestFormatEventDetails_ShowsResponseActions(t *testing.T) {
+	start := time.Date(2026, 5, 5, 12, 0, 0, 0, time.Local)
+	event := &calendar.Event{
+		Summary:  "Planning",
+		Start:    start,
+		End:      start.Add(time.Hour),
+		Response: calendar.PartStatTentative,
+		Remote:   calendar.RemoteRef{ID: "AAMk-1"},
+	}
+
+	lines, _ := formatEventDetails(event, nil, false)
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "Your response: Tentative") {
+		t.Fatalf("expected current response in details, got %q", joined)
+	}
+
+	var got []calendar.PartStat
+	for _, line := range lines {
+		if status, ok := responseAction(line); ok {
+			got = append(got, status)
+		}
+	}
+	want := []calendar.PartStat{calendar.PartStatAccepted, calendar.PartStatDeclined}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("unexpected response actions: got %v want %v", got, want)
+	}
+
+	event.Remote = calendar.RemoteRef{}
+	lines, _ = formatEventDetails(event, nil, false)
+	for _, line := range lines {
+		if _, ok := responseAction(line); ok {
+			t.Fatalf("unexpected response action %q for read-only event", line)
+		}
+	}
+}
+
+func TestFormatEventDetails_AttendeesCollapsible(t *testing.T) {
+	start := time.Date(2026, 5, 5, 12, 0, 0, 0, time.Local)
+	event := &calendar.Event{
+		Summary: "Planning",
+		Start:   start,
+		End:     start.Add(time.Hour),
+		Attendees: []calendar.Attendee{
+			{Name: "Jane Doe", Email: "jane@example.com", Role: calendar.RoleRequired, Status: calendar.PartStatDeclined},
+			{Email: "sam@example.com", Role: calendar.RoleOptional, Status: calendar.PartStatAccepted},
+		},
+	}
+
+	lines, _ := formatEventDetails(event, nil, false)
+	joined := strings.Join(lines, "\n")
+	toggle := "  👥 2 attendees: 1 accepted, 1 declined ▸"
+	if !strings.Contains(joined, toggle) {
+		t.Fatalf("expected collapsed attendee summary, got %q", joined)
+	}
+	if !isAttendeesToggle(strings.TrimSpace(toggle)) {
+		t.Fatal("expected summary line to toggle the attendee list")
+	}
+	if strings.Contains(joined, "jane@example.com") {
+		t.Fatalf("expected attendee list to be collapsed, got %q", joined)
+	}
+
+	lines, _ = formatEventDetails(event, nil, true)
+	joined = strings.Join(lines, "\n")
+	for _, want := range []string{
+		"👥 2 attendees: 1 accepted, 1 declined ▾",
+		"✗ Jane Doe <jane@example.com>",
+		"✓ sam@example.com (optional)",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected %q in expanded details, got %q", want, joined)
+		}
+	}
+}
+
+func TestMarkupEventLines(t *testing.T) {
+	colored := &calendar.Event{Summary: "Standup", Color: "#3584e4"}
+	plain := &calendar.Event{Summary: "Lunch"}
+	tentative := &calendar.Event{Summary: "Review", Color: "#3584e4", Response: calendar.PartStatTentative}
+	cancelled := &calendar.Event{Summary: "1:1", Status: calendar.StatusCancelled}
+	lines := []string{
+		"━━━━ Today ━━━━",
+		"  09:00  Standup & sync (15m)",
+		"  12:00  Lunch <team> (1h)",
+		"  15:00  Review (1h)",
+		"✗ 16:00  1:1 (30m)",
+	}
+	eventMap := map[int]*calendar.Event{1: colored, 2: plain, 3: tentative, 4: cancelled}
+
+	display := markupEventLines(lines, eventMap, true)
+	want := []string{
+		"━━━━ Today ━━━━",
+		`<span foreground="#3584e4">●</span> 09:00  Standup &amp; sync (15m)`,
+		"  12:00  Lunch &lt;team&gt; (1h)",
+		`<span foreground="#3584e4">●</span> <span alpha="50%">15:00  Review (1h)</span>`,
+		"<s>✗ 16:00  1:1 (30m)</s>",
+	}
+	for i := range want {
+		if display[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, display[i], want[i])
+		}
+	}
+
+	if got := plainSelection(display[1], display, lines); got != lines[1] {
+		t.Errorf("plainSelection(colored) = %q, want %q", got, lines[1])
+	}
+	if got := plainSelection(strings.TrimSpace(display[2]), display, lines); got != lines[2] {
+		t.Errorf("plainSelection(trimmed) = %q, want %q", got, lines[2])
+	}
+}
+
+func TestFormatEventList_MarksConflicts(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	events := []calendar.Event{
+		{UID: "review", Summary: "Review", Start: start, End: start.Add(time.Hour)},
+		{UID: "1:1", Summary: "1:1", Start: start.Add(30 * time.Minute), End: start.Add(90 * time.Minute)},
+		{UID: "lunch", Summary: "Lunch", Start: start.Add(3 * time.Hour), End: start.Add(4 * time.Hour)},
+	}
+
+	lines, eventMap := formatEventList(events, nil, 24*time.Hour, 0)
+	for i, e := range eventMap {
+		conflict := strings.HasPrefix(lines[i], "‼ ")
+		if want := e.UID != "lunch"; conflict != want {
+			t.Errorf("line %q marked as conflict = %v, want %v", lines[i], conflict, want)
+		}
+	}
+}
@@ -21,8 +21,9 @@ type Config struct {
 	Program            string   // dmenu program to use (auto-detect if empty)
 	Args               []string // extra args to pass to the program
 	TimeRange          time.Duration
-	EventEndGrace      time.Duration // Keep events visible after they end
-	NotificationBefore []time.Duration
+	EventEndGrace      time.Duration                        // Keep events visible after they end
+	NotificationBefore func(calendar.Event) []time.Duration // Configured reminder offsets for an event; nil result = event reminders
+	DimTentative       bool                                 // Dim events the user tentatively accepted
 }
 
 // Menu implements the ui.UI interface using dmenu-style launchers.
@@ -116,6 +117,36 @@ func (m *Menu) Search() {
 	m.Show()
 }
 
+// ShowDetails displays the details menu for the event with the given UID,
+// or the event list if it is not listed.
+func (m *Menu) ShowDetails(uid string) {
+	m.mu.Lock()
+	if m.isShowing {
+		m.mu.Unlock()
+		return
+	}
+	m.isShowing = true
+	events := m.events
+	hiddenEvents := m.hiddenEvents
+	m.mu.Unlock()
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			m.isShowing = false
+			m.mu.Unlock()
+		}()
+
+		i := slices.IndexFunc(events, func(e calendar.Event) bool { return e.UID == uid })
+		if i < 0 {
+			slog.Debug("event to show details for is not listed", "uid", uid)
+			m.showEventList(events, hiddenEvents)
+			return
+		}
+		m.showEventDetails(&events[i], events, hiddenEvents)
+	}()
+}
+
 // SetEvents updates the event list.
 func (m *Menu) SetEvents(events []calendar.Event) {
 	m.mu.Lock()
@@ -168,13 +199,22 @@ func (m *Menu) showEventList(events, hiddenEvents []calendar.Event) {
 	lines, eventMap := formatEventList(events, hiddenEvents, m.cfg.TimeRange, m.cfg.EventEndGrace)
 	slog.Debug("formatted event list", "lineCount", len(lines), "eventMapSize", len(eventMap))
 
-	selected, err := m.runDmenu(lines, "CalBar")
+	markup := supportsMarkup(m.program)
+	display := lines
+	if markup {
+		display = markupEventLines(lines, eventMap, m.cfg.DimTentative)
+	}
+
+	selected, err := m.runMenu(display, "CalBar", markup)
 	if err != nil {
 		slog.Debug("menu closed without selection", "error", err)
 		return
 	}
 
 	selected = strings.TrimSpace(selected)
+	if markup {
+		selected = strings.TrimSpace(plainSelection(selected, display, lines))
+	}
 	slog.Debug("event list selection", "selected", selected, "selectedLen", len(selected), "selectedBytes", fmt.Sprintf("%q", selected))
 
 	if selected == "" || isSeparator(selected) {
@@ -219,7 +259,13 @@ func (m *Menu) showEventList(events, hiddenEvents []calendar.Event) {
 
 // showEventDetails displays event details and handles selection.
 func (m *Menu) showEventDetails(event *calendar.Event, allEvents, hiddenEvents []calendar.Event) {
-	lines, urlMap := formatEventDetails(event, m.cfg.NotificationBefore)
+	m.showEventDetailsView(event, allEvents, hiddenEvents, false)
+}
+
+// showEventDetailsView shows the details menu, with the attendee list expanded
+// if showAttendees is set.
+func (m *Menu) showEventDetailsView(event *calendar.Event, allEvents, hiddenEvents []calendar.Event, showAttendees bool) {
+	lines, urlMap := formatEventDetails(event, m.cfg.NotificationBefore(*event), showAttendees)
 
 	slog.Debug("showing event details menu", "eventSummary", event.Summary, "lineCount", len(lines))
 
@@ -266,6 +312,21 @@ func (m *Menu) showEventDetails(event *calendar.Event, allEvents, hiddenEvents [
 		return
 	}
 
+	// Expand or collapse the attendee list
+	if isAttendeesToggle(selected) {
+		m.showEventDetailsView(event, allEvents, hiddenEvents, !showAttendees)
+		return
+	}
+
+	// Check for invitation response
+	if status, ok := responseAction(selected); ok {
+		slog.Debug("respond to invitation via menu", "uid", event.UID, "response", status)
+		if m.onAction != nil {
+			m.onAction(ui.Action{Type: ui.ActionRespond, UID: event.UID, Response: status})
+		}
+		return
+	}
+
 	// Check for URL action (urlMap keys are already trimmed)
 	if url, ok := urlMap[selected]; ok {
 		slog.Debug("opening URL from menu", "url", url)
@@ -353,7 +414,12 @@ func (m *Menu) showHiddenEventsList(allEvents, hiddenEvents []calendar.Event) {
 // runDmenu runs the dmenu program with the given input lines.
 // Returns the selected line or an error if the user cancelled.
 func (m *Menu) runDmenu(lines []string, prompt string) (string, error) {
-	args := m.buildArgs(prompt)
+	return m.runMenu(lines, prompt, false)
+}
+
+// runMenu runs the dmenu program, optionally rendering lines as Pango markup.
+func (m *Menu) runMenu(lines []string, prompt string, markup bool) (string, error) {
+	args := m.buildArgs(prompt, markup)
 	cmd := exec.Command(m.program, args...)
 
 	// Prepare input
@@ -380,8 +446,13 @@ func (m *Menu) runDmenu(lines []string, prompt string) (string, error) {
 	return stdout.String(), nil
 }
 
+// supportsMarkup reports whether program can render Pango markup in menu rows.
+func supportsMarkup(program string) bool {
+	return program == "rofi" || program == "wofi"
+}
+
 // buildArgs builds command-line arguments for the dmenu program.
-func (m *Menu) buildArgs(prompt string) []string {
+func (m *Menu) buildArgs(prompt string, markup bool) []string {
 	var args []string
 
 	switch m.program {
@@ -400,6 +471,15 @@ func (m *Menu) buildArgs(prompt string) []string {
 		args = []string{"-p", prompt}
 	}
 
+	if markup {
+		switch m.program {
+		case "rofi":
+			args = append(args, "-markup-rows")
+		case "wofi":
+			args = append(args, "--allow-markup")
+		}
+	}
+
 	// Add user-specified extra args
 	args = append(args, m.cfg.Args...)
 
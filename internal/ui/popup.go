@@ -61,6 +61,9 @@ type Popup struct {
 	searchButton  *gtk.Button
 	searchBox     *gtk.Box
 	searchEntry   *gtk.SearchEntry
+	addButton     *gtk.Button
+	addBox        *gtk.Box
+	addEntry      *gtk.Entry
 
 	// Details panel
 	stack             *gtk.Stack
@@ -83,14 +86,26 @@ type Popup struct {
 	searchQuery        string
 	pointerInside      bool
 	hoverDismissDelay  time.Duration
-	notificationBefore []time.Duration
+	notificationBefore func(calendar.Event) []time.Duration
 	cssFile            string
+	dimTentative       bool
+	detailsUID         string // Event to show by the next showDetailsCb
+
+	// Generated CSS for calendar/source color accents (GTK main thread only)
+	colorProvider *gtk.CssProvider
+	colorCSS      string
+
+	// Overlapping events in the time range by UID (GTK main thread only)
+	conflicts     map[string][]calendar.Event
+	conflictCount int
 
 	dismissTimer uint
 	onJoin       func(url string)
 	onHide       func(uid string)
 	onUnhide     func(uid string)
 	onSync       func()
+	onRespond    func(uid string, status calendar.PartStat)
+	onQuickAdd   func(text string)
 
 	// Stable callback references to avoid exhausting purego callback slots.
 	eventRowClickCb        stableCallback[func(gtk.GestureClick, int, float64, float64)]
@@ -101,12 +116,16 @@ type Popup struct {
 	unhideBtnClickCb       stableCallback[func(gtk.Button)]
 	joinClickCb            stableCallback[func(gtk.Button)]
 	hideClickCb            stableCallback[func(gtk.Button)]
+	respondClickCb         stableCallback[func(gtk.Button)]
 	unhideClickCb          stableCallback[func(gtk.Button)]
 	syncClickCb            stableCallback[func(gtk.Button)]
 	searchClickCb          stableCallback[func(gtk.Button)]
 	searchCloseClickCb     stableCallback[func(gtk.Button)]
 	searchChangedCb        stableCallback[func(gtk.SearchEntry)]
 	searchStopCb           stableCallback[func(gtk.SearchEntry)]
+	addClickCb             stableCallback[func(gtk.Button)]
+	addCloseClickCb        stableCallback[func(gtk.Button)]
+	addActivateCb          stableCallback[func(gtk.Entry)]
 	backBtnClickCb         stableCallback[func(gtk.Button)]
 	hiddenBackBtnClickCb   stableCallback[func(gtk.Button)]
 	syncErrorsBackBtnCb    stableCallback[func(gtk.Button)]
@@ -115,6 +134,7 @@ type Popup struct {
 	updateStatusCb         stableCallback[glib.SourceFunc]
 	showCb                 stableCallback[glib.SourceFunc]
 	searchShowCb           stableCallback[glib.SourceFunc]
+	showDetailsCb          stableCallback[glib.SourceFunc]
 	hideCb                 stableCallback[glib.SourceFunc]
 	toggleCb               stableCallback[glib.SourceFunc]
 	dismissTimerCb         stableCallback[glib.SourceFunc]
@@ -125,8 +145,9 @@ type Popup struct {
 	clickOutsideContent bool
 
 	// Widget -> data lookup for stable callbacks (accessed only from GTK main thread)
-	widgetEvents map[uintptr]*calendar.Event
-	widgetLinks  map[uintptr]string
+	widgetEvents    map[uintptr]*calendar.Event
+	widgetLinks     map[uintptr]string
+	widgetResponses map[uintptr]calendar.PartStat
 
 	// Track view-local lookup entries so repeated details/hidden rebuilds don't
 	// retain stale event/link data until the next full list refresh.
@@ -172,6 +193,7 @@ func (p *Popup) clearLookupEntries(ptrs []uintptr) []uintptr {
 	for _, ptr := range ptrs {
 		delete(p.widgetEvents, ptr)
 		delete(p.widgetLinks, ptr)
+		delete(p.widgetResponses, ptr)
 	}
 	return nil
 }
@@ -250,6 +272,25 @@ func (p *Popup) getHideClickCb() *func(gtk.Button) {
 	})
 }
 
+func (p *Popup) getRespondClickCb() *func(gtk.Button) {
+	return p.respondClickCb.get(func() func(gtk.Button) {
+		return func(btn gtk.Button) {
+			status, ok := p.widgetResponses[btn.GoPointer()]
+			if !ok || p.detailsEvent == nil {
+				return
+			}
+			slog.Debug("respond to invitation", "uid", p.detailsEvent.UID, "response", status)
+			if p.onRespond != nil {
+				p.onRespond(p.detailsEvent.UID, status)
+			}
+			// Reflect the choice right away; the next sync confirms it.
+			event := *p.detailsEvent
+			event.Response = status
+			p.showDetails(event)
+		}
+	})
+}
+
 func (p *Popup) getHiddenIndicatorClickCb() *func(gtk.GestureClick, int, float64, float64) {
 	return p.hiddenIndicatorClickCb.get(func() func(gtk.GestureClick, int, float64, float64) {
 		return func(gesture gtk.GestureClick, nPress int, x, y float64) {
@@ -364,6 +405,38 @@ func (p *Popup) getSearchStopCb() *func(gtk.SearchEntry) {
 	})
 }
 
+func (p *Popup) getAddClickCb() *func(gtk.Button) {
+	return p.addClickCb.get(func() func(gtk.Button) {
+		return func(btn gtk.Button) {
+			p.showQuickAdd()
+		}
+	})
+}
+
+func (p *Popup) getAddCloseClickCb() *func(gtk.Button) {
+	return p.addCloseClickCb.get(func() func(gtk.Button) {
+		return func(btn gtk.Button) {
+			p.hideQuickAdd()
+		}
+	})
+}
+
+func (p *Popup) getAddActivateCb() *func(gtk.Entry) {
+	return p.addActivateCb.get(func() func(gtk.Entry) {
+		return func(entry gtk.Entry) {
+			text := strings.TrimSpace(entry.GetText())
+			if text == "" {
+				return
+			}
+			if p.onQuickAdd != nil {
+				slog.Debug("quick-add requested", "text", text)
+				p.onQuickAdd(text)
+			}
+			p.hideQuickAdd()
+		}
+	})
+}
+
 func (p *Popup) getHiddenBackBtnClickCb() *func(gtk.Button) {
 	return p.hiddenBackBtnClickCb.get(func() func(gtk.Button) {
 		return func(btn gtk.Button) {
@@ -452,6 +525,39 @@ func (p *Popup) getSearchShowCb() *glib.SourceFunc {
 	})
 }
 
+func (p *Popup) getShowDetailsCb() *glib.SourceFunc {
+	return p.showDetailsCb.get(func() glib.SourceFunc {
+		return func(data uintptr) bool {
+			p.mu.RLock()
+			uid := p.detailsUID
+			var event *calendar.Event
+			for i := range p.events {
+				if p.events[i].UID == uid {
+					e := p.events[i]
+					event = &e
+					break
+				}
+			}
+			p.mu.RUnlock()
+
+			p.detailsFromHidden = false
+			p.updateList()
+			if event != nil {
+				p.showDetails(*event)
+			} else {
+				slog.Debug("event to show details for is not listed", "uid", uid)
+				p.detailsEvent = nil
+				if p.stack != nil {
+					p.stack.SetVisibleChildName("list")
+				}
+			}
+			p.window.SetVisible(true)
+			p.window.Present()
+			return false
+		}
+	})
+}
+
 func (p *Popup) getHideCb() *glib.SourceFunc {
 	return p.hideCb.get(func() glib.SourceFunc {
 		return func(data uintptr) bool {
@@ -532,14 +638,15 @@ func (p *Popup) getClickOutsideReleasedCb() *func(gtk.GestureClick, int, float64
 }
 
 // NewPopup creates a new popup window.
-func NewPopup(timeRange, eventEndGrace, hoverDismissDelay time.Duration, notificationBefore []time.Duration, cssFile string) *Popup {
+func NewPopup(timeRange, eventEndGrace, hoverDismissDelay time.Duration, notificationBefore func(calendar.Event) []time.Duration, cssFile string, dimTentative bool) *Popup {
 	return &Popup{
 		timeRange:          timeRange,
 		eventEndGrace:      eventEndGrace,
 		loading:            true,
 		hoverDismissDelay:  hoverDismissDelay,
-		notificationBefore: append([]time.Duration(nil), notificationBefore...),
+		notificationBefore: notificationBefore,
 		cssFile:            cssFile,
+		dimTentative:       dimTentative,
 	}
 }
 
@@ -548,6 +655,7 @@ func (p *Popup) Init() {
 	// Initialize widget -> data lookup maps
 	p.widgetEvents = make(map[uintptr]*calendar.Event)
 	p.widgetLinks = make(map[uintptr]string)
+	p.widgetResponses = make(map[uintptr]calendar.PartStat)
 
 	// Initialize libadwaita for automatic dark/light mode support
 	adw.Init()
@@ -595,6 +703,10 @@ func (p *Popup) Init() {
 			}
 		}
 		if keyval == uint(gdk.KEY_Escape) {
+			if p.stack != nil && p.stack.GetVisibleChildName() == "list" && p.addBox != nil && p.addBox.GetVisible() {
+				p.hideQuickAdd()
+				return true
+			}
 			if p.stack != nil && p.stack.GetVisibleChildName() == "list" && p.searchBox != nil && p.searchBox.GetVisible() {
 				p.hideSearch()
 				return true
@@ -701,6 +813,10 @@ func (p *Popup) buildUI() {
 	searchBox := p.buildSearchBox()
 	p.listView.Append(&searchBox.Widget)
 
+	// Quick-add row
+	addBox := p.buildQuickAddBox()
+	p.listView.Append(&addBox.Widget)
+
 	// Scrolled event list
 	scrolled := gtk.NewScrolledWindow()
 	scrolled.SetVexpand(true)
@@ -788,6 +904,15 @@ func (p *Popup) buildHeader() *gtk.Box {
 	p.searchButton.ConnectClicked(p.getSearchClickCb())
 	header.Append(&p.searchButton.Widget)
 
+	p.addButton = gtk.NewButton()
+	p.addButton.AddCssClass("add-button")
+	p.addButton.SetTooltipText("Quick-add event")
+	addIcon := gtk.NewImageFromIconName("list-add-symbolic")
+	addIcon.SetPixelSize(16)
+	setOwnedChild(p.addButton, &addIcon.Widget, addIcon)
+	p.addButton.ConnectClicked(p.getAddClickCb())
+	header.Append(&p.addButton.Widget)
+
 	spacer := gtk.NewBox(gtk.OrientationHorizontalValue, 0)
 	spacer.SetHexpand(true)
 	header.Append(&spacer.Widget)
@@ -838,6 +963,58 @@ func (p *Popup) buildSearchBox() *gtk.Box {
 	return p.searchBox
 }
 
+// buildQuickAddBox creates the expandable quick-add row below the header.
+func (p *Popup) buildQuickAddBox() *gtk.Box {
+	p.addBox = gtk.NewBox(gtk.OrientationHorizontalValue, 8)
+	p.addBox.AddCssClass("add-box")
+	p.addBox.SetVisible(false)
+
+	p.addEntry = gtk.NewEntry()
+	p.addEntry.SetPlaceholderText("Focus 2pm-4pm tomorrow")
+	p.addEntry.SetHexpand(true)
+	p.addEntry.SetValign(gtk.AlignCenterValue)
+	p.addEntry.ConnectActivate(p.getAddActivateCb())
+	p.addBox.Append(&p.addEntry.Widget)
+
+	closeButton := gtk.NewButton()
+	closeButton.AddCssClass("search-close-button")
+	closeButton.SetTooltipText("Cancel")
+	closeButton.SetValign(gtk.AlignCenterValue)
+	closeIcon := gtk.NewImageFromIconName("window-close-symbolic")
+	closeIcon.SetPixelSize(16)
+	setOwnedChild(closeButton, &closeIcon.Widget, closeIcon)
+	closeButton.ConnectClicked(p.getAddCloseClickCb())
+	p.addBox.Append(&closeButton.Widget)
+
+	return p.addBox
+}
+
+func (p *Popup) showQuickAdd() {
+	if p.addBox == nil {
+		return
+	}
+	p.addBox.SetVisible(true)
+	if p.addEntry != nil {
+		p.addEntry.GrabFocus()
+	}
+	if p.addButton != nil {
+		p.addButton.SetSensitive(false)
+	}
+}
+
+func (p *Popup) hideQuickAdd() {
+	if p.addBox == nil {
+		return
+	}
+	p.addBox.SetVisible(false)
+	if p.addEntry != nil {
+		p.addEntry.SetText("")
+	}
+	if p.addButton != nil {
+		p.addButton.SetSensitive(true)
+	}
+}
+
 func (p *Popup) showSearch() {
 	if p.searchBox == nil {
 		return
@@ -913,19 +1090,26 @@ func (p *Popup) applyCSS() {
 			padding: 0;
 		}
 
-		.search-button {
+		.search-button,
+		.add-button {
 			min-width: 32px;
 			min-height: 32px;
 			padding: 0;
 			margin-left: 8px;
 		}
 
-		.search-box {
+		.add-button {
+			margin-left: 4px;
+		}
+
+		.search-box,
+		.add-box {
 			padding: 6px 16px 8px 16px;
 			border-bottom: 1px solid alpha(@borders, 0.3);
 		}
 
-		.search-box entry {
+		.search-box entry,
+		.add-box entry {
 			min-height: 28px;
 		}
 
@@ -970,6 +1154,18 @@ func (p *Popup) applyCSS() {
 			border-bottom: 1px solid alpha(@borders, 0.2);
 		}
 
+		/* Calendar/source color accent; colors come from generated .source-color-RRGGBB rules */
+		.source-accent {
+			min-width: 4px;
+			margin-right: 10px;
+			border-radius: 2px;
+		}
+
+		.all-day-row.has-source-color {
+			border-left: 3px solid transparent;
+			padding-left: 13px;
+		}
+
 		/* Time indicator on the left */
 		.time-indicator {
 			min-width: 52px;
@@ -1147,6 +1343,30 @@ func (p *Popup) applyCSS() {
 			border-bottom: none;
 		}
 
+		/* Events the user tentatively accepted */
+		.event-card.tentative,
+		.all-day-row.tentative {
+			opacity: 0.55;
+		}
+
+		/* Events cancelled by the organizer */
+		.event-card.cancelled .event-title,
+		.all-day-row.cancelled .all-day-title {
+			text-decoration: line-through;
+		}
+
+		.event-card.cancelled,
+		.all-day-row.cancelled {
+			opacity: 0.55;
+		}
+
+		/* Events that overlap another busy event */
+		.event-conflict {
+			font-size: 11px;
+			color: @warning_color;
+			margin-top: 4px;
+		}
+
 		.all-day-title {
 			font-size: 13px;
 			font-weight: 400;
@@ -1223,6 +1443,25 @@ func (p *Popup) applyCSS() {
 			line-height: 1.5;
 		}
 
+		/* Attendees */
+		.details-attendees {
+			font-size: 13px;
+		}
+
+		.details-attendee-list {
+			margin-top: 6px;
+		}
+
+		.details-attendee {
+			font-size: 12px;
+			color: alpha(@view_fg_color, 0.8);
+		}
+
+		.details-attendee.declined {
+			color: alpha(@view_fg_color, 0.5);
+			text-decoration: line-through;
+		}
+
 		.details-join-box {
 			margin-top: 24px;
 			padding-top: 16px;
@@ -1236,6 +1475,27 @@ func (p *Popup) applyCSS() {
 			font-size: 12px;
 		}
 
+		/* Invitation response */
+		.details-rsvp-box {
+			margin-top: 16px;
+			padding-top: 16px;
+			border-top: 1px solid alpha(@borders, 0.2);
+		}
+
+		.rsvp-btn {
+			min-height: 28px;
+			min-width: 80px;
+			padding: 0 12px;
+			border-radius: 8px;
+			font-size: 12px;
+			font-weight: 500;
+		}
+
+		.rsvp-btn.selected {
+			background: alpha(@accent_bg_color, 0.25);
+			color: @accent_color;
+		}
+
 		/* Hide/Unhide button */
 		.details-action-box {
 			margin-top: 16px;
@@ -1401,6 +1661,50 @@ func (p *Popup) applyCSS() {
 	}
 }
 
+// colorClass returns the generated CSS class carrying an event color.
+func colorClass(color string) string {
+	return "source-color-" + strings.TrimPrefix(color, "#")
+}
+
+// sourceColorCSS generates the accent rules for the given "#rrggbb" colors.
+func sourceColorCSS(colors []string) string {
+	var b strings.Builder
+	for _, color := range colors {
+		class := colorClass(color)
+		fmt.Fprintf(&b, ".source-accent.%s { background-color: %s; }\n", class, color)
+		fmt.Fprintf(&b, ".all-day-row.%s { border-left-color: %s; }\n", class, color)
+	}
+	return b.String()
+}
+
+// updateColorCSS (re)loads the generated accent rules for the colors in events.
+// Must be called from the GTK main thread.
+func (p *Popup) updateColorCSS(events []calendar.Event) {
+	var colors []string
+	for _, e := range events {
+		if e.Color != "" && !slices.Contains(colors, e.Color) {
+			colors = append(colors, e.Color)
+		}
+	}
+	slices.Sort(colors)
+
+	css := sourceColorCSS(colors)
+	if css == p.colorCSS {
+		return
+	}
+
+	if p.colorProvider == nil {
+		display := gdk.DisplayGetDefault()
+		if display == nil {
+			return
+		}
+		p.colorProvider = gtk.NewCssProvider()
+		gtk.StyleContextAddProviderForDisplay(display, p.colorProvider, uint(gtk.STYLE_PROVIDER_PRIORITY_APPLICATION))
+	}
+	p.colorProvider.LoadFromString(css)
+	p.colorCSS = css
+}
+
 func (p *Popup) userCSSPath() (string, bool) {
 	path := p.cssFile
 	if path == "" {
@@ -1437,6 +1741,18 @@ func (p *Popup) Search() {
 	glib.IdleAdd(p.getSearchShowCb(), 0)
 }
 
+// ShowDetails shows the popup window with the details of the event with the
+// given UID, or the event list if it is not listed.
+func (p *Popup) ShowDetails(uid string) {
+	if p.window == nil {
+		return
+	}
+	p.mu.Lock()
+	p.detailsUID = uid
+	p.mu.Unlock()
+	glib.IdleAdd(p.getShowDetailsCb(), 0)
+}
+
 // Hide hides the popup window.
 func (p *Popup) Hide() {
 	if p.window == nil {
@@ -1518,11 +1834,21 @@ func (p *Popup) OnJoin(fn func(url string)) {
 	p.onJoin = fn
 }
 
+// OnQuickAdd sets the callback for when the user enters quick-add text.
+func (p *Popup) OnQuickAdd(fn func(text string)) {
+	p.onQuickAdd = fn
+}
+
 // OnSync sets the callback for when the user requests a manual sync.
 func (p *Popup) OnSync(fn func()) {
 	p.onSync = fn
 }
 
+// OnRespond sets the callback for when the user responds to an invitation.
+func (p *Popup) OnRespond(fn func(uid string, status calendar.PartStat)) {
+	p.onRespond = fn
+}
+
 // OnHide sets the callback for when the user hides an event.
 func (p *Popup) OnHide(fn func(uid string)) {
 	p.onHide = fn
@@ -1552,6 +1878,7 @@ func (p *Popup) updateList() {
 	// Clear widget -> data lookup maps before rebuilding
 	p.widgetEvents = make(map[uintptr]*calendar.Event)
 	p.widgetLinks = make(map[uintptr]string)
+	p.widgetResponses = make(map[uintptr]calendar.PartStat)
 	p.detailsLookupPtrs = nil
 	p.hiddenLookupPtrs = nil
 
@@ -1577,8 +1904,14 @@ func (p *Popup) updateList() {
 		return
 	}
 
+	p.updateColorCSS(events)
+
 	now := time.Now()
 	cutoff := now.Add(timeRange)
+
+	conflicts := calendar.FindConflicts(events, now, cutoff)
+	p.conflicts = calendar.ConflictingEvents(conflicts)
+	p.conflictCount = len(conflicts)
 	// Get today in local time for all-day event filtering
 	localNow := now.Local()
 	today := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, time.Local)
@@ -1852,6 +2185,16 @@ func (p *Popup) createTimedEventRow(event calendar.Event, now time.Time) *gtk.Bo
 	} else if startsIn := event.Start.Sub(now); startsIn <= 15*time.Minute && startsIn > 0 {
 		row.AddCssClass("imminent")
 	}
+	if p.dimTentative && event.Response == calendar.PartStatTentative {
+		row.AddCssClass("tentative")
+	}
+	if event.IsCancelled() {
+		row.AddCssClass("cancelled")
+	}
+	overlaps := p.conflicts[event.UID]
+	if len(overlaps) > 0 {
+		row.AddCssClass("conflict")
+	}
 
 	// Store event for lookup
 	eventCopy := event
@@ -1869,6 +2212,15 @@ func (p *Popup) createTimedEventRow(event calendar.Event, now time.Time) *gtk.Bo
 	rightClickGesture.ConnectReleased(p.getEventRowRightClickCb())
 	row.AddController(&rightClickGesture.EventController)
 
+	// Calendar/source color accent
+	if event.Color != "" {
+		row.AddCssClass("has-source-color")
+		accent := gtk.NewBox(gtk.OrientationVerticalValue, 0)
+		accent.AddCssClass("source-accent")
+		accent.AddCssClass(colorClass(event.Color))
+		appendOwned(row, &accent.Widget, accent)
+	}
+
 	// Time indicator
 	timeBox := p.createTimeIndicator(event, now)
 	appendOwned(row, &timeBox.Widget, timeBox)
@@ -1915,6 +2267,16 @@ func (p *Popup) createTimedEventRow(event calendar.Event, now time.Time) *gtk.Bo
 		appendOwned(details, &source.Widget, source)
 	}
 
+	// Double-booking
+	if len(overlaps) > 0 {
+		conflict := gtk.NewLabel("⚠ " + formatOverlaps(overlaps))
+		conflict.AddCssClass("event-conflict")
+		conflict.SetXalign(0)
+		conflict.SetEllipsize(pango.EllipsizeEndValue)
+		conflict.SetTooltipText(formatOverlaps(overlaps))
+		appendOwned(details, &conflict.Widget, conflict)
+	}
+
 	// Join button
 	meetingLink := event.Meeting.URL
 	if meetingLink == "" {
@@ -1932,6 +2294,16 @@ func (p *Popup) createTimedEventRow(event calendar.Event, now time.Time) *gtk.Bo
 func (p *Popup) createAllDayEventRow(event calendar.Event, now time.Time) *gtk.Box {
 	row := gtk.NewBox(gtk.OrientationVerticalValue, 0)
 	row.AddCssClass("all-day-row")
+	if event.Color != "" {
+		row.AddCssClass("has-source-color")
+		row.AddCssClass(colorClass(event.Color))
+	}
+	if p.dimTentative && event.Response == calendar.PartStatTentative {
+		row.AddCssClass("tentative")
+	}
+	if event.IsCancelled() {
+		row.AddCssClass("cancelled")
+	}
 
 	// Store event for lookup
 	eventCopy := event
@@ -2214,6 +2586,10 @@ func (p *Popup) updateStatusBar() {
 		p.statusBar.AddCssClass("stale")
 	case lastSync.IsZero():
 		text = "Waiting for sync..."
+	case p.conflictCount == 1:
+		text = fmt.Sprintf("%d events • 1 conflict • Synced %s", eventCount, lastSync.Format("3:04 PM"))
+	case p.conflictCount > 1:
+		text = fmt.Sprintf("%d events • %d conflicts • Synced %s", eventCount, p.conflictCount, lastSync.Format("3:04 PM"))
 	default:
 		text = fmt.Sprintf("%d events • Synced %s", eventCount, lastSync.Format("3:04 PM"))
 	}
@@ -2222,6 +2598,14 @@ func (p *Popup) updateStatusBar() {
 	p.statusText.SetTooltipText(text)
 }
 
+// formatOverlaps describes the events an event overlaps.
+func formatOverlaps(events []calendar.Event) string {
+	if len(events) == 1 {
+		return "Overlaps " + events[0].Summary
+	}
+	return fmt.Sprintf("Overlaps %s and %d more", events[0].Summary, len(events)-1)
+}
+
 // updateHiddenIndicator updates the hidden events indicator in the status bar.
 func (p *Popup) updateHiddenIndicator(count int) {
 	if p.hiddenCount == nil {
@@ -2314,6 +2698,9 @@ func (p *Popup) showDetails(event calendar.Event) {
 		dayLabel := p.getDayLabel(event.Start, now)
 		timeStr = fmt.Sprintf("%s • %s – %s", dayLabel, localStart.Format("3:04 PM"), localEnd.Format("3:04 PM"))
 	}
+	if event.IsCancelled() {
+		p.addDetailRow(content, "🚫", "Cancelled")
+	}
 	p.addDetailRow(content, "📅", timeStr)
 
 	// Duration (for non-all-day events)
@@ -2322,7 +2709,7 @@ func (p *Popup) showDetails(event calendar.Event) {
 		p.addDetailRow(content, "⏱", duration)
 	}
 
-	if reminderText := formatReminderDetails(event, p.notificationBefore); reminderText != "" {
+	if reminderText := formatReminderDetails(event, p.notificationBefore(event)); reminderText != "" {
 		p.addDetailRow(content, "🔔", reminderText)
 	}
 
@@ -2346,11 +2733,20 @@ func (p *Popup) showDetails(event calendar.Event) {
 		p.addDetailRow(content, "☎", "Phone conference ID: "+event.Meeting.PhoneConferenceID)
 	}
 
+	if len(event.Tags) > 0 {
+		p.addDetailRow(content, "🏷", strings.Join(event.Tags, ", "))
+	}
+
 	// Organizer
 	if event.Organizer != "" {
 		p.addDetailRow(content, "👤", event.Organizer)
 	}
 
+	// Attendees
+	if len(event.Attendees) > 0 {
+		p.addAttendeesRow(content, &event)
+	}
+
 	// Source
 	if event.Source != "" {
 		p.addDetailRow(content, "📁", event.Source)
@@ -2396,6 +2792,11 @@ func (p *Popup) showDetails(event calendar.Event) {
 		appendOwned(content, &btnBox.Widget, btnBox)
 	}
 
+	// Invitation response buttons
+	if event.CanRespond() {
+		p.addResponseButtons(content, event.Response)
+	}
+
 	// Hide/Unhide button (depends on whether we're viewing a hidden event)
 	actionBtnBox := gtk.NewBox(gtk.OrientationHorizontalValue, 0)
 	actionBtnBox.AddCssClass("details-action-box")
@@ -2433,6 +2834,45 @@ func (p *Popup) showDetails(event calendar.Event) {
 	p.stack.SetVisibleChildName("details")
 }
 
+// responseChoices are the invitation responses offered in the details view.
+var responseChoices = []struct {
+	status calendar.PartStat
+	label  string
+}{
+	{calendar.PartStatAccepted, "Accept"},
+	{calendar.PartStatTentative, "Tentative"},
+	{calendar.PartStatDeclined, "Decline"},
+}
+
+// addResponseButtons adds the current response and Accept/Tentative/Decline
+// buttons for an invitation to the details view.
+func (p *Popup) addResponseButtons(content *gtk.Box, current calendar.PartStat) {
+	section := gtk.NewBox(gtk.OrientationVerticalValue, 8)
+	section.AddCssClass("details-rsvp-box")
+
+	label := gtk.NewLabel("Your response: " + current.Label())
+	label.AddCssClass("details-section-label")
+	label.SetXalign(0)
+	appendOwned(section, &label.Widget, label)
+
+	buttons := gtk.NewBox(gtk.OrientationHorizontalValue, 6)
+	buttons.SetHalign(gtk.AlignCenterValue)
+	for _, choice := range responseChoices {
+		btn := gtk.NewButtonWithLabel(choice.label)
+		btn.AddCssClass("rsvp-btn")
+		if choice.status == current {
+			btn.AddCssClass("selected")
+		}
+		p.widgetResponses[btn.GoPointer()] = choice.status
+		p.detailsLookupPtrs = append(p.detailsLookupPtrs, btn.GoPointer())
+		btn.ConnectClicked(p.getRespondClickCb())
+		appendOwned(buttons, &btn.Widget, btn)
+	}
+	appendOwned(section, &buttons.Widget, buttons)
+
+	appendOwned(content, &section.Widget, section)
+}
+
 // hideDetails returns to the event list view.
 func (p *Popup) hideDetails() {
 	if p.detailsFromHidden {
@@ -2681,6 +3121,41 @@ func (p *Popup) addDetailRow(container *gtk.Box, icon string, text string) {
 	appendOwned(container, &row.Widget, row)
 }
 
+// addAttendeesRow adds the attendee count with a collapsible attendee list to
+// the details panel.
+func (p *Popup) addAttendeesRow(container *gtk.Box, event *calendar.Event) {
+	row := gtk.NewBox(gtk.OrientationHorizontalValue, 8)
+	row.AddCssClass("details-row")
+
+	iconLabel := gtk.NewLabel("👥")
+	iconLabel.AddCssClass("details-icon")
+	iconLabel.SetValign(gtk.AlignStartValue)
+	appendOwned(row, &iconLabel.Widget, iconLabel)
+
+	expander := gtk.NewExpander(event.AttendeeSummary())
+	expander.AddCssClass("details-attendees")
+	expander.SetHexpand(true)
+
+	list := gtk.NewBox(gtk.OrientationVerticalValue, 2)
+	list.AddCssClass("details-attendee-list")
+	for _, a := range event.Attendees {
+		label := gtk.NewLabel(FormatAttendee(a))
+		label.AddCssClass("details-attendee")
+		if a.Status != "" {
+			label.AddCssClass(strings.ToLower(string(a.Status)))
+		}
+		label.SetXalign(0)
+		label.SetWrap(true)
+		label.SetWrapMode(pango.WrapWordCharValue)
+		label.SetSelectable(true)
+		appendOwned(list, &label.Widget, label)
+	}
+	setOwnedChild(expander, &list.Widget, list)
+
+	appendOwned(row, &expander.Widget, expander)
+	appendOwned(container, &row.Widget, row)
+}
+
 // stripHTML removes HTML tags and converts to readable plain text.
 func stripHTML(s string) string {
 	s = htmlLineEndingReplacer.Replace(s)
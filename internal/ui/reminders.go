@@ -10,6 +10,15 @@ import (
 )
 
 func formatReminderDetails(event calendar.Event, notificationBefore []time.Duration) string {
+	if event.IsCancelled() {
+		return "No notifications for cancelled events"
+	}
+	if event.NotifySet {
+		if len(event.NotifyAt) == 0 {
+			return "Notifications disabled for this event by a transform"
+		}
+		return formatReminderSchedule(event.NotifyAt, event.Start, "Using transform reminders")
+	}
 	if notificationBefore != nil {
 		if len(notificationBefore) == 0 {
 			return "Notifications disabled for this event by config override"
@@ -19,16 +28,16 @@ func formatReminderDetails(event calendar.Event, notificationBefore []time.Durat
 		for _, before := range notificationBefore {
 			triggers = append(triggers, event.Start.Add(-before))
 		}
-		return formatReminderSchedule(triggers, event.Start, true)
+		return formatReminderSchedule(triggers, event.Start, "Using config override")
 	}
 
 	if len(event.NotifyAt) == 0 {
 		return "No event-defined reminders"
 	}
-	return formatReminderSchedule(event.NotifyAt, event.Start, false)
+	return formatReminderSchedule(event.NotifyAt, event.Start, "Using event reminders")
 }
 
-func formatReminderSchedule(times []time.Time, eventStart time.Time, overridden bool) string {
+func formatReminderSchedule(times []time.Time, eventStart time.Time, heading string) string {
 	if len(times) == 0 {
 		return ""
 	}
@@ -39,11 +48,7 @@ func formatReminderSchedule(times []time.Time, eventStart time.Time, overridden
 	})
 
 	lines := make([]string, 0, len(sorted)+1)
-	if overridden {
-		lines = append(lines, "Using config override")
-	} else {
-		lines = append(lines, "Using event reminders")
-	}
+	lines = append(lines, heading)
 
 	for _, t := range sorted {
 		lines = append(lines, fmt.Sprintf("%s (%s)", t.Local().Format("Mon Jan 2, 3:04 PM"), formatReminderOffset(eventStart, t)))
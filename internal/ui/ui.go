@@ -24,6 +24,9 @@ type UI interface {
 	// Search displays the UI and focuses search when the backend supports it.
 	Search()
 
+	// ShowDetails displays the details of the event with the given UID.
+	ShowDetails(uid string)
+
 	// SetEvents updates the event list.
 	SetEvents(events []calendar.Event)
 
@@ -51,8 +54,11 @@ type UI interface {
 
 // Action represents a user action from the UI.
 type Action struct {
-	Type ActionType
-	URL  string // For ActionOpenURL
+	Type     ActionType
+	URL      string            // For ActionOpenURL
+	UID      string            // For ActionRespond
+	Response calendar.PartStat // For ActionRespond
+	Text     string            // For ActionQuickAdd
 }
 
 // ActionType identifies the type of action.
@@ -63,13 +69,18 @@ const (
 	ActionOpenURL ActionType = iota
 	// ActionSync indicates the user wants to trigger a sync.
 	ActionSync
+	// ActionRespond indicates the user wants to respond to an invitation.
+	ActionRespond
+	// ActionQuickAdd indicates the user wants to create an event from text.
+	ActionQuickAdd
 )
 
 // Config holds UI configuration.
 type Config struct {
 	TimeRange          time.Duration
-	EventEndGrace      time.Duration // Keep events visible after they end
-	HoverDismissDelay  time.Duration // Delay before dismiss on pointer-leave (0 = never auto-dismiss)
-	NotificationBefore []time.Duration
+	EventEndGrace      time.Duration                        // Keep events visible after they end
+	HoverDismissDelay  time.Duration                        // Delay before dismiss on pointer-leave (0 = never auto-dismiss)
+	NotificationBefore func(calendar.Event) []time.Duration // Configured reminder offsets for an event; nil result = event reminders
 	CSSFile            string
+	DimTentative       bool // Dim events the user tentatively accepted
 }